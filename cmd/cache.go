@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached data",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove cached data",
+	Long: `Remove cached data from the database.
+
+--ai removes the cached article analyses. fetch keys them by a hash of the
+article content, the models used and the version of the prompt, so copies of
+an article under other URLs are not analyzed again; clear them to have every
+article analyzed afresh.
+
+Examples:
+  ai-news cache clear --ai`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		clearAI, _ := cmd.Flags().GetBool("ai")
+		if !clearAI {
+			return errors.New("nothing to clear: pass --ai to remove cached analyses")
+		}
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		db, queries, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		removed, err := queries.DeleteAICache(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("clear AI cache", err)))
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Removed %d cached analyses\n", removed)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.PersistentFlags().StringP("config", "c", "", "Path to config file")
+	cacheClearCmd.Flags().Bool("ai", false, "Remove cached AI analyses")
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeCacheCommand(t *testing.T, cfg *config.Config, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return cfg, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	cacheClearCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+	})
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(cacheCmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"cache"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestCacheClearCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "cache.db")
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+	for _, hash := range []string{"a", "b"} {
+		require.NoError(t, queries.PutAICacheEntry(context.Background(), database.PutAICacheEntryParams{
+			ContentHash:   hash,
+			Model:         "gemini/gemini-2.5-flash",
+			PromptVersion: "v1",
+			Result:        `{"summary":"s"}`,
+		}))
+	}
+	db.Close()
+
+	cfg := &config.Config{DSN: dsn}
+
+	_, err = executeCacheCommand(t, cfg, "clear")
+	assert.ErrorContains(t, err, "pass --ai")
+
+	output, err := executeCacheCommand(t, cfg, "clear", "--ai")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Removed 2 cached analyses")
+
+	output, err = executeCacheCommand(t, cfg, "clear", "--ai")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Removed 0 cached analyses")
+}
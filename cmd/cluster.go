@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/robertguss/rss-agent-cli/internal/story"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+var clusterCmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Group near-duplicate articles into stories",
+	Long: `Group articles that cover the same story, usually from different sources,
+so that the view command shows them as one entry.
+
+New articles are grouped as they are fetched. This command groups articles
+stored before that, and with --rebuild regroups every article, which is needed
+for changes to the stories settings in the config to affect stored articles.
+Two articles published within stories.window of each other belong to the same
+story when their texts are similar enough under stories.min_similarity or
+stories.max_distance.
+
+Examples:
+  ai-news cluster             # Group articles that are not in a story yet
+  ai-news cluster --rebuild   # Regroup all articles`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rebuild, _ := cmd.Flags().GetBool("rebuild")
+		configPath, _ := cmd.Flags().GetString("config")
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		db, queries, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		assign := story.AssignPending
+		if rebuild {
+			assign = story.Rebuild
+		}
+		assigned, err := assign(cmd.Context(), queries, cfg.Stories)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "Grouped %d articles into stories\n", assigned)
+		return nil
+	},
+}
+
+func init() {
+	clusterCmd.Flags().StringP("config", "c", "", "Path to config file")
+	clusterCmd.Flags().Bool("rebuild", false, "Regroup all articles, not only new ones")
+	rootCmd.AddCommand(clusterCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeClusterCommand(t *testing.T, dsn string, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return &config.Config{DSN: dsn}, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(clusterCmd)
+	clusterCmd.Flags().VisitAll(func(f *pflag.Flag) { _ = f.Value.Set(f.DefValue) })
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"cluster"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestClusterCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "cluster.db")
+	published := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+	var ids []int64
+	for i, source := range []string{"alpha", "beta"} {
+		article, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+			Title:         sql.NullString{String: "Anthropic ships a new Claude model for coding agents", Valid: true},
+			Url:           sql.NullString{String: "https://" + source + ".example.com/claude", Valid: true},
+			SourceName:    sql.NullString{String: source, Valid: true},
+			PublishedDate: sql.NullTime{Time: published.Add(time.Duration(i) * time.Hour), Valid: true},
+		})
+		require.NoError(t, err)
+		ids = append(ids, article.ID)
+	}
+	require.NoError(t, db.Close())
+
+	output, err := executeClusterCommand(t, dsn)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Grouped 2 articles into stories")
+
+	output, err = executeClusterCommand(t, dsn)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Grouped 0 articles into stories")
+
+	output, err = executeClusterCommand(t, dsn, "--rebuild")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Grouped 2 articles into stories")
+
+	db, queries, err = database.Open(dsn)
+	require.NoError(t, err)
+	defer db.Close()
+	first, err := queries.GetArticle(context.Background(), ids[0])
+	require.NoError(t, err)
+	second, err := queries.GetArticle(context.Background(), ids[1])
+	require.NoError(t, err)
+	assert.True(t, first.StoryGroupID.Valid)
+	assert.Equal(t, first.StoryGroupID, second.StoryGroupID)
+}
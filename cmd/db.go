@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/urlnorm"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the article database",
+}
+
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply, roll back, or inspect schema migrations",
+	Long: `Manage the versioned schema migrations embedded in the binary.
+
+Every command already applies pending migrations when it opens the database,
+so 'db migrate up' is only needed to upgrade a database ahead of time.
+
+Examples:
+  ai-news db migrate status        # Show applied and pending migrations
+  ai-news db migrate up            # Apply all pending migrations
+  ai-news db migrate down          # Roll back the latest migration
+  ai-news db migrate down -s 2     # Roll back the two latest migrations`,
+}
+
+var dbMigrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openMigrationDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		applied, err := database.MigrateUp(cmd.Context(), db)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		if len(applied) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Database is already up to date")
+			return nil
+		}
+		for _, m := range applied {
+			fmt.Fprintf(cmd.OutOrStdout(), "Applied %04d_%s\n", m.Version, m.Name)
+		}
+		return nil
+	},
+}
+
+var dbMigrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Roll back the most recently applied migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		steps, _ := cmd.Flags().GetInt("steps")
+		if steps <= 0 {
+			return fmt.Errorf("invalid steps %d: must be a positive integer", steps)
+		}
+
+		db, err := openMigrationDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		rolledBack, err := database.MigrateDown(cmd.Context(), db, steps)
+		for _, m := range rolledBack {
+			fmt.Fprintf(cmd.OutOrStdout(), "Rolled back %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		if len(rolledBack) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No applied migrations to roll back")
+		}
+		return nil
+	},
+}
+
+var dbMigrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List migrations and whether they have been applied",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openMigrationDB(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		statuses, err := database.Status(cmd.Context(), db)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state := "pending"
+			appliedAt := "-"
+			if s.Applied {
+				state = "applied"
+				if s.AppliedAt.Valid {
+					appliedAt = s.AppliedAt.Time.Local().Format("2006-01-02 15:04:05")
+				}
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+	},
+}
+
+var dbCanonicalizeCmd = &cobra.Command{
+	Use:   "canonicalize",
+	Short: "Canonicalize stored article URLs and merge duplicates",
+	Long: `Rewrite the URLs of stored articles to their canonical form and merge
+articles that turn out to be the same post.
+
+New articles are canonicalized as they are fetched: tracking parameters such
+as utm_source, fragments and AMP variants are removed, and http/https, "www."
+and trailing-slash differences no longer count as different articles. This
+command applies the same rules, including urls.strip_params from the config,
+to articles stored before that. Of each set of duplicates, the oldest analyzed
+article is kept and marked read if any of the others was.
+
+Examples:
+  ai-news db canonicalize --dry-run   # Report what would change
+  ai-news db canonicalize             # Rewrite URLs and merge duplicates`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		configPath, _ := cmd.Flags().GetString("config")
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		db, _, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		norm := urlnorm.New(cfg.URLs.StripParams...)
+		result, err := database.CanonicalizeURLs(cmd.Context(), db, norm, dryRun)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		if dryRun {
+			fmt.Fprintf(cmd.OutOrStdout(), "Would merge %d duplicate articles and rewrite %d URLs\n", result.Merged, result.Updated)
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Merged %d duplicate articles and rewrote %d URLs\n", result.Merged, result.Updated)
+		return nil
+	},
+}
+
+// openMigrationDB opens the configured database without applying migrations,
+// so the migrate subcommands control the schema version themselves.
+func openMigrationDB(cmd *cobra.Command) (*sql.DB, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+
+	cfg, err := loadCfg(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+	}
+
+	db, _, err := openDB(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+	}
+	return db, nil
+}
+
+func init() {
+	dbCmd.PersistentFlags().StringP("config", "c", "", "Path to config file")
+	dbMigrateDownCmd.Flags().IntP("steps", "s", 1, "Number of migrations to roll back")
+	dbCanonicalizeCmd.Flags().Bool("dry-run", false, "Report what would change without writing")
+
+	dbMigrateCmd.AddCommand(dbMigrateUpCmd, dbMigrateDownCmd, dbMigrateStatusCmd)
+	dbCmd.AddCommand(dbMigrateCmd, dbCanonicalizeCmd)
+	rootCmd.AddCommand(dbCmd)
+}
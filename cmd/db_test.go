@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeDBCommand(t *testing.T, dsn string, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return &config.Config{DSN: dsn}, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(dbCmd)
+	for _, c := range dbCmd.Commands() {
+		c.Flags().VisitAll(func(f *pflag.Flag) { _ = f.Value.Set(f.DefValue) })
+	}
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"db"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestDBMigrateCommand_UpStatusDown(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "migrate.db")
+
+	output, err := executeDBCommand(t, dsn, "migrate", "status")
+	require.NoError(t, err)
+	assert.Contains(t, output, "0001")
+	assert.Contains(t, output, "create_articles")
+	assert.Contains(t, output, "pending")
+	assert.NotContains(t, output, "applied ")
+
+	output, err = executeDBCommand(t, dsn, "migrate", "up")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Applied 0001_create_articles")
+
+	output, err = executeDBCommand(t, dsn, "migrate", "up")
+	require.NoError(t, err)
+	assert.Contains(t, output, "already up to date")
+
+	output, err = executeDBCommand(t, dsn, "migrate", "status")
+	require.NoError(t, err)
+	assert.NotContains(t, output, "pending")
+
+	output, err = executeDBCommand(t, dsn, "migrate", "down")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Rolled back")
+
+	output, err = executeDBCommand(t, dsn, "migrate", "status")
+	require.NoError(t, err)
+	assert.Contains(t, output, "pending")
+}
+
+func TestDBMigrateCommand_InvalidSteps(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "migrate.db")
+
+	_, err := executeDBCommand(t, dsn, "migrate", "down", "--steps", "0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid steps")
+}
+
+func TestDBCanonicalizeCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "canonicalize.db")
+
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+	for _, url := range []string{
+		"https://example.com/post?utm_source=rss",
+		"http://www.example.com/post/",
+		"https://example.com/other",
+	} {
+		_, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+			Url: sql.NullString{String: url, Valid: true},
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	output, err := executeDBCommand(t, dsn, "canonicalize", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Would merge 1 duplicate articles and rewrite 2 URLs")
+
+	output, err = executeDBCommand(t, dsn, "canonicalize")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Merged 1 duplicate articles and rewrote 2 URLs")
+
+	output, err = executeDBCommand(t, dsn, "canonicalize")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Merged 0 duplicate articles and rewrote 0 URLs")
+}
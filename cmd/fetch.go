@@ -2,22 +2,24 @@ package cmd
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"runtime"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
-	"github.com/robertguss/rss-agent-cli/internal/ai/processor/mocks"
 	"github.com/robertguss/rss-agent-cli/internal/config"
 	"github.com/robertguss/rss-agent-cli/internal/database"
 	"github.com/robertguss/rss-agent-cli/internal/fetcher"
+	"github.com/robertguss/rss-agent-cli/internal/jobs"
 	"github.com/robertguss/rss-agent-cli/internal/scraper"
 	"github.com/robertguss/rss-agent-cli/internal/tui"
 	"github.com/robertguss/rss-agent-cli/internal/tui/fetchui"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
 	"github.com/robertguss/rss-agent-cli/pkg/errs"
 	"github.com/robertguss/rss-agent-cli/pkg/logging"
 	"github.com/spf13/cobra"
-	"github.com/stretchr/testify/mock"
 )
 
 var (
@@ -37,6 +39,8 @@ var fetchCmd = &cobra.Command{
 		plain, _ := cmd.Flags().GetBool("plain")
 		workers, _ := cmd.Flags().GetInt("workers")
 		limit, _ := cmd.Flags().GetInt("limit")
+		force, _ := cmd.Flags().GetBool("force")
+		enqueueOnly, _ := cmd.Flags().GetBool("enqueue-only")
 
 		cfg, err := loadCfg(configPath)
 		if err != nil {
@@ -57,47 +61,132 @@ var fetchCmd = &cobra.Command{
 			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
 		}
 
-		var aiProcessor processor.AIProcessor
-
-		if useMockAI {
-			mockProcessor := new(mocks.AIProcessor)
-			mockProcessor.On("AnalyzeContent", mock.Anything).Return(&processor.AnalysisResult{
-				Summary: "mock summary",
-			}, nil)
-			mockProcessor.On("AnalyzeContentWithRetry", mock.Anything, mock.Anything, mock.Anything).Return(&processor.AnalysisResult{
-				Summary: "mock summary with retry",
-			}, nil)
-			aiProcessor = mockProcessor
-		} else {
-			var err error
-			aiProcessor, err = processor.NewGeminiProcessor(ctx, cfg.AI.GeminiModel)
-			if err != nil {
-				return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("initialize AI processor", err)))
-			}
+		scrapers, err := newScrapers(cfg)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("initialize scraper", err)))
 		}
 
-		opts := fetcher.FetchOptions{Limit: limit}
+		deps, cache, err := newPipeline(ctx, cfg, queries, useMockAI)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		opts := fetcher.FetchOptions{Limit: limit, Force: force}
+		if workers <= 0 {
+			workers = runtime.NumCPU()
+		}
+		run := fetchRun{
+			base:        deps,
+			cache:       cache,
+			scrapers:    scrapers,
+			workers:     workers,
+			enqueueOnly: enqueueOnly,
+			opts:        opts,
+		}
 
 		if !plain && tui.ShouldUseTUI() {
-			return runInteractiveFetch(ctx, cfg, queries, aiProcessor, workers, opts)
+			return runInteractiveFetch(ctx, run)
 		}
 
-		return runPlainFetch(ctx, cmd, cfg, queries, aiProcessor, opts)
+		return runPlainFetch(ctx, cmd, run)
 	},
 }
 
-func runInteractiveFetch(ctx context.Context, cfg *config.Config, queries *database.Queries, aiProcessor processor.AIProcessor, workers int, opts fetcher.FetchOptions) error {
-	if workers <= 0 {
-		workers = runtime.NumCPU()
+// newScrapers creates the scrapers used by the enabled sources, keyed by
+// their config name, so that a misspelled scraper fails the fetch up front.
+func newScrapers(cfg *config.Config) (map[string]scraper.Scraper, error) {
+	scrapers := make(map[string]scraper.Scraper)
+	for _, source := range cfg.EnabledSources() {
+		name := cfg.ScraperFor(source)
+		if _, ok := scrapers[name]; ok {
+			continue
+		}
+		s, err := scraper.New(name)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+		scrapers[name] = s
+	}
+	return scrapers, nil
+}
+
+// newPrompts loads the prompt templates of the enabled sources, so that a
+// broken template fails the fetch up front.
+func newPrompts(cfg *config.Config) (*processor.Prompts, error) {
+	prompts := processor.NewPrompts()
+	for _, source := range cfg.EnabledSources() {
+		if _, err := prompts.For(cfg, source); err != nil {
+			return nil, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+	}
+	return prompts, nil
+}
+
+// newPipeline sets up what analyzing articles takes: the AI processor cfg
+// configures, or the mock one, answering from the analysis cache; the
+// prompts of the enabled sources; the usage tracker and the job queue. The
+// cache is also returned for its statistics.
+func newPipeline(ctx context.Context, cfg *config.Config, queries *database.Queries, useMockAI bool) (fetcher.PipelineDeps, *processor.CachingProcessor, error) {
+	if useMockAI {
+		cfg.AI.Providers = []config.ProviderConfig{{Name: "mock", Type: "mock"}}
+		cfg.AI.Routes = nil
+	}
+
+	registry, err := processor.NewRegistry(cfg.AI)
+	if err != nil {
+		return fetcher.PipelineDeps{}, nil, errs.Wrap("initialize AI processor", err)
+	}
+	aiProcessor, err := registry.Processor(ctx)
+	if err != nil {
+		return fetcher.PipelineDeps{}, nil, errs.Wrap("initialize AI processor", err)
+	}
+	cache := processor.NewCachingProcessor(aiProcessor, queries, registry.Models())
+
+	prompts, err := newPrompts(cfg)
+	if err != nil {
+		return fetcher.PipelineDeps{}, nil, errs.Wrap("load prompt", err)
 	}
 
-	sourceNames := make([]string, len(cfg.Sources))
-	for i, source := range cfg.Sources {
+	tracker, err := usage.NewTracker(ctx, queries, cfg.Usage)
+	if err != nil {
+		return fetcher.PipelineDeps{}, nil, err
+	}
+
+	return fetcher.PipelineDeps{
+		AI:      cache,
+		Queries: queries,
+		Config:  cfg,
+		Prompts: prompts,
+		Usage:   tracker,
+		Jobs:    jobs.NewQueue(queries),
+	}, cache, nil
+}
+
+// fetchRun is what a fetch runs with. The sources are fetched with base,
+// whose job queue receives the scraping and analysis of new articles; unless
+// enqueueOnly is set, workers goroutines then run the queued jobs with
+// scrapers.
+type fetchRun struct {
+	base        fetcher.PipelineDeps
+	cache       *processor.CachingProcessor
+	scrapers    map[string]scraper.Scraper
+	workers     int
+	enqueueOnly bool
+	opts        fetcher.FetchOptions
+}
+
+// runInteractiveFetch fetches the enabled sources concurrently and then
+// runs the queued jobs, showing their progress in the fetch TUI.
+func runInteractiveFetch(ctx context.Context, run fetchRun) error {
+	cfg := run.base.Config
+
+	sources := cfg.EnabledSources()
+	sourceNames := make([]string, len(sources))
+	for i, source := range sources {
 		sourceNames[i] = source.Name
 	}
 
 	model := fetchui.New(sourceNames)
-	model.SetWorkerCount(workers)
+	model.SetWorkerCount(run.workers)
 
 	program := tea.NewProgram(model, tea.WithAltScreen())
 
@@ -115,17 +204,11 @@ func runInteractiveFetch(ctx context.Context, cfg *config.Config, queries *datab
 		var totalAdded int
 		var errors []error
 		successCount := 0
+		unchangedCount := 0
 		errorCount := 0
 
 		processSource := func(ctx context.Context, source fetcher.Source, opts fetcher.FetchOptions, progressCh chan<- tui.DetailedProgressMsg) (int, error) {
-			deps := fetcher.PipelineDeps{
-				Scraper: scraper.NewJinaScraper(),
-				AI:      aiProcessor,
-				Queries: queries,
-				Config:  cfg,
-			}
-
-			return fetcher.FetchAndStoreWithAIProgress(ctx, deps, source, opts, progressCh)
+			return fetcher.FetchAndStoreWithAIProgress(ctx, run.base, source, opts, progressCh)
 		}
 
 		detailedProgress := make(chan tui.DetailedProgressMsg, 100)
@@ -143,9 +226,29 @@ func runInteractiveFetch(ctx context.Context, cfg *config.Config, queries *datab
 			}
 		}()
 
-		results := fetcher.ProcessSourcesConcurrently(ctx, cfg.Sources, workers, processSource, opts, detailedProgress)
+		results := fetcher.ProcessSourcesConcurrently(ctx, sources, run.workers, processSource, run.opts, detailedProgress)
 		close(detailedProgress)
 
+		var tally jobTally
+		if !run.enqueueOnly {
+			worker, runner := newJobWorker(run.base, run.scrapers)
+			runner.OnStart = func(kind string, article database.Article) {
+				phase := tui.PhaseAI
+				if kind == fetcher.JobScrape {
+					phase = tui.PhaseScrape
+				}
+				program.Send(tui.ArticleProgressMsg{
+					Source:       article.SourceName.String,
+					Phase:        phase,
+					ArticleTitle: article.Title.String,
+				})
+			}
+			if err := worker.Run(ctx, run.workers, 0, tally.add); err != nil {
+				errors = append(errors, errs.Wrap("run jobs", err))
+			}
+			errors = append(errors, tally.failures...)
+		}
+
 		for _, result := range results {
 			if result.Error != nil {
 				errorCount++
@@ -154,6 +257,12 @@ func runInteractiveFetch(ctx context.Context, cfg *config.Config, queries *datab
 					Source: result.Source.Name,
 					Error:  result.Error,
 				})
+			} else if result.Unchanged {
+				unchangedCount++
+				program.Send(tui.CompletedMsg{
+					Source:    result.Source.Name,
+					Unchanged: true,
+				})
 			} else {
 				successCount++
 				totalAdded += result.Added
@@ -164,12 +273,24 @@ func runInteractiveFetch(ctx context.Context, cfg *config.Config, queries *datab
 			}
 		}
 
+		var summary []string
+		if run.enqueueOnly {
+			summary = append(summary, enqueuedNote)
+		} else if tally.ran > 0 {
+			summary = append(summary, tally.String())
+		}
+		if line := formatAIUsage(ctx, run.base.Usage, run.cache); line != "" {
+			summary = append(summary, line)
+		}
+
 		program.Send(tui.FinalSummaryMsg{
-			TotalAdded:   totalAdded,
-			TotalSources: len(cfg.Sources),
-			SuccessCount: successCount,
-			ErrorCount:   errorCount,
-			Errors:       errors,
+			TotalAdded:     totalAdded,
+			TotalSources:   len(sources),
+			SuccessCount:   successCount,
+			UnchangedCount: unchangedCount,
+			ErrorCount:     errorCount,
+			Errors:         errors,
+			AIUsage:        strings.Join(summary, "\n"),
 		})
 	}()
 
@@ -177,19 +298,24 @@ func runInteractiveFetch(ctx context.Context, cfg *config.Config, queries *datab
 	return err
 }
 
-func runPlainFetch(ctx context.Context, cmd *cobra.Command, cfg *config.Config, queries *database.Queries, aiProcessor processor.AIProcessor, opts fetcher.FetchOptions) error {
+// enqueuedNote tells how to get the work of a fetch with --enqueue-only done.
+const enqueuedNote = "Scraping and analysis were queued: run `ai-news worker` to process them"
+
+// runPlainFetch fetches the enabled sources one after another, runs the
+// queued jobs and prints a summary.
+func runPlainFetch(ctx context.Context, cmd *cobra.Command, run fetchRun) error {
+	cfg := run.base.Config
 	var added int
+	var unchanged []string
 	var errors []error
 
-	for _, source := range cfg.Sources {
-		deps := fetcher.PipelineDeps{
-			Scraper: scraper.NewJinaScraper(),
-			AI:      aiProcessor,
-			Queries: queries,
-			Config:  cfg,
+	sources := cfg.EnabledSources()
+	for _, source := range sources {
+		n, err := fetcher.FetchAndStoreWithAI(ctx, run.base, source, run.opts)
+		if stderrors.Is(err, fetcher.ErrFeedUnchanged) {
+			unchanged = append(unchanged, source.Name)
+			continue
 		}
-
-		n, err := fetcher.FetchAndStoreWithAI(ctx, deps, source, opts)
 		if err != nil {
 			userFriendlyErr := errs.GetUserFriendlyMessage(err)
 			errors = append(errors, fmt.Errorf("source %s: %s", source.Name, userFriendlyErr))
@@ -198,24 +324,66 @@ func runPlainFetch(ctx context.Context, cmd *cobra.Command, cfg *config.Config,
 		added += n
 	}
 
+	var tally jobTally
+	if !run.enqueueOnly {
+		worker, _ := newJobWorker(run.base, run.scrapers)
+		if err := worker.Run(ctx, run.workers, 0, tally.add); err != nil {
+			errors = append(errors, fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("run jobs", err))))
+		}
+	}
+
+	if len(unchanged) > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Unchanged since last fetch: %s\n", strings.Join(unchanged, ", "))
+	}
+
 	if len(errors) > 0 {
-		fmt.Fprintf(cmd.OutOrStdout(), "Added %d new articles from %d sources\n", added, len(cfg.Sources))
+		fmt.Fprintf(cmd.OutOrStdout(), "Added %d new articles from %d sources\n", added, len(sources))
 		fmt.Fprintf(cmd.OutOrStdout(), "%d errors occurred:\n", len(errors))
 		for _, err := range errors {
 			fmt.Fprintf(cmd.OutOrStdout(), "  - %v\n", err)
 		}
 	} else {
-		fmt.Fprintf(cmd.OutOrStdout(), "Added %d new articles from %d sources\n", added, len(cfg.Sources))
+		fmt.Fprintf(cmd.OutOrStdout(), "Added %d new articles from %d sources\n", added, len(sources))
+	}
+
+	if run.enqueueOnly {
+		fmt.Fprintln(cmd.OutOrStdout(), enqueuedNote)
+	} else if tally.ran > 0 {
+		printJobTally(cmd, &tally)
+	}
+
+	if line := formatAIUsage(ctx, run.base.Usage, run.cache); line != "" {
+		fmt.Fprintln(cmd.OutOrStdout(), line)
 	}
 
 	return nil
 }
 
+// formatAIUsage describes the AI calls a fetch made, how many analyses
+// came from the cache and whether the monthly budget stopped it from
+// analyzing articles, or returns "" if there is nothing to say.
+func formatAIUsage(ctx context.Context, tracker *usage.Tracker, cache *processor.CachingProcessor) string {
+	var lines []string
+	if run := tracker.Run(); run.Calls > 0 {
+		lines = append(lines, fmt.Sprintf("AI usage: %d calls, %d prompt and %d output tokens, $%.4f",
+			run.Calls, run.PromptTokens, run.OutputTokens, run.Cost))
+	}
+	if hits, misses := cache.Stats(); hits+misses > 0 {
+		lines = append(lines, fmt.Sprintf("AI cache: %d hits, %d misses", hits, misses))
+	}
+	if tracker.OverBudget(ctx) {
+		lines = append(lines, "Monthly AI budget reached: new articles were left pending")
+	}
+	return strings.Join(lines, "\n")
+}
+
 func init() {
 	fetchCmd.Flags().StringP("config", "c", "", "Path to config file")
 	fetchCmd.Flags().Bool("use-mock-ai", false, "Use mock AI processor for testing")
 	fetchCmd.Flags().Bool("plain", false, "Use plain text output instead of interactive TUI")
 	fetchCmd.Flags().IntP("workers", "w", 0, "Number of worker goroutines (0 = auto-detect based on CPU cores)")
 	fetchCmd.Flags().IntP("limit", "n", 5, "Maximum number of articles to fetch per source (0 = unlimited)")
+	fetchCmd.Flags().Bool("force", false, "Refetch unchanged feeds and retry quarantined sources")
+	fetchCmd.Flags().Bool("enqueue-only", false, "Queue scraping and analysis for the worker command instead of running them")
 	rootCmd.AddCommand(fetchCmd)
 }
@@ -87,6 +87,7 @@ sources:
 	output = buf.String()
 	assert.Contains(t, output, "Added 0 new articles")
 	assert.Contains(t, output, "from 1 sources")
+	assert.Contains(t, output, "Unchanged since last fetch: Test Source")
 }
 
 func TestFetchCmd_Integration_NetworkError(t *testing.T) {
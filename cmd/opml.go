@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/opml"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+var opmlCmd = &cobra.Command{
+	Use:   "opml",
+	Short: "Import or export sources as OPML",
+	Long: `Move subscriptions between this tool and other feed readers.
+
+Examples:
+  ai-news opml import subscriptions.opml
+  ai-news opml export > sources.opml
+  ai-news opml export -o sources.opml`,
+}
+
+var opmlImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Add the feeds in an OPML file to the config",
+	Long: `Add every feed in an OPML file to the config file's sources.
+
+Folder outlines (or a feed's category attribute) become the source group.
+Feeds whose URL is already configured are skipped, so importing the same
+file twice is harmless.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("open opml", err)))
+		}
+		defer f.Close()
+
+		doc, err := opml.Parse(f)
+		if err != nil {
+			return fmt.Errorf("invalid OPML file %s: %w", args[0], err)
+		}
+
+		file, err := config.OpenSourcesFile(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		added, skipped, err := importSources(file, doc.Sources())
+		if err != nil {
+			return err
+		}
+
+		if added > 0 {
+			if err := file.Save(); err != nil {
+				return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("save config", err)))
+			}
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Imported %d sources into %s (%d already configured)\n", added, file.Path(), skipped)
+		return nil
+	},
+}
+
+var opmlExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the configured sources as OPML 2.0",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		output, _ := cmd.Flags().GetString("output")
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		var w io.Writer = cmd.OutOrStdout()
+		if output != "" {
+			f, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("create opml", err)))
+			}
+			defer f.Close()
+			w = f
+		}
+
+		doc := opml.FromSources("AI News sources", cfg.Sources, time.Now())
+		if err := doc.Write(w); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		if output != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "Exported %d sources to %s\n", len(cfg.Sources), output)
+		}
+		return nil
+	},
+}
+
+// importSources adds sources whose URL is not configured yet. A source whose
+// name is taken by a different feed gets a numbered name instead.
+func importSources(file *config.SourcesFile, sources []config.Source) (added, skipped int, err error) {
+	existing, err := file.Sources()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	urls := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, source := range existing {
+		urls[source.URL] = true
+		names[strings.ToLower(source.Name)] = true
+	}
+
+	for _, source := range sources {
+		if source.URL == "" || urls[source.URL] {
+			skipped++
+			continue
+		}
+
+		name := source.Name
+		for n := 2; names[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s (%d)", source.Name, n)
+		}
+		source.Name = name
+
+		if err := file.Add(source); err != nil {
+			return added, skipped, err
+		}
+		urls[source.URL] = true
+		names[strings.ToLower(source.Name)] = true
+		added++
+	}
+	return added, skipped, nil
+}
+
+func init() {
+	opmlCmd.PersistentFlags().StringP("config", "c", "", "Path to config file")
+	opmlExportCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+
+	opmlCmd.AddCommand(opmlImportCmd, opmlExportCmd)
+	rootCmd.AddCommand(opmlCmd)
+}
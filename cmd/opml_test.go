@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeOPMLCommand(t *testing.T, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = config.LoadFromPath
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	for _, c := range append(opmlCmd.Commands(), opmlCmd) {
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			_ = f.Value.Set(f.DefValue)
+		})
+	}
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(opmlCmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"opml"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestOPMLImport_DedupesByURL(t *testing.T) {
+	configPath := writeSourcesConfigFile(t)
+	opmlPath := filepath.Join(t.TempDir(), "subs.opml")
+	require.NoError(t, os.WriteFile(opmlPath, []byte(`<?xml version="1.0"?>
+<opml version="2.0">
+  <head><title>Subs</title></head>
+  <body>
+    <outline text="Tech">
+      <outline text="Existing Blog" type="rss" xmlUrl="https://example.com/existing.xml"/>
+      <outline text="Existing Blog" type="rss" xmlUrl="https://example.com/renamed.xml"/>
+      <outline text="Fresh Blog" type="rss" xmlUrl="https://example.com/fresh.xml"/>
+      <outline text="Fresh Again" type="rss" xmlUrl="https://example.com/fresh.xml"/>
+    </outline>
+  </body>
+</opml>`), 0644))
+
+	output, err := executeOPMLCommand(t, "import", opmlPath, "--config", configPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Imported 2 sources")
+	assert.Contains(t, output, "2 already configured")
+
+	output, err = executeOPMLCommand(t, "import", opmlPath, "--config", configPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Imported 0 sources")
+
+	cfg, err := config.LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "news.db", cfg.DSN)
+	require.Len(t, cfg.Sources, 3)
+	assert.Equal(t, "Existing Blog (2)", cfg.Sources[1].Name)
+	assert.Equal(t, "https://example.com/renamed.xml", cfg.Sources[1].URL)
+	assert.Equal(t, "Tech", cfg.Sources[1].Group)
+	assert.Equal(t, "Fresh Blog", cfg.Sources[2].Name)
+	assert.Equal(t, 3, cfg.Sources[2].Priority)
+}
+
+func TestOPMLExportImport_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	sourceConfig := filepath.Join(dir, "source.yaml")
+	require.NoError(t, os.WriteFile(sourceConfig, []byte(`sources:
+  - name: "OpenAI Blog"
+    url: "https://openai.com/blog/rss.xml"
+    type: "rss"
+    priority: 1
+    group: "AI"
+  - name: "Ars Technica"
+    url: "http://feeds.arstechnica.com/arstechnica/technology-lab"
+    type: "rss"
+    priority: 2
+    enabled: false
+  - name: "Lab News"
+    url: "https://example.com/news"
+    type: "html"
+    priority: 3
+    html:
+      item: "article.post"
+      link: "h2 a"
+      date: "time"
+      date_layouts: ["Jan 2, 2006"]
+    scraper: "readability"
+    prompt: "prompts/research.tmpl"
+`), 0644))
+	targetConfig := filepath.Join(dir, "target.yaml")
+	require.NoError(t, os.WriteFile(targetConfig, []byte("dsn: target.db\n"), 0644))
+	opmlPath := filepath.Join(dir, "export.opml")
+
+	output, err := executeOPMLCommand(t, "export", "-o", opmlPath, "--config", sourceConfig)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Exported 3 sources")
+
+	_, err = executeOPMLCommand(t, "import", opmlPath, "--config", targetConfig)
+	require.NoError(t, err)
+
+	want, err := config.LoadFromPath(sourceConfig)
+	require.NoError(t, err)
+	got, err := config.LoadFromPath(targetConfig)
+	require.NoError(t, err)
+	assert.Equal(t, want.Sources, got.Sources)
+	assert.Equal(t, "target.db", got.DSN)
+}
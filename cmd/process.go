@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/fetcher"
+	"github.com/robertguss/rss-agent-cli/internal/scraper"
+	"github.com/robertguss/rss-agent-cli/internal/tui"
+	"github.com/robertguss/rss-agent-cli/internal/tui/fetchui"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var processCmd = &cobra.Command{
+	Use:   "process",
+	Short: "Analyze stored articles that are pending or were never analyzed",
+	Long: `Work through the articles awaiting AI analysis: those a fetch left
+pending because scraping or the AI call failed or the monthly budget ran out,
+and those stored without analysis. Articles without content are scraped
+first.
+
+Each failure is recorded with the article. An article that has failed
+process.max_attempts times (default 5) is marked failed and skipped from
+then on; a failed article under the limit, for example after the limit was
+raised, is retried. --id processes an article whatever its state.
+
+Examples:
+  ai-news process
+  ai-news process --source "Hacker News" --workers 8
+  ai-news process --id 42 --plain`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		configPath, _ := cmd.Flags().GetString("config")
+		useMockAI, _ := cmd.Flags().GetBool("use-mock-ai")
+		plain, _ := cmd.Flags().GetBool("plain")
+		workers, _ := cmd.Flags().GetInt("workers")
+		articleID, _ := cmd.Flags().GetInt64("id")
+		sourceName, _ := cmd.Flags().GetString("source")
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		maxAttempts := cfg.Process.MaxAttempts
+		if cmd.Flags().Changed("max-attempts") {
+			maxAttempts, _ = cmd.Flags().GetInt("max-attempts")
+		}
+
+		if err := logging.Init(cfg.LogFile); err != nil {
+			return fmt.Errorf("failed to initialize logging: %w", err)
+		}
+
+		db, queries, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		articles, err := fetcher.Backlog(ctx, queries, fetcher.BacklogOptions{
+			ArticleID:   articleID,
+			Source:      sourceName,
+			MaxAttempts: maxAttempts,
+		})
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		if len(articles) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No articles awaiting analysis.")
+			return nil
+		}
+
+		scrapers, err := backlogScrapers(cfg, articles)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("initialize scraper", err)))
+		}
+
+		if useMockAI {
+			cfg.AI.Providers = []config.ProviderConfig{{Name: "mock", Type: "mock"}}
+			cfg.AI.Routes = nil
+		}
+
+		registry, err := processor.NewRegistry(cfg.AI)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("initialize AI processor", err)))
+		}
+		aiProcessor, err := registry.Processor(ctx)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("initialize AI processor", err)))
+		}
+		cache := processor.NewCachingProcessor(aiProcessor, queries, registry.Models())
+
+		tracker, err := usage.NewTracker(ctx, queries, cfg.Usage)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		base := fetcher.PipelineDeps{
+			AI:      cache,
+			Queries: queries,
+			Config:  cfg,
+			Prompts: processor.NewPrompts(),
+			Usage:   tracker,
+		}
+		process := func(ctx context.Context, article database.Article) fetcher.ProcessResult {
+			source := cfg.SourceNamed(article.SourceName.String)
+			deps := base
+			deps.Scraper = scrapers[cfg.ScraperFor(source)]
+			return fetcher.ProcessArticle(ctx, deps, source, article, maxAttempts)
+		}
+
+		if workers <= 0 {
+			workers = runtime.NumCPU()
+		}
+
+		if !plain && tui.ShouldUseTUI() {
+			return runInteractiveProcess(ctx, articles, workers, process, tracker, cache)
+		}
+
+		results := fetcher.ProcessConcurrently(ctx, articles, workers, process, nil)
+		printProcessResults(ctx, cmd, results, tracker, cache)
+		return nil
+	},
+}
+
+// backlogScrapers creates the scrapers configured for the sources of
+// articles, keyed by their config name.
+func backlogScrapers(cfg *config.Config, articles []database.Article) (map[string]scraper.Scraper, error) {
+	scrapers := make(map[string]scraper.Scraper)
+	for _, article := range articles {
+		source := cfg.SourceNamed(article.SourceName.String)
+		name := cfg.ScraperFor(source)
+		if _, ok := scrapers[name]; ok {
+			continue
+		}
+		s, err := scraper.New(name)
+		if err != nil {
+			return nil, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+		scrapers[name] = s
+	}
+	return scrapers, nil
+}
+
+// printProcessResults summarizes a plain run of the process command.
+func printProcessResults(ctx context.Context, cmd *cobra.Command, results []fetcher.ProcessResult, tracker *usage.Tracker, cache *processor.CachingProcessor) {
+	out := cmd.OutOrStdout()
+	analyzed, skipped := 0, 0
+	var failed []fetcher.ProcessResult
+	for _, result := range results {
+		switch {
+		case result.Analyzed:
+			analyzed++
+		case stderrors.Is(result.Error, usage.ErrBudgetExceeded):
+			skipped++
+		default:
+			failed = append(failed, result)
+		}
+	}
+
+	fmt.Fprintf(out, "Analyzed %d of %d articles\n", analyzed, len(results))
+	if len(failed) > 0 {
+		fmt.Fprintf(out, "%d articles failed:\n", len(failed))
+		for _, result := range failed {
+			note := ""
+			if result.GaveUp {
+				note = " (giving up)"
+			}
+			fmt.Fprintf(out, "  - #%d %s: %s%s\n", result.Article.ID, result.Article.Title.String,
+				errs.GetUserFriendlyMessage(result.Error), note)
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(out, "%d articles left pending\n", skipped)
+	}
+	if line := formatAIUsage(ctx, tracker, cache); line != "" {
+		fmt.Fprintln(out, line)
+	}
+}
+
+// runInteractiveProcess processes articles concurrently, showing progress
+// per source in the fetch TUI.
+func runInteractiveProcess(ctx context.Context, articles []database.Article, workers int, process func(context.Context, database.Article) fetcher.ProcessResult, tracker *usage.Tracker, cache *processor.CachingProcessor) error {
+	var sourceNames []string
+	totals := make(map[string]int)
+	for _, article := range articles {
+		name := article.SourceName.String
+		if totals[name] == 0 {
+			sourceNames = append(sourceNames, name)
+		}
+		totals[name]++
+	}
+
+	model := fetchui.New(sourceNames)
+	model.SetWorkerCount(workers)
+	model.SetLabels(fetchui.Labels{
+		Title:     "Processing Articles",
+		DoneTitle: "Processing Complete",
+		Added:     "analyzed",
+	})
+
+	program := tea.NewProgram(model, tea.WithAltScreen())
+
+	go func() {
+		var mu sync.Mutex
+		done := make(map[string]int)
+		analyzed := make(map[string]int)
+		failures := make(map[string][]error)
+
+		start := func(ctx context.Context, article database.Article) fetcher.ProcessResult {
+			name := article.SourceName.String
+			mu.Lock()
+			current := done[name]
+			mu.Unlock()
+			program.Send(tui.ArticleProgressMsg{
+				Source:       name,
+				Phase:        tui.PhaseAI,
+				Current:      current,
+				Total:        totals[name],
+				ArticleTitle: article.Title.String,
+			})
+			return process(ctx, article)
+		}
+
+		finish := func(result fetcher.ProcessResult) {
+			name := result.Article.SourceName.String
+			mu.Lock()
+			defer mu.Unlock()
+
+			done[name]++
+			if result.Analyzed {
+				analyzed[name]++
+				program.Send(tui.ArticleAddedMsg{Source: name, Count: 1})
+			} else if result.Error != nil && !stderrors.Is(result.Error, usage.ErrBudgetExceeded) {
+				failures[name] = append(failures[name], fmt.Errorf("#%d %s: %w", result.Article.ID, result.Article.Title.String, result.Error))
+			}
+			program.Send(tui.ArticleProgressMsg{
+				Source:  name,
+				Phase:   tui.PhaseAI,
+				Current: done[name],
+				Total:   totals[name],
+			})
+			if done[name] == totals[name] {
+				program.Send(tui.CompletedMsg{
+					Source: name,
+					Added:  analyzed[name],
+					Error:  stderrors.Join(failures[name]...),
+				})
+			}
+		}
+
+		fetcher.ProcessConcurrently(ctx, articles, workers, start, finish)
+
+		summary := tui.FinalSummaryMsg{TotalSources: len(sourceNames), AIUsage: formatAIUsage(ctx, tracker, cache)}
+		for _, name := range sourceNames {
+			summary.TotalAdded += analyzed[name]
+			if len(failures[name]) > 0 {
+				summary.ErrorCount++
+				summary.Errors = append(summary.Errors, fmt.Errorf("source %s: %w", name, stderrors.Join(failures[name]...)))
+			} else {
+				summary.SuccessCount++
+			}
+		}
+		program.Send(summary)
+	}()
+
+	_, err := program.Run()
+	return err
+}
+
+func init() {
+	processCmd.Flags().StringP("config", "c", "", "Path to config file")
+	processCmd.Flags().Bool("use-mock-ai", false, "Use mock AI processor for testing")
+	processCmd.Flags().Bool("plain", false, "Use plain text output instead of interactive TUI")
+	processCmd.Flags().IntP("workers", "w", 0, "Number of worker goroutines (0 = auto-detect based on CPU cores)")
+	processCmd.Flags().Int64("id", 0, "Process only the article with this ID, whatever its state")
+	processCmd.Flags().String("source", "", "Process only the articles of this source")
+	processCmd.Flags().Int("max-attempts", 0, "Failures after which an article is given up (default from config, -1 = no limit)")
+	rootCmd.AddCommand(processCmd)
+}
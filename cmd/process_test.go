@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeProcessCommand(t *testing.T, cfg *config.Config, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return cfg, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	processCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(processCmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"process", "--plain", "--use-mock-ai"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestProcessCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "process.db")
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+
+	ctx := context.Background()
+	var ids []int64
+	for _, params := range []database.CreateArticleParams{
+		{Url: sql.NullString{String: "https://a.example/1", Valid: true}, SourceName: sql.NullString{String: "A", Valid: true}, AnalysisStatus: sql.NullString{String: "pending", Valid: true}},
+		{Url: sql.NullString{String: "https://b.example/1", Valid: true}, SourceName: sql.NullString{String: "B", Valid: true}, AnalysisStatus: sql.NullString{String: "unprocessed", Valid: true}},
+		{Url: sql.NullString{String: "https://b.example/2", Valid: true}, SourceName: sql.NullString{String: "B", Valid: true}, AnalysisStatus: sql.NullString{String: "unprocessed", Valid: true}},
+	} {
+		params.Title = sql.NullString{String: "Article", Valid: true}
+		params.Content = sql.NullString{String: "Some article content", Valid: true}
+		params.PublishedDate = sql.NullTime{Time: time.Now(), Valid: true}
+		article, err := queries.CreateArticle(ctx, params)
+		require.NoError(t, err)
+		ids = append(ids, article.ID)
+	}
+	db.Close()
+
+	cfg := &config.Config{DSN: dsn, Process: config.ProcessConfig{MaxAttempts: 5}}
+
+	output, err := executeProcessCommand(t, cfg, "--source", "B")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Analyzed 2 of 2 articles")
+
+	output, err = executeProcessCommand(t, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Analyzed 1 of 1 articles")
+
+	output, err = executeProcessCommand(t, cfg)
+	require.NoError(t, err)
+	assert.Contains(t, output, "No articles awaiting analysis.")
+
+	output, err = executeProcessCommand(t, cfg, "--id", strconv.FormatInt(ids[1], 10))
+	require.NoError(t, err)
+	assert.Contains(t, output, "Analyzed 1 of 1 articles")
+
+	db, queries, err = database.Open(dsn)
+	require.NoError(t, err)
+	defer db.Close()
+	for _, id := range ids {
+		article, err := queries.GetArticle(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", article.AnalysisStatus.String)
+		assert.True(t, article.Summary.Valid)
+	}
+}
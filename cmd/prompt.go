@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Inspect the prompts used to analyze articles",
+}
+
+var promptRenderCmd = &cobra.Command{
+	Use:   "render <article-id>",
+	Short: "Print the analysis prompt for a stored article",
+	Long: `Print the prompt that analyzing a stored article would send to the AI
+provider, rendered from the template configured for the article's source.
+
+Articles are analyzed with a built-in prompt unless ai.prompt, or prompt on
+a source, names a Go text/template file. Templates are executed with .Title,
+.URL, .Source, .Content and .ContentTypes, and can use join; articles too
+long for one prompt are analyzed in chunks, with .Part and .Parts set, but
+are rendered here whole. The name and version of the template are printed
+to stderr.
+
+Examples:
+  ai-news prompt render 42
+  ai-news prompt render 42 --config custom.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid article ID %q: must be an integer", args[0])
+		}
+		configPath, _ := cmd.Flags().GetString("config")
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		db, queries, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		article, err := queries.GetArticle(cmd.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("article %d not found", id)
+		} else if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("get article", err)))
+		}
+		if article.Content.String == "" {
+			return fmt.Errorf("article %d has no content to analyze", id)
+		}
+
+		source := cfg.SourceNamed(article.SourceName.String)
+		prompt, err := processor.NewPrompts().For(cfg, source)
+		if err != nil {
+			return err
+		}
+		text, err := prompt.Render(processor.PromptData{
+			Title:   article.Title.String,
+			URL:     article.Url.String,
+			Source:  article.SourceName.String,
+			Content: article.Content.String,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.ErrOrStderr(), "Prompt %s (version %s)\n", prompt.Name, prompt.Version)
+		fmt.Fprint(cmd.OutOrStdout(), text)
+		return nil
+	},
+}
+
+func init() {
+	promptCmd.PersistentFlags().StringP("config", "c", "", "Path to config file")
+	promptCmd.AddCommand(promptRenderCmd)
+	rootCmd.AddCommand(promptCmd)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executePromptCommand(t *testing.T, cfg *config.Config, args ...string) (string, string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return cfg, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(promptCmd)
+
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(append([]string{"prompt"}, args...))
+
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestPromptRenderCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "prompt.db")
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+	article, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+		Title:      sql.NullString{String: "Attention Is All You Need", Valid: true},
+		Url:        sql.NullString{String: "https://arxiv.org/abs/1706.03762", Valid: true},
+		SourceName: sql.NullString{String: "arXiv", Valid: true},
+		Content:    sql.NullString{String: "The dominant sequence transduction models...", Valid: true},
+	})
+	require.NoError(t, err)
+	empty, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+		Title: sql.NullString{String: "No content", Valid: true},
+		Url:   sql.NullString{String: "https://example.com/empty", Valid: true},
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	id := func(a database.Article) string { return strconv.FormatInt(a.ID, 10) }
+
+	stdout, stderr, err := executePromptCommand(t, &config.Config{DSN: dsn}, "render", id(article))
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Analyze this AI news article")
+	assert.Contains(t, stdout, "Article content:\nThe dominant sequence transduction models...")
+	assert.Contains(t, stderr, "Prompt analysis (version ")
+
+	promptPath := filepath.Join(t.TempDir(), "papers.tmpl")
+	require.NoError(t, os.WriteFile(promptPath, []byte("{{.Source}}: {{.Title}} <{{.URL}}>"), 0644))
+	cfg := &config.Config{DSN: dsn, Sources: []config.Source{{Name: "arXiv", Prompt: promptPath}}}
+
+	stdout, stderr, err = executePromptCommand(t, cfg, "render", id(article))
+	require.NoError(t, err)
+	assert.Equal(t, "arXiv: Attention Is All You Need <https://arxiv.org/abs/1706.03762>", stdout)
+	assert.Contains(t, stderr, "Prompt papers (version ")
+
+	_, _, err = executePromptCommand(t, cfg, "render", id(empty))
+	assert.ErrorContains(t, err, "has no content to analyze")
+
+	_, _, err = executePromptCommand(t, cfg, "render", "999")
+	assert.ErrorContains(t, err, "article 999 not found")
+
+	_, _, err = executePromptCommand(t, cfg, "render", "abc")
+	assert.ErrorContains(t, err, `invalid article ID "abc"`)
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/semantic"
+	"github.com/robertguss/rss-agent-cli/internal/state"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+// SearchOptions holds the filters for a full-text article search.
+type SearchOptions struct {
+	ViewOptions
+	Limit int
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Full-text or semantic search over stored articles",
+	Long: `Search article titles, summaries and stored content, ranked by relevance.
+
+Every word in the query must match. Words are stemmed, so "run" also finds
+"running"; end a word with * to match it as a prefix. Like 'view', only
+unread articles are searched unless --all is given. The results become the
+current article list for 'read' and 'open'.
+
+With --semantic, articles are instead ranked by how close they are in meaning
+to the query, so they need not share any of its words. This uses the
+embedding model configured under ai.embeddings; articles that have not been
+embedded yet are embedded first, which can take a while the first time.
+
+Examples:
+  ai-news search "diffusion model"
+  ai-news search agent* --source "OpenAI Blog"
+  ai-news search transformers --topic "Research" --all
+  ai-news search --semantic "robots that learn from watching people"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath, _ := cmd.Flags().GetString("db")
+		all, _ := cmd.Flags().GetBool("all")
+		source, _ := cmd.Flags().GetString("source")
+		topic, _ := cmd.Flags().GetString("topic")
+		limit, _ := cmd.Flags().GetInt("limit")
+		semantic, _ := cmd.Flags().GetBool("semantic")
+
+		opts := SearchOptions{
+			ViewOptions: ViewOptions{
+				All:    all,
+				Source: source,
+				Topic:  topic,
+			},
+			Limit: limit,
+		}
+
+		if semantic {
+			configPath, _ := cmd.Flags().GetString("config")
+			return runSemanticSearch(cmd, dbPath, configPath, strings.Join(args, " "), opts)
+		}
+		return runSearch(cmd, dbPath, strings.Join(args, " "), opts)
+	},
+}
+
+// newEmbedder creates the embedder for semantic search; tests replace it.
+var newEmbedder = processor.NewRoutedEmbedder
+
+func runSearch(cmd *cobra.Command, dbPath, query string, opts SearchOptions) error {
+	if strings.TrimSpace(database.MatchQuery(query)) == "" {
+		return fmt.Errorf("invalid search query %q: must contain at least one word", query)
+	}
+
+	db, q, err := databaseOpen(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = database.InitSchema(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	results, err := q.SearchArticles(ctx, database.SearchParams{
+		Query:  query,
+		Source: opts.Source,
+		Topic:  opts.Topic,
+		All:    opts.All,
+		Limit:  int64(opts.Limit),
+	})
+	if err != nil {
+		return err
+	}
+
+	printSearchResults(cmd, results)
+	return nil
+}
+
+// runSemanticSearch ranks articles by the cosine similarity of their
+// embeddings to the embedding of query, applying the same filters as
+// runSearch to the ranked articles.
+func runSemanticSearch(cmd *cobra.Command, dbPath, configPath, query string, opts SearchOptions) error {
+	if strings.TrimSpace(query) == "" {
+		return fmt.Errorf("invalid search query %q: must contain at least one word", query)
+	}
+
+	cfg, err := loadCfg(configPath)
+	if err != nil {
+		return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+	}
+
+	ctx := context.Background()
+	embedder, err := newEmbedder(ctx, cfg.AI)
+	if err != nil {
+		return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("create embedder", err)))
+	}
+	if closer, ok := embedder.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	db, q, err := databaseOpen(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = database.InitSchema(db)
+	if err != nil {
+		return err
+	}
+
+	matches, err := semantic.NewIndex(q, embedder).Search(ctx, query)
+	if err != nil {
+		return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+	}
+
+	var results []database.SearchResult
+	for _, match := range matches {
+		if opts.Limit > 0 && len(results) >= opts.Limit {
+			break
+		}
+
+		article, err := q.GetArticle(ctx, match.ArticleID)
+		if err != nil {
+			return err
+		}
+		if !opts.All && article.Status.String == "read" {
+			continue
+		}
+		if opts.Source != "" && article.SourceName.String != opts.Source {
+			continue
+		}
+		if opts.Topic != "" {
+			topics, err := q.ListArticleTopics(ctx, article.ID)
+			if err != nil {
+				return err
+			}
+			if !slices.ContainsFunc(topics, func(topic string) bool { return strings.EqualFold(topic, opts.Topic) }) {
+				continue
+			}
+		}
+
+		results = append(results, database.SearchResult{
+			Article: article,
+			Snippet: truncateString(strings.Join(strings.Fields(article.Summary.String), " "), 200),
+		})
+	}
+
+	printSearchResults(cmd, results)
+	return nil
+}
+
+// printSearchResults prints ranked results and makes them the current
+// article list for 'read' and 'open'.
+func printSearchResults(cmd *cobra.Command, results []database.SearchResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No matching articles found.")
+		return
+	}
+
+	styled := shouldUseTUIFunc()
+	stateMap := make(map[string]state.ArticleRef)
+
+	for i, result := range results {
+		fmt.Fprint(cmd.OutOrStdout(), formatSearchResult(i+1, result, styled))
+
+		stateMap[strconv.Itoa(i+1)] = state.ArticleRef{
+			ID:           result.ID,
+			URL:          formatNullString(result.Url, ""),
+			Title:        formatNullString(result.Title, ""),
+			StoryGroupID: formatNullString(result.StoryGroupID, ""),
+		}
+	}
+
+	viewState := &state.ViewState{
+		Timestamp: time.Now().UTC(),
+		Articles:  stateMap,
+	}
+	_ = state.Save(viewState)
+}
+
+func formatSearchResult(index int, result database.SearchResult, styled bool) string {
+	var b strings.Builder
+
+	title := result.TitleHighlight
+	if title == "" {
+		title = formatNullString(result.Title, "(no title)")
+	}
+	b.WriteString(fmt.Sprintf("[%d] %s\n", index, renderHighlights(title, titleStyle.Render, styled)))
+
+	sourceInfo := "Source: " + formatNullString(result.SourceName, "(no source)")
+	if result.PublishedDate.Valid {
+		sourceInfo += " • " + result.PublishedDate.Time.Format("2006-01-02")
+	}
+	b.WriteString("    " + sourceStyle.Render(sourceInfo) + "\n")
+
+	// The snippet comes from the best matching column, which may be the title itself.
+	if snippet := strings.Join(strings.Fields(result.Snippet), " "); snippet != "" && snippet != result.TitleHighlight {
+		b.WriteString("    " + renderHighlights(snippet, summaryStyle.UnsetMarginLeft().Render, styled) + "\n")
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderHighlights renders text containing database match markers, styling
+// matched terms with matchStyle, or wrapping them in ** when unstyled.
+func renderHighlights(text string, render func(...string) string, styled bool) string {
+	var b strings.Builder
+
+	for text != "" {
+		start := strings.Index(text, database.MatchStart)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(text[start:], database.MatchEnd)
+		if end < 0 {
+			break
+		}
+		end += start
+
+		match := text[start+len(database.MatchStart) : end]
+		b.WriteString(render(text[:start]))
+		if styled {
+			b.WriteString(matchStyle.Render(match))
+		} else {
+			b.WriteString("**" + match + "**")
+		}
+		text = text[end+len(database.MatchEnd):]
+	}
+
+	text = strings.NewReplacer(database.MatchStart, "", database.MatchEnd, "").Replace(text)
+	b.WriteString(render(text))
+	return b.String()
+}
+
+func init() {
+	searchCmd.Flags().StringP("db", "d", "ai-news.db", "Database file path")
+	searchCmd.Flags().Bool("all", false, "Search all articles (read and unread)")
+	searchCmd.Flags().String("source", "", "Filter results by source name")
+	searchCmd.Flags().String("topic", "", "Filter results by topic")
+	searchCmd.Flags().IntP("limit", "n", 20, "Maximum number of results (0 = unlimited)")
+	searchCmd.Flags().Bool("semantic", false, "Rank by similarity in meaning using embeddings")
+	searchCmd.Flags().StringP("config", "c", "", "Path to config file (for --semantic)")
+	rootCmd.AddCommand(searchCmd)
+}
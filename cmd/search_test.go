@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/state"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeSearchCommand(t *testing.T, dbPath string, args ...string) (string, error) {
+	originalTUI := shouldUseTUIFunc
+	shouldUseTUIFunc = func() bool { return false }
+	t.Cleanup(func() { shouldUseTUIFunc = originalTUI })
+
+	originalPath := state.GetPathFunc()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	state.SetPathFunc(func() (string, error) { return statePath, nil })
+	t.Cleanup(func() { state.SetPathFunc(originalPath) })
+
+	// searchCmd is shared between tests, so reset flags left over from earlier runs.
+	searchCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+	})
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(searchCmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"search", "--db", dbPath}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestSearchCmd_RankedResultsWithHighlights(t *testing.T) {
+	db, dbPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	insertTestArticleWithDetails(db, "Weekly roundup", "Blog", "unread", "Includes a note on agents", "", "")
+	insertTestArticleWithDetails(db, "Agents everywhere", "OpenAI Blog", "unread", "", "", "")
+	insertTestArticleWithDetails(db, "Unrelated", "Blog", "unread", "Nothing to see", "", "")
+
+	output, err := executeSearchCommand(t, dbPath, "agents")
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "[1] **Agents** everywhere")
+	assert.Contains(t, output, "[2] Weekly roundup")
+	assert.Contains(t, output, "**agents**")
+	assert.NotContains(t, output, "Unrelated")
+
+	vs, err := state.Load()
+	require.NoError(t, err)
+	require.Len(t, vs.Articles, 2)
+	assert.Equal(t, "Agents everywhere", vs.Articles["1"].Title)
+}
+
+func TestSearchCmd_Filters(t *testing.T) {
+	db, dbPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	insertTestArticleWithDetails(db, "Robotics news", "Blog", "read", "", `["Robotics"]`, "")
+	insertTestArticleWithDetails(db, "More robotics", "News", "unread", "", `["Hardware"]`, "")
+
+	output, err := executeSearchCommand(t, dbPath, "robotics")
+	require.NoError(t, err)
+	assert.Contains(t, output, "More **robotics**")
+	assert.NotContains(t, output, "Robotics news")
+
+	output, err = executeSearchCommand(t, dbPath, "robotics", "--all", "--source", "Blog")
+	require.NoError(t, err)
+	assert.Contains(t, output, "**Robotics** news")
+	assert.NotContains(t, output, "More **robotics**")
+
+	output, err = executeSearchCommand(t, dbPath, "robotics", "--all", "--source", "", "--topic", "Hardware")
+	require.NoError(t, err)
+	assert.Contains(t, output, "More **robotics**")
+	assert.NotContains(t, output, "Robotics news")
+}
+
+func TestSearchCmd_NoResultsAndInvalidQuery(t *testing.T) {
+	_, dbPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	output, err := executeSearchCommand(t, dbPath, "nothing")
+	require.NoError(t, err)
+	assert.Equal(t, "No matching articles found.\n", output)
+
+	_, err = executeSearchCommand(t, dbPath, "*")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid search query")
+}
+
+// topicEmbedder embeds a text as counts of a few topic words, standing in for
+// a real embedding model.
+type topicEmbedder struct{}
+
+func (topicEmbedder) Model() string { return "topics" }
+
+func (topicEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	words := []string{"robot", "chip", "law"}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(words))
+		for j, word := range words {
+			vectors[i][j] = float32(strings.Count(strings.ToLower(text), word))
+		}
+	}
+	return vectors, nil
+}
+
+// closingEmbedder is a topicEmbedder holding a connection to close.
+type closingEmbedder struct {
+	topicEmbedder
+	closed *int
+}
+
+func (e closingEmbedder) Close() error {
+	*e.closed++
+	return nil
+}
+
+func TestSearchCmd_Semantic(t *testing.T) {
+	db, dbPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) { return &config.Config{}, nil }
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+	originalEmbedder := newEmbedder
+	var closed int
+	newEmbedder = func(ctx context.Context, cfg config.AIConfig) (processor.Embedder, error) {
+		return closingEmbedder{closed: &closed}, nil
+	}
+	t.Cleanup(func() { newEmbedder = originalEmbedder })
+
+	insertTestArticleWithDetails(db, "Humanoids for the home", "Blog", "unread", "A robot that folds laundry", "", "")
+	insertTestArticleWithDetails(db, "Regulators move", "News", "unread", "A new law on model providers", "", "")
+	insertTestArticleWithDetails(db, "Old robot news", "News", "read", "A robot from last year", "", "")
+
+	output, err := executeSearchCommand(t, dbPath, "--semantic", "household robots")
+	require.NoError(t, err)
+	assert.Contains(t, output, "[1] Humanoids for the home")
+	assert.Contains(t, output, "A robot that folds laundry")
+	assert.NotContains(t, output, "Old robot news", "read articles are skipped without --all")
+
+	output, err = executeSearchCommand(t, dbPath, "--semantic", "--all", "--source", "News", "-n", "1", "robots")
+	require.NoError(t, err)
+	assert.Contains(t, output, "[1] Old robot news")
+	assert.NotContains(t, output, "[2]")
+
+	assert.Equal(t, 2, closed, "the embedder is closed after each search")
+
+	vs, err := state.Load()
+	require.NoError(t, err)
+	require.Len(t, vs.Articles, 1)
+	assert.Equal(t, "Old robot news", vs.Articles["1"].Title)
+}
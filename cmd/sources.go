@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/fetcher"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+var sourcesCmd = &cobra.Command{
+	Use:     "sources",
+	Aliases: []string{"source"},
+	Short:   "Manage configured news sources",
+	Long: `List, add, remove, enable, disable and reprioritize the sources in the
+config file, and inspect their fetch health.
+
+Changes are written back to the config file; settings other than the
+sources list are preserved, though YAML comments are not.
+
+Examples:
+  ai-news source add "Simon Willison" https://simonwillison.net/atom/everything/
+  ai-news source set-priority "Simon Willison" 1
+  ai-news source disable "Simon Willison"
+  ai-news sources list
+  ai-news sources status`,
+}
+
+var sourcesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured sources",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		if len(cfg.Sources) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No sources configured.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tGROUP\tTYPE\tPRIORITY\tENABLED\tURL")
+		for _, source := range cfg.Sources {
+			enabled := "yes"
+			if !source.IsEnabled() {
+				enabled = "no"
+			}
+			group := source.Group
+			if group == "" {
+				group = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", source.Name, group, source.Type, source.Priority, enabled, source.URL)
+		}
+		return w.Flush()
+	},
+}
+
+var sourcesAddCmd = &cobra.Command{
+	Use:   "add <name> <url>",
+	Short: "Add a source after checking that its feed parses",
+	Long: `Add a source after checking that its feed parses.
+
+An html source lists its articles on an ordinary web page; --item-selector
+picks one element per article, and --title-selector, --link-selector and
+--date-selector find its parts inside it (config keys html.item, html.title,
+html.link and html.date).
+
+Examples:
+  ai-news sources add "Simon Willison" https://simonwillison.net/atom/everything/
+  ai-news sources add "Lab News" https://example.com/news --type html --item-selector "article.post" --date-selector time`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		sourceType, _ := cmd.Flags().GetString("type")
+		priority, _ := cmd.Flags().GetInt("priority")
+		skipValidation, _ := cmd.Flags().GetBool("no-validate")
+		var selectors config.HTMLSelectors
+		selectors.Item, _ = cmd.Flags().GetString("item-selector")
+		selectors.Title, _ = cmd.Flags().GetString("title-selector")
+		selectors.Link, _ = cmd.Flags().GetString("link-selector")
+		selectors.Date, _ = cmd.Flags().GetString("date-selector")
+
+		if err := validatePriority(priority); err != nil {
+			return err
+		}
+		if _, err := fetcher.AdapterFor(sourceType); err != nil {
+			return err
+		}
+		if err := validateSelectors(sourceType, selectors); err != nil {
+			return err
+		}
+
+		name, url := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+		if name == "" {
+			return fmt.Errorf("invalid source name: must not be empty")
+		}
+
+		file, err := config.OpenSourcesFile(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		source := config.Source{Name: name, URL: url, Type: sourceType, Priority: priority, HTML: selectors}
+		if err := file.Add(source); err != nil {
+			return err
+		}
+
+		if !skipValidation {
+			items, err := validateFeed(cmd.Context(), source)
+			if err != nil {
+				return fmt.Errorf("invalid feed %s: %w (use --no-validate to add it anyway)", url, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Found %d items at %s\n", items, url)
+		}
+
+		if err := file.Save(); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("save config", err)))
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Added source %q to %s\n", name, file.Path())
+		return nil
+	},
+}
+
+var sourcesRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a source; its stored articles are kept",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editSource(cmd, "Removed", args[0], func(file *config.SourcesFile) error {
+			return file.Remove(args[0])
+		})
+	},
+}
+
+var sourcesEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable fetching a source",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editSource(cmd, "Enabled", args[0], func(file *config.SourcesFile) error {
+			return file.SetEnabled(args[0], true)
+		})
+	},
+}
+
+var sourcesDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Stop fetching a source without removing it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editSource(cmd, "Disabled", args[0], func(file *config.SourcesFile) error {
+			return file.SetEnabled(args[0], false)
+		})
+	},
+}
+
+var sourcesSetPriorityCmd = &cobra.Command{
+	Use:   "set-priority <name> <priority>",
+	Short: "Change the priority of a source",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		priority, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid priority %q: must be a positive integer", args[1])
+		}
+		if err := validatePriority(priority); err != nil {
+			return err
+		}
+
+		return editSource(cmd, "Updated", args[0], func(file *config.SourcesFile) error {
+			return file.SetPriority(args[0], priority)
+		})
+	},
+}
+
+var sourcesStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show fetch health for each configured source",
+	Long: `Show the fetch history recorded for each configured source: when it last
+succeeded, its current failure streak, average fetch latency, and how many
+feed items it has produced.
+
+A source that fails health.failure_threshold times in a row is skipped by
+'fetch' for health.cooldown; 'fetch --force' retries it immediately.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		db, queries, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		health, err := fetcher.ListSourceHealth(cmd.Context(), queries, cfg.Sources)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		now := time.Now()
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SOURCE\tSTATUS\tLAST SUCCESS\tFAILURES\tAVG LATENCY\tITEMS\tLAST ERROR")
+		for _, h := range health {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%d\t%s\n",
+				h.Source.Name,
+				sourceHealthStatus(h, now),
+				formatHealthTime(h.LastSuccess),
+				h.ConsecutiveFailures,
+				formatLatency(h),
+				h.ItemsSeen,
+				truncateString(h.LastError, 60),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+// validateFeed fetches a source once with the adapter for its type, so that
+// add can reject URLs that are not feeds, and returns the number of items.
+var validateFeed = func(ctx context.Context, source config.Source) (int, error) {
+	articles, err := fetcher.Fetch(ctx, source, &config.Config{NetworkTimeout: 30 * time.Second}, fetcher.FetchOptions{})
+	return len(articles), err
+}
+
+// editSource applies edit to the config file's sources and saves it.
+func editSource(cmd *cobra.Command, verb, name string, edit func(*config.SourcesFile) error) error {
+	configPath, _ := cmd.Flags().GetString("config")
+
+	file, err := config.OpenSourcesFile(configPath)
+	if err != nil {
+		return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+	}
+
+	if err := edit(file); err != nil {
+		return err
+	}
+
+	if err := file.Save(); err != nil {
+		return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("save config", err)))
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s source %q in %s\n", verb, name, file.Path())
+	return nil
+}
+
+func validatePriority(priority int) error {
+	if priority < 1 {
+		return fmt.Errorf("invalid priority %d: must be a positive integer", priority)
+	}
+	return nil
+}
+
+// validateSelectors checks that an html source has an item selector and that
+// only html sources are given selectors.
+func validateSelectors(sourceType string, selectors config.HTMLSelectors) error {
+	if sourceType != "html" {
+		if selectors.Item+selectors.Title+selectors.Link+selectors.Date != "" {
+			return fmt.Errorf("selectors only apply to sources of type html, not %s", sourceType)
+		}
+		return nil
+	}
+	if strings.TrimSpace(selectors.Item) == "" {
+		return fmt.Errorf("an html source needs --item-selector (html.item in the config file) to find its articles")
+	}
+	return nil
+}
+
+func sourceHealthStatus(h fetcher.SourceHealth, now time.Time) string {
+	switch {
+	case !h.Source.IsEnabled():
+		return "disabled"
+	case h.Fetches == 0:
+		return "never fetched"
+	case h.Quarantined(now):
+		return "quarantined until " + h.QuarantinedUntil.Local().Format("2006-01-02 15:04")
+	case h.ConsecutiveFailures > 0:
+		return "failing"
+	default:
+		return "ok"
+	}
+}
+
+func formatHealthTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func formatLatency(h fetcher.SourceHealth) string {
+	if h.Fetches == 0 {
+		return "-"
+	}
+	return h.AverageLatency.Round(time.Millisecond).String()
+}
+
+func truncateString(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+func init() {
+	sourcesCmd.PersistentFlags().StringP("config", "c", "", "Path to config file")
+
+	sourcesAddCmd.Flags().String("type", "rss", "Source type (rss, atom, jsonfeed, sitemap, html)")
+	sourcesAddCmd.Flags().Int("priority", 3, "Source priority (1 = highest)")
+	sourcesAddCmd.Flags().Bool("no-validate", false, "Add the source without checking that its feed parses")
+	sourcesAddCmd.Flags().String("item-selector", "", "CSS selector matching each article on an html source's page")
+	sourcesAddCmd.Flags().String("title-selector", "", "CSS selector for an article's title within its item (default: the link text)")
+	sourcesAddCmd.Flags().String("link-selector", "", "CSS selector for an article's link within its item (default: its first link)")
+	sourcesAddCmd.Flags().String("date-selector", "", "CSS selector for an article's date within its item")
+
+	sourcesCmd.AddCommand(
+		sourcesListCmd,
+		sourcesAddCmd,
+		sourcesRemoveCmd,
+		sourcesEnableCmd,
+		sourcesDisableCmd,
+		sourcesSetPriorityCmd,
+		sourcesStatusCmd,
+	)
+	rootCmd.AddCommand(sourcesCmd)
+}
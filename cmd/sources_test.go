@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeSourcesCommand(t *testing.T, cfg *config.Config, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return cfg, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	for _, c := range append(sourcesCmd.Commands(), sourcesCmd) {
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			_ = f.Value.Set(f.DefValue)
+		})
+	}
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(sourcesCmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"sources"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestSourcesStatusCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "sources.db")
+	cfg := &config.Config{
+		DSN: dsn,
+		Sources: []config.Source{
+			{Name: "Healthy Blog", URL: "https://example.com/healthy.xml"},
+			{Name: "Broken Blog", URL: "https://example.com/broken.xml"},
+			{Name: "New Blog", URL: "https://example.com/new.xml"},
+		},
+	}
+
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	require.NoError(t, queries.RecordFeedSuccess(ctx, database.RecordFeedSuccessParams{
+		SourceUrl:      "https://example.com/healthy.xml",
+		LastSuccessAt:  sql.NullTime{Time: now, Valid: true},
+		TotalLatencyMs: 120,
+		ItemsSeen:      7,
+	}))
+	_, err = queries.RecordFeedFailure(ctx, database.RecordFeedFailureParams{
+		SourceUrl:      "https://example.com/broken.xml",
+		LastError:      sql.NullString{String: "fetch rss: http error: 404 Not Found", Valid: true},
+		LastErrorAt:    sql.NullTime{Time: now, Valid: true},
+		TotalLatencyMs: 40,
+	})
+	require.NoError(t, err)
+	require.NoError(t, queries.QuarantineFeed(ctx, database.QuarantineFeedParams{
+		QuarantinedUntil: sql.NullTime{Time: now.Add(time.Hour), Valid: true},
+		SourceUrl:        "https://example.com/broken.xml",
+	}))
+	require.NoError(t, db.Close())
+
+	output, err := executeSourcesCommand(t, cfg, "status")
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace([]byte(output)), []byte("\n"))
+	require.Len(t, lines, 4)
+	assert.Contains(t, string(lines[0]), "SOURCE")
+	assert.Contains(t, string(lines[0]), "AVG LATENCY")
+
+	assert.Contains(t, string(lines[1]), "Healthy Blog")
+	assert.Contains(t, string(lines[1]), "ok")
+	assert.Contains(t, string(lines[1]), "120ms")
+	assert.Contains(t, string(lines[1]), "7")
+
+	assert.Contains(t, string(lines[2]), "Broken Blog")
+	assert.Contains(t, string(lines[2]), "quarantined until")
+	assert.Contains(t, string(lines[2]), "404 Not Found")
+
+	assert.Contains(t, string(lines[3]), "New Blog")
+	assert.Contains(t, string(lines[3]), "never fetched")
+}
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "short", truncateString("short", 10))
+	assert.Equal(t, "abcd…", truncateString("abcdefgh", 5))
+}
+
+func writeSourcesConfigFile(t *testing.T) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `dsn: news.db
+sources:
+  - name: "Existing Blog"
+    url: "https://example.com/existing.xml"
+    type: "rss"
+    priority: 2
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0644))
+	return configPath
+}
+
+func TestSourcesAddCommand_ValidatesFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/feed.xml" {
+			fmt.Fprint(w, "<html><body>not a feed</body></html>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprint(w, `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>New Feed</title>
+<item><title>One</title><link>https://example.com/1</link></item>
+<item><title>Two</title><link>https://example.com/2</link></item>
+</channel></rss>`)
+	}))
+	defer server.Close()
+
+	configPath := writeSourcesConfigFile(t)
+
+	output, err := executeSourcesCommand(t, nil, "add", "New Blog", server.URL+"/feed.xml", "--priority", "1", "--config", configPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Found 2 items")
+	assert.Contains(t, output, `Added source "New Blog"`)
+
+	_, err = executeSourcesCommand(t, nil, "add", "Bad Blog", server.URL+"/page.html", "--config", configPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--no-validate")
+
+	_, err = executeSourcesCommand(t, nil, "add", "Odd Blog", server.URL+"/feed.xml", "--type", "gopher", "--config", configPath)
+	assert.ErrorContains(t, err, "unsupported source type")
+
+	_, err = executeSourcesCommand(t, nil, "add", "existing blog", "https://example.com/other.xml", "--no-validate", "--config", configPath)
+	assert.ErrorIs(t, err, config.ErrDuplicateSource)
+
+	cfg, err := config.LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "news.db", cfg.DSN)
+	require.Len(t, cfg.Sources, 2)
+	assert.Equal(t, "New Blog", cfg.Sources[1].Name)
+	assert.Equal(t, server.URL+"/feed.xml", cfg.Sources[1].URL)
+	assert.Equal(t, "rss", cfg.Sources[1].Type)
+	assert.Equal(t, 1, cfg.Sources[1].Priority)
+}
+
+func TestSourcesAddCommand_HTMLSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body>
+<article class="post"><h2><a href="/one">One</a></h2><time datetime="2025-08-13">Aug 13</time></article>
+<article class="post"><h2><a href="/two">Two</a></h2><time datetime="2025-08-12">Aug 12</time></article>
+</body></html>`)
+	}))
+	defer server.Close()
+
+	configPath := writeSourcesConfigFile(t)
+
+	_, err := executeSourcesCommand(t, nil, "add", "Lab News", server.URL+"/news", "--type", "html", "--config", configPath)
+	assert.ErrorContains(t, err, "--item-selector")
+
+	_, err = executeSourcesCommand(t, nil, "add", "Lab Feed", server.URL+"/feed.xml", "--item-selector", "article", "--config", configPath)
+	assert.ErrorContains(t, err, "only apply to sources of type html")
+
+	output, err := executeSourcesCommand(t, nil, "add", "Lab News", server.URL+"/news", "--type", "html",
+		"--item-selector", "article.post", "--link-selector", "h2 a", "--date-selector", "time", "--config", configPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, "Found 2 items")
+
+	cfg, err := config.LoadFromPath(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 2)
+	assert.Equal(t, "html", cfg.Sources[1].Type)
+	assert.Equal(t, config.HTMLSelectors{Item: "article.post", Link: "h2 a", Date: "time"}, cfg.Sources[1].HTML)
+}
+
+func TestSourcesEditCommands(t *testing.T) {
+	configPath := writeSourcesConfigFile(t)
+
+	_, err := executeSourcesCommand(t, nil, "add", "Other Blog", "https://example.com/other.xml", "--no-validate", "--config", configPath)
+	require.NoError(t, err)
+
+	output, err := executeSourcesCommand(t, nil, "disable", "Existing Blog", "--config", configPath)
+	require.NoError(t, err)
+	assert.Contains(t, output, `Disabled source "Existing Blog"`)
+
+	_, err = executeSourcesCommand(t, nil, "set-priority", "Other Blog", "5", "--config", configPath)
+	require.NoError(t, err)
+
+	_, err = executeSourcesCommand(t, nil, "set-priority", "Other Blog", "0", "--config", configPath)
+	assert.Error(t, err)
+
+	_, err = executeSourcesCommand(t, nil, "enable", "Missing Blog", "--config", configPath)
+	assert.ErrorIs(t, err, config.ErrSourceNotFound)
+
+	cfg, err := config.LoadFromPath(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 2)
+	assert.False(t, cfg.Sources[0].IsEnabled())
+	assert.Equal(t, 5, cfg.Sources[1].Priority)
+	require.Len(t, cfg.EnabledSources(), 1)
+	assert.Equal(t, "Other Blog", cfg.EnabledSources()[0].Name)
+
+	output, err = executeSourcesCommand(t, cfg, "list")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "ENABLED")
+	assert.Contains(t, lines[1], "Existing Blog")
+	assert.Contains(t, lines[1], "no")
+	assert.Contains(t, lines[2], "Other Blog")
+	assert.Contains(t, lines[2], "yes")
+
+	_, err = executeSourcesCommand(t, nil, "remove", "Existing Blog", "--config", configPath)
+	require.NoError(t, err)
+
+	cfg, err = config.LoadFromPath(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 1)
+	assert.Equal(t, "Other Blog", cfg.Sources[0].Name)
+}
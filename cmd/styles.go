@@ -32,6 +32,11 @@ var (
 	duplicatesStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FFA500")).
 			MarginLeft(2)
+
+	matchStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#000000")).
+			Background(lipgloss.Color("#FFD700"))
 )
 
 func getSourceTier(sourceName string) int {
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/spf13/cobra"
+)
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show the tokens and cost of AI analysis",
+	Long: `Show how many AI calls fetch has made, the tokens they used and what
+they cost, grouped by day, ISO week or source.
+
+Costs are computed from the per-model prices under usage.prices in the
+config, in US dollars per million tokens; models without a price are listed
+and count as free. When usage.monthly_budget is set, the month's spending is
+shown against it.
+
+Examples:
+  ai-news usage
+  ai-news usage --by week --days 90
+  ai-news usage --by source`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		by, _ := cmd.Flags().GetString("by")
+		days, _ := cmd.Flags().GetInt("days")
+
+		if by != usage.ByDay && by != usage.ByWeek && by != usage.BySource {
+			return fmt.Errorf("invalid --by %q: must be day, week or source", by)
+		}
+		if days <= 0 {
+			return fmt.Errorf("invalid --days %d: must be positive", days)
+		}
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		db, queries, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		periodStart := today.AddDate(0, 0, 1-days)
+		monthStart := usage.MonthStart(now)
+		since := periodStart
+		if monthStart.Before(since) {
+			since = monthStart
+		}
+
+		rows, err := queries.ListAIUsageSince(cmd.Context(), since.Unix())
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("list AI usage", err)))
+		}
+
+		// Rows before the period are only read for the month's spending.
+		var monthCost float64
+		var period []database.ListAIUsageSinceRow
+		for _, row := range rows {
+			if row.CreatedAt >= monthStart.Unix() {
+				cost, _ := usage.Cost(cfg.Usage.Prices, row.Model, row.PromptTokens, row.OutputTokens)
+				monthCost += cost
+			}
+			if row.CreatedAt >= periodStart.Unix() {
+				period = append(period, row)
+			}
+		}
+		summary := usage.Summarize(period, cfg.Usage.Prices, by, now.Location())
+
+		out := cmd.OutOrStdout()
+		if len(summary.Rows) == 0 {
+			fmt.Fprintf(out, "No AI usage in the last %d days.\n", days)
+		} else {
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "%s\tCALLS\tPROMPT TOKENS\tOUTPUT TOKENS\tCOST\n", strings.ToUpper(by))
+			for _, row := range summary.Rows {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t$%.4f\n", row.Key, row.Calls, row.PromptTokens, row.OutputTokens, row.Cost)
+			}
+			fmt.Fprintf(w, "TOTAL\t%d\t%d\t%d\t$%.4f\n", summary.Total.Calls, summary.Total.PromptTokens, summary.Total.OutputTokens, summary.Total.Cost)
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if len(summary.Unpriced) > 0 {
+				fmt.Fprintf(out, "\nNo price configured for: %s\n", strings.Join(summary.Unpriced, ", "))
+			}
+		}
+
+		if budget := cfg.Usage.MonthlyBudget; budget > 0 {
+			fmt.Fprintf(out, "\nThis month: $%.4f of $%.2f budget (%.0f%%)\n", monthCost, budget, 100*monthCost/budget)
+		} else {
+			fmt.Fprintf(out, "\nThis month: $%.4f (no monthly budget)\n", monthCost)
+		}
+		return nil
+	},
+}
+
+func init() {
+	usageCmd.Flags().StringP("config", "c", "", "Path to config file")
+	usageCmd.Flags().String("by", usage.ByDay, "Group usage by day, week or source")
+	usageCmd.Flags().Int("days", 30, "Number of days to report, including today")
+	rootCmd.AddCommand(usageCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeUsageCommand(t *testing.T, cfg *config.Config, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return cfg, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	usageCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+	})
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(usageCmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"usage"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestUsageCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "usage.db")
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+
+	now := time.Now()
+	for _, params := range []database.CreateAIUsageParams{
+		{RunID: "a", SourceName: "Blog", Provider: "gemini", Model: "gemini-2.5-flash", PromptTokens: 1_000_000, OutputTokens: 100_000, CreatedAt: now.Unix()},
+		{RunID: "a", SourceName: "Papers", Provider: "openai", Model: "llama3", PromptTokens: 2000, OutputTokens: 300, CreatedAt: now.Unix()},
+		{RunID: "old", SourceName: "Blog", Provider: "gemini", Model: "gemini-2.5-flash", PromptTokens: 1_000_000, CreatedAt: now.AddDate(0, 0, -60).Unix()},
+	} {
+		require.NoError(t, queries.CreateAIUsage(context.Background(), params))
+	}
+	db.Close()
+
+	cfg := &config.Config{
+		DSN: dsn,
+		Usage: config.UsageConfig{
+			Prices:        []config.ModelPrice{{Model: "gemini-2.5-flash", Input: 0.30, Output: 2.50}},
+			MonthlyBudget: 1,
+		},
+	}
+
+	output, err := executeUsageCommand(t, cfg, "--by", "source")
+	require.NoError(t, err)
+	assert.Contains(t, output, "SOURCE")
+	assert.Regexp(t, `Blog\s+1\s+1000000\s+100000\s+\$0\.5500`, output)
+	assert.Regexp(t, `Papers\s+1\s+2000\s+300\s+\$0\.0000`, output)
+	assert.Regexp(t, `TOTAL\s+2\s+1002000\s+100300\s+\$0\.5500`, output)
+	assert.Contains(t, output, "No price configured for: llama3")
+	assert.Contains(t, output, "This month: $0.5500 of $1.00 budget (55%)")
+
+	output, err = executeUsageCommand(t, cfg, "--by", "week", "--days", "90")
+	require.NoError(t, err)
+	assert.Regexp(t, `TOTAL\s+3\s+`, output)
+
+	_, err = executeUsageCommand(t, cfg, "--by", "model")
+	assert.ErrorContains(t, err, "invalid --by")
+}
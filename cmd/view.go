@@ -21,6 +21,8 @@ type ViewOptions struct {
 	All    bool
 	Source string
 	Topic  string
+	Entity string
+	Author string
 }
 
 var databaseOpen = database.Open
@@ -35,11 +37,15 @@ var viewCmd = &cobra.Command{
 		all, _ := cmd.Flags().GetBool("all")
 		source, _ := cmd.Flags().GetString("source")
 		topic, _ := cmd.Flags().GetString("topic")
+		entity, _ := cmd.Flags().GetString("entity")
+		author, _ := cmd.Flags().GetString("author")
 
 		opts := ViewOptions{
 			All:    all,
 			Source: source,
 			Topic:  topic,
+			Entity: entity,
+			Author: author,
 		}
 
 		if shouldUseTUIFunc() {
@@ -63,7 +69,7 @@ func runTUIView(dbPath string, opts ViewOptions) error {
 	}
 
 	ctx := context.Background()
-	articles, err := getFilteredArticles(ctx, q, opts.All, opts.Source, opts.Topic)
+	articles, err := getFilteredArticles(ctx, q, opts)
 	if err != nil {
 		return err
 	}
@@ -79,6 +85,8 @@ func runTUIView(dbPath string, opts ViewOptions) error {
 			URL:     formatNullString(article.Url, ""),
 			IsRead:  article.Status.String == "read",
 			Content: formatNullString(article.Content, ""),
+			Author:  formatNullString(article.Author, ""),
+			Image:   formatNullString(article.ImageUrl, ""),
 		})
 	}
 
@@ -151,7 +159,7 @@ func runLegacyView(cmd *cobra.Command, dbPath string, opts ViewOptions) error {
 	}
 
 	ctx := context.Background()
-	articles, err := getFilteredArticles(ctx, q, opts.All, opts.Source, opts.Topic)
+	articles, err := getFilteredArticles(ctx, q, opts)
 	if err != nil {
 		return err
 	}
@@ -242,31 +250,49 @@ func formatTopics(topicsJSON interface{}) string {
 	return strings.Join(topics, ", ")
 }
 
-func getFilteredArticles(ctx context.Context, q *database.Queries, all bool, source, topic string) ([]database.Article, error) {
+// getFilteredArticles lists the articles matching opts. Topic, entity and
+// author names match exactly, ignoring case.
+func getFilteredArticles(ctx context.Context, q *database.Queries, opts ViewOptions) ([]database.Article, error) {
+	all, source, topic := opts.All, opts.Source, opts.Topic
 	hasSource := source != ""
 	hasTopic := topic != ""
 
 	switch {
+	case opts.Author != "":
+		return q.ListArticlesByAuthor(ctx, database.ListArticlesByAuthorParams{
+			IncludeRead: all,
+			SourceName:  sql.NullString{String: source, Valid: hasSource},
+			Topic:       sql.NullString{String: topic, Valid: hasTopic},
+			Entity:      sql.NullString{String: opts.Entity, Valid: opts.Entity != ""},
+			Author:      opts.Author,
+		})
+	case opts.Entity != "":
+		return q.ListArticlesByEntity(ctx, database.ListArticlesByEntityParams{
+			IncludeRead: all,
+			SourceName:  sql.NullString{String: source, Valid: hasSource},
+			Topic:       sql.NullString{String: topic, Valid: hasTopic},
+			Entity:      opts.Entity,
+		})
 	case all && hasSource && hasTopic:
 		return q.ListAllArticlesBySourceAndTopic(ctx, database.ListAllArticlesBySourceAndTopicParams{
 			SourceName: sql.NullString{String: source, Valid: true},
-			Column2:    sql.NullString{String: topic, Valid: true},
+			Name:       topic,
 		})
 	case all && hasSource:
 		return q.ListAllArticlesBySource(ctx, sql.NullString{String: source, Valid: true})
 	case all && hasTopic:
-		return q.ListAllArticlesByTopic(ctx, sql.NullString{String: topic, Valid: true})
+		return q.ListAllArticlesByTopic(ctx, topic)
 	case all:
 		return q.ListAllArticles(ctx)
 	case hasSource && hasTopic:
 		return q.ListArticlesBySourceAndTopic(ctx, database.ListArticlesBySourceAndTopicParams{
 			SourceName: sql.NullString{String: source, Valid: true},
-			Column2:    sql.NullString{String: topic, Valid: true},
+			Name:       topic,
 		})
 	case hasSource:
 		return q.ListArticlesBySource(ctx, sql.NullString{String: source, Valid: true})
 	case hasTopic:
-		return q.ListArticlesByTopic(ctx, sql.NullString{String: topic, Valid: true})
+		return q.ListArticlesByTopic(ctx, topic)
 	default:
 		return q.ListUnreadArticles(ctx)
 	}
@@ -304,5 +330,7 @@ func init() {
 	viewCmd.Flags().Bool("all", false, "Show all articles (read and unread) and don't mark as read")
 	viewCmd.Flags().String("source", "", "Filter articles by source name")
 	viewCmd.Flags().String("topic", "", "Filter articles by topic")
+	viewCmd.Flags().String("entity", "", "Filter articles by organization, product or person")
+	viewCmd.Flags().String("author", "", "Filter articles by author")
 	rootCmd.AddCommand(viewCmd)
 }
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -53,7 +55,7 @@ func insertTestArticleWithDetails(db *sql.DB, title, sourceName, status, summary
 	}
 
 	q := database.New(db)
-	_, err := q.CreateArticle(context.Background(), database.CreateArticleParams{
+	article, err := q.CreateArticle(context.Background(), database.CreateArticleParams{
 		Title:        titleVal,
 		Url:          sql.NullString{String: "http://example.com/" + title + "-" + sourceName, Valid: true},
 		SourceName:   sourceVal,
@@ -65,9 +67,23 @@ func insertTestArticleWithDetails(db *sql.DB, title, sourceName, status, summary
 	if err != nil {
 		panic(err)
 	}
+
+	// Topic filters use the normalized topic tables, so tag the article the
+	// way the fetcher does. Malformed topics JSON is left untagged.
+	var tags []string
+	if json.Unmarshal([]byte(topics), &tags) == nil {
+		if err := q.TagArticle(context.Background(), article.ID, tags, nil); err != nil {
+			panic(err)
+		}
+	}
 }
 
 func executeViewCommand(args ...string) (string, error) {
+	// viewCmd is shared between tests, so reset flags left over from earlier runs.
+	viewCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+	})
+
 	cmd := NewRootCmd()
 	cmd.AddCommand(viewCmd)
 
@@ -309,6 +325,72 @@ func TestViewCmd_TopicFilterWorksCorrectly(t *testing.T) {
 	assert.NotContains(t, output, "ML Article")
 }
 
+func TestViewCmd_TopicFilterIsExactAndCaseInsensitive(t *testing.T) {
+	db, dbPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	insertTestArticleWithDetails(db, "AI Article", "Google AI Blog", "unread", "Summary", `["AI"]`, "story-1")
+	insertTestArticleWithDetails(db, "OpenAI Article", "OpenAI Blog", "unread", "Summary", `["OpenAI"]`, "story-2")
+
+	output, err := executeViewCommand("view", "--topic", "ai", "--db", dbPath)
+
+	assert.NoError(t, err)
+	assert.Contains(t, output, "AI Article")
+	assert.NotContains(t, output, "OpenAI Article")
+}
+
+func TestViewCmd_EntityFilterWorksCorrectly(t *testing.T) {
+	db, dbPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	insertTestArticleWithDetails(db, "Gemini Article", "Google AI Blog", "unread", "Summary", `["AI"]`, "story-1")
+	insertTestArticleWithDetails(db, "Other Article", "OpenAI Blog", "unread", "Summary", `["AI"]`, "story-2")
+
+	q := database.New(db)
+	articles, err := q.ListArticlesByTopic(context.Background(), "AI")
+	require.NoError(t, err)
+	for _, article := range articles {
+		if article.Title.String == "Gemini Article" {
+			err = q.TagArticle(context.Background(), article.ID, nil, []database.EntityRef{
+				{Name: "Gemini", Kind: database.EntityProduct},
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	output, err := executeViewCommand("view", "--entity", "gemini", "--db", dbPath)
+
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Gemini Article")
+	assert.NotContains(t, output, "Other Article")
+}
+
+func TestViewCmd_AuthorFilterWorksCorrectly(t *testing.T) {
+	db, dbPath, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	insertTestArticleWithDetails(db, "Jane's Article", "Google AI Blog", "unread", "Summary", `["AI"]`, "story-1")
+	insertTestArticleWithDetails(db, "John's Article", "OpenAI Blog", "unread", "Summary", `["AI"]`, "story-2")
+
+	q := database.New(db)
+	articles, err := q.ListArticlesByTopic(context.Background(), "AI")
+	require.NoError(t, err)
+	for _, article := range articles {
+		author := "John Roe"
+		if article.Title.String == "Jane's Article" {
+			author = "Jane Doe"
+		}
+		err = q.SetArticleMetadata(context.Background(), article.ID, database.ArticleMetadata{Authors: []string{author}})
+		require.NoError(t, err)
+	}
+
+	output, err := executeViewCommand("view", "--author", "jane doe", "--db", dbPath)
+
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Jane's Article")
+	assert.NotContains(t, output, "John's Article")
+}
+
 func TestViewCmd_StyledOutputContainsExpectedElements(t *testing.T) {
 	db, dbPath, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/fetcher"
+	"github.com/robertguss/rss-agent-cli/internal/jobs"
+	"github.com/robertguss/rss-agent-cli/internal/scraper"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Run queued scraping and analysis jobs",
+	Long: `Run the scraping and analysis jobs that fetch queues for new articles.
+
+Jobs are kept in the database, so work left over by an interrupted fetch or
+worker is picked up by the next one, and several workers, in one or more
+processes or on machines sharing the database file, can run at once: each
+job is leased to one worker at a time. The lease is renewed while the job
+runs; a job whose worker dies is taken over once its lease (process.lease,
+default 10m) runs out.
+
+A failed job is retried with a growing delay until the article has failed
+process.max_attempts times. A job held back by the monthly AI budget is
+postponed without counting an attempt and checks the budget again within
+the hour.
+
+Examples:
+  ai-news fetch --enqueue-only && ai-news worker --once
+  ai-news worker --workers 8
+  ai-news worker --status`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		useMockAI, _ := cmd.Flags().GetBool("use-mock-ai")
+		workers, _ := cmd.Flags().GetInt("workers")
+		once, _ := cmd.Flags().GetBool("once")
+		poll, _ := cmd.Flags().GetDuration("poll")
+		status, _ := cmd.Flags().GetBool("status")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		cfg, err := loadCfg(configPath)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("load config", err)))
+		}
+
+		if err := logging.Init(cfg.LogFile); err != nil {
+			return fmt.Errorf("failed to initialize logging: %w", err)
+		}
+
+		db, queries, err := openDB(cfg.DSN)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		defer db.Close()
+
+		if err := initDB(db); err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+
+		out := cmd.OutOrStdout()
+		if status {
+			counts, err := jobs.NewQueue(queries).Counts(ctx)
+			if err != nil {
+				return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+			}
+			if len(counts) == 0 {
+				fmt.Fprintln(out, "No jobs queued.")
+				return nil
+			}
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KIND\tSTATE\tJOBS")
+			for _, row := range counts {
+				fmt.Fprintf(w, "%s\t%s\t%d\n", row.Kind, row.State, row.Count)
+			}
+			return w.Flush()
+		}
+
+		scrapers, err := newScrapers(cfg)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("initialize scraper", err)))
+		}
+		deps, cache, err := newPipeline(ctx, cfg, queries, useMockAI)
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(err))
+		}
+		if workers <= 0 {
+			workers = runtime.NumCPU()
+		}
+		if once {
+			poll = 0
+		}
+
+		worker, _ := newJobWorker(deps, scrapers)
+		var tally jobTally
+		report := func(result jobs.Result) {
+			tally.add(result)
+			outcome := "done"
+			if result.Err != nil {
+				outcome = "failed: " + errs.GetUserFriendlyMessage(result.Err)
+			}
+			fmt.Fprintf(out, "%s job for article %d: %s\n", result.Job.Kind, result.Job.ArticleID, outcome)
+		}
+
+		if !once {
+			fmt.Fprintf(out, "Worker %s running jobs with %d workers; press Ctrl-C to stop\n", worker.Owner, workers)
+		}
+		err = worker.Run(ctx, workers, poll, report)
+
+		printJobTally(cmd, &tally)
+		if line := formatAIUsage(ctx, deps.Usage, cache); line != "" {
+			fmt.Fprintln(out, line)
+		}
+		if err != nil {
+			return fmt.Errorf("%s", errs.GetUserFriendlyMessage(errs.Wrap("run jobs", err)))
+		}
+		return nil
+	},
+}
+
+// newJobWorker returns a worker that runs the jobs in deps.Jobs with deps and
+// scrapers, as the process settings of deps.Config direct, and the runner
+// behind its handlers.
+func newJobWorker(deps fetcher.PipelineDeps, scrapers map[string]scraper.Scraper) (*jobs.Worker, *fetcher.JobRunner) {
+	settings := deps.Config.Process
+	runner := &fetcher.JobRunner{Deps: deps, MaxAttempts: settings.MaxAttempts, Scrapers: scrapers}
+	worker := &jobs.Worker{
+		Queue:       deps.Jobs,
+		Owner:       jobs.NewOwner(),
+		Handlers:    runner.Handlers(),
+		Lease:       settings.Lease,
+		MaxAttempts: settings.MaxAttempts,
+	}
+	return worker, runner
+}
+
+// jobTally counts the outcomes of jobs as a worker reports them.
+type jobTally struct {
+	mu        sync.Mutex
+	ran       int
+	analyzed  int
+	postponed int
+	failures  []error
+}
+
+func (t *jobTally) add(result jobs.Result) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ran++
+	switch {
+	case result.Err == nil:
+		if result.Job.Kind == fetcher.JobAnalyze {
+			t.analyzed++
+		}
+	case stderrors.Is(result.Err, usage.ErrBudgetExceeded):
+		t.postponed++
+	default:
+		t.failures = append(t.failures, fmt.Errorf("%s job for article %d: %s",
+			result.Job.Kind, result.Job.ArticleID, errs.GetUserFriendlyMessage(result.Err)))
+	}
+}
+
+func (t *jobTally) String() string {
+	s := fmt.Sprintf("Ran %d jobs: %d articles analyzed, %d failed", t.ran, t.analyzed, len(t.failures))
+	if t.postponed > 0 {
+		s += fmt.Sprintf(", %d postponed by the budget", t.postponed)
+	}
+	return s
+}
+
+// printJobTally prints the summary of the jobs a plain fetch or a worker ran
+// and what went wrong.
+func printJobTally(cmd *cobra.Command, tally *jobTally) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out, tally.String())
+	for _, err := range tally.failures {
+		fmt.Fprintf(out, "  - %v\n", err)
+	}
+}
+
+func init() {
+	workerCmd.Flags().StringP("config", "c", "", "Path to config file")
+	workerCmd.Flags().Bool("use-mock-ai", false, "Use mock AI processor for testing")
+	workerCmd.Flags().IntP("workers", "w", 0, "Number of worker goroutines (0 = auto-detect based on CPU cores)")
+	workerCmd.Flags().Bool("once", false, "Exit once no job is due instead of waiting for more")
+	workerCmd.Flags().Duration("poll", 5*time.Second, "How often to check for new jobs while the queue is empty")
+	workerCmd.Flags().Bool("status", false, "Show how many jobs are queued, running and failed, and exit")
+	rootCmd.AddCommand(workerCmd)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/fetcher"
+	"github.com/robertguss/rss-agent-cli/internal/jobs"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeWorkerCommand(t *testing.T, cfg *config.Config, args ...string) (string, error) {
+	originalLoadCfg := loadCfg
+	loadCfg = func(configPath string) (*config.Config, error) {
+		return cfg, nil
+	}
+	t.Cleanup(func() { loadCfg = originalLoadCfg })
+
+	workerCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+
+	cmd := NewRootCmd()
+	cmd.AddCommand(workerCmd)
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(append([]string{"worker"}, args...))
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestWorkerCommand(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "worker.db")
+	db, queries, err := database.Open(dsn)
+	require.NoError(t, err)
+	require.NoError(t, database.InitSchema(db))
+
+	ctx := context.Background()
+	queue := jobs.NewQueue(queries)
+	for _, url := range []string{"https://a.example/1", "https://a.example/2"} {
+		article, err := queries.CreateArticle(ctx, database.CreateArticleParams{
+			Title:          sql.NullString{String: "Article", Valid: true},
+			Url:            sql.NullString{String: url, Valid: true},
+			SourceName:     sql.NullString{String: "A", Valid: true},
+			Content:        sql.NullString{String: "Some article content", Valid: true},
+			PublishedDate:  sql.NullTime{Time: time.Now(), Valid: true},
+			AnalysisStatus: sql.NullString{String: "pending", Valid: true},
+		})
+		require.NoError(t, err)
+		_, err = queue.Enqueue(ctx, fetcher.JobAnalyze, article.ID)
+		require.NoError(t, err)
+	}
+	db.Close()
+
+	cfg := &config.Config{DSN: dsn, Process: config.ProcessConfig{MaxAttempts: 5, Lease: time.Minute}}
+
+	output, err := executeWorkerCommand(t, cfg, "--status")
+	require.NoError(t, err)
+	assert.Regexp(t, `analyze\s+queued\s+2`, output)
+
+	output, err = executeWorkerCommand(t, cfg, "--once", "--use-mock-ai", "--workers", "2")
+	require.NoError(t, err)
+	assert.Contains(t, output, "Ran 2 jobs: 2 articles analyzed, 0 failed")
+
+	output, err = executeWorkerCommand(t, cfg, "--status")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No jobs queued.")
+
+	db, queries, err = database.Open(dsn)
+	require.NoError(t, err)
+	defer db.Close()
+	article, err := queries.GetArticleByUrl(ctx, sql.NullString{String: "https://a.example/1", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, "completed", article.AnalysisStatus.String)
+}
@@ -0,0 +1,246 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// analysisPrompt renders the default prompt for content, for callers that
+// did not choose a prompt with WithPrompt.
+func analysisPrompt(content string) string {
+	prompt, err := DefaultPrompt().Render(PromptData{Content: content})
+	if err != nil {
+		panic(err)
+	}
+	return prompt
+}
+
+// repairPrompt asks a model to correct an answer to prompt that could not
+// be used, telling it why.
+func repairPrompt(prompt, response string, problem error) string {
+	return fmt.Sprintf(`%s
+
+Your previous answer was:
+%s
+
+It could not be used: %v
+Answer again with only the corrected JSON object.`, prompt, response, problem)
+}
+
+// generateFunc sends a prompt to a model and returns the text of its answer.
+type generateFunc func(ctx context.Context, prompt string) (string, error)
+
+// runAnalysis has generate analyze content, with the prompt from ctx if
+// there is one. When the answer is not a valid analysis, the model is asked
+// once more with the problem included, and the error is only returned if
+// the second answer fails as well.
+func runAnalysis(ctx context.Context, provider, content string, generate generateFunc) (*AnalysisResult, error) {
+	prompt := PromptFrom(ctx)
+	if prompt == "" {
+		prompt = analysisPrompt(content)
+	}
+
+	response, err := generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseAnalysis(response)
+	if err == nil {
+		return result, nil
+	}
+
+	logging.Warn("ai_analysis", fmt.Sprintf("asking %s to repair its response: %v", provider, err))
+	response, err = generate(ctx, repairPrompt(prompt, response, err))
+	if err != nil {
+		return nil, err
+	}
+	result, err = parseAnalysis(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s response: %w", provider, err)
+	}
+	return result, nil
+}
+
+// parseAnalysis decodes and validates a model's answer to analysisPrompt.
+// It accepts the JSON object surrounded by prose or Markdown fences, with
+// trailing commas, and with lists given as a single string or summaries
+// given as a list of bullets.
+func parseAnalysis(response string) (*AnalysisResult, error) {
+	object, err := extractJSON(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var analysis struct {
+		Summary  looseText `json:"summary"`
+		Entities struct {
+			Organizations looseStrings `json:"organizations"`
+			Products      looseStrings `json:"products"`
+			People        looseStrings `json:"people"`
+		} `json:"entities"`
+		Topics      looseStrings `json:"topics"`
+		ContentType string       `json:"content_type"`
+	}
+	if err := json.Unmarshal([]byte(object), &analysis); err != nil {
+		return nil, err
+	}
+
+	result := &AnalysisResult{
+		Summary: strings.TrimSpace(string(analysis.Summary)),
+		Entities: Entities{
+			Organizations: analysis.Entities.Organizations.clean(),
+			Products:      analysis.Entities.Products.clean(),
+			People:        analysis.Entities.People.clean(),
+		},
+		Topics:      analysis.Topics.clean(),
+		ContentType: canonicalContentType(analysis.ContentType),
+	}
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// extractJSON returns the first complete JSON object in response, with
+// trailing commas removed.
+func extractJSON(response string) (string, error) {
+	response = cleanJSONResponse(response)
+
+	for start := strings.IndexByte(response, '{'); start >= 0; {
+		if end := matchingBrace(response, start); end > 0 {
+			object := removeTrailingCommas(response[start : end+1])
+			if json.Valid([]byte(object)) {
+				return object, nil
+			}
+		}
+		next := strings.IndexByte(response[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return "", errors.New("no JSON object found in response")
+}
+
+// matchingBrace returns the index of the brace closing the one at start,
+// or -1 if it is not closed.
+func matchingBrace(s string, start int) int {
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// removeTrailingCommas drops commas that directly precede a closing brace
+// or bracket outside of strings.
+func removeTrailingCommas(s string) string {
+	var b strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		} else if c == '"' {
+			inString = true
+		} else if c == ',' {
+			rest := strings.TrimLeft(s[i+1:], " \t\r\n")
+			if rest != "" && (rest[0] == '}' || rest[0] == ']') {
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// cleanJSONResponse removes markdown code block formatting from the response.
+func cleanJSONResponse(response string) string {
+	// Remove markdown code block markers
+	response = strings.TrimSpace(response)
+	response = strings.TrimPrefix(response, "```json")
+	response = strings.TrimPrefix(response, "```")
+	response = strings.TrimSuffix(response, "```")
+	return strings.TrimSpace(response)
+}
+
+// looseText decodes a string, or a list of strings joined by newlines.
+type looseText string
+
+func (t *looseText) UnmarshalJSON(data []byte) error {
+	var lines []string
+	if err := json.Unmarshal(data, &lines); err == nil {
+		*t = looseText(strings.Join(lines, "\n"))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = looseText(s)
+	return nil
+}
+
+// looseStrings decodes a list of strings, or a single comma-separated
+// string.
+type looseStrings []string
+
+func (l *looseStrings) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*l = list
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*l = strings.Split(s, ",")
+	return nil
+}
+
+// clean trims the entries and drops empty ones.
+func (l looseStrings) clean() []string {
+	var out []string
+	for _, s := range l {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
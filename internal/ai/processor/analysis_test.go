@@ -0,0 +1,125 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{
+			name:     "plain object",
+			response: `{"summary": "s"}`,
+			want:     `{"summary": "s"}`,
+		},
+		{
+			name:     "code fence",
+			response: "```json\n{\"summary\": \"s\"}\n```",
+			want:     `{"summary": "s"}`,
+		},
+		{
+			name:     "prose around the object",
+			response: "Sure! Here is the analysis:\n{\"summary\": \"s\"}\nLet me know if you need more.",
+			want:     `{"summary": "s"}`,
+		},
+		{
+			name:     "braces in prose and strings",
+			response: `Using the {format} you gave: {"summary": "a } inside", "topics": ["x"]}`,
+			want:     `{"summary": "a } inside", "topics": ["x"]}`,
+		},
+		{
+			name:     "trailing commas",
+			response: `{"topics": ["a", "b",], "summary": "s, t",}`,
+			want:     `{"topics": ["a", "b"], "summary": "s, t"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractJSON(tt.response)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := extractJSON("I cannot analyze this article.")
+	assert.Error(t, err)
+}
+
+func TestParseAnalysis_ToleratesLooseTypes(t *testing.T) {
+	result, err := parseAnalysis(`Here you go: {
+		"summary": ["• First point", "• Second point"],
+		"entities": {"organizations": "OpenAI, Microsoft", "people": null},
+		"topics": "LLMs",
+		"content_type": "product launch",
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, "• First point\n• Second point", result.Summary)
+	assert.Equal(t, []string{"OpenAI", "Microsoft"}, result.Entities.Organizations)
+	assert.Empty(t, result.Entities.People)
+	assert.Equal(t, []string{"LLMs"}, result.Topics)
+	assert.Equal(t, "Product Launch", result.ContentType)
+}
+
+func TestAnalysisResult_Validate(t *testing.T) {
+	valid := AnalysisResult{Summary: "• Point", ContentType: "Tutorial", Topics: []string{"Agents"}}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(*AnalysisResult)
+		want   string
+	}{
+		{name: "missing summary", modify: func(a *AnalysisResult) { a.Summary = " " }, want: "summary is required"},
+		{name: "long summary", modify: func(a *AnalysisResult) { a.Summary = strings.Repeat("x", maxSummaryLength+1) }, want: "summary is 2001 characters"},
+		{name: "missing content type", modify: func(a *AnalysisResult) { a.ContentType = "" }, want: "content_type is required"},
+		{name: "unknown content type", modify: func(a *AnalysisResult) { a.ContentType = "Podcast" }, want: `content_type "Podcast" is not one of`},
+		{name: "too many topics", modify: func(a *AnalysisResult) { a.Topics = make([]string, maxTopics+1) }, want: "topics has 11 entries"},
+		{name: "long entity", modify: func(a *AnalysisResult) { a.Entities.People = []string{strings.Repeat("x", maxEntityLength+1)} }, want: "entities.people entry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := valid
+			tt.modify(&result)
+			assert.ErrorContains(t, result.Validate(), tt.want)
+		})
+	}
+}
+
+func TestRunAnalysis_RepairsOnce(t *testing.T) {
+	var prompts []string
+	answers := []string{`{"summary": ""}`, `{"summary": "still", "content_type": "nope"}`}
+	generate := func(ctx context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return answers[len(prompts)-1], nil
+	}
+
+	_, err := runAnalysis(context.Background(), "test", "content", generate)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse test response")
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[1], "summary is required")
+	assert.Contains(t, prompts[1], `{"summary": ""}`)
+}
+
+func TestRunAnalysis_ReturnsGenerateErrors(t *testing.T) {
+	calls := 0
+	generate := func(ctx context.Context, prompt string) (string, error) {
+		calls++
+		return "", errors.New("openai server error (status 503)")
+	}
+
+	_, err := runAnalysis(context.Background(), "test", "content", generate)
+	assert.EqualError(t, err, "openai server error (status 503)")
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// CachingProcessor answers from the ai_cache table when the same content
+// has been analyzed before by the same models with the same version of the
+// prompt, and otherwise asks the processor it wraps and stores the answer.
+// Analyses sent with a prompt of unknown version are not cached. Failing to
+// read or write the cache is logged and does not fail the analysis.
+type CachingProcessor struct {
+	inner   AIProcessor
+	queries *database.Queries
+	model   string
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCachingProcessor caches the analyses of inner, keyed by model, which
+// identifies the models behind inner (see Registry.Models).
+func NewCachingProcessor(inner AIProcessor, queries *database.Queries, model string) *CachingProcessor {
+	return &CachingProcessor{inner: inner, queries: queries, model: model}
+}
+
+func (c *CachingProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
+	return c.analyze(context.Background(), content, func(context.Context) (*AnalysisResult, error) {
+		return c.inner.AnalyzeContent(content)
+	})
+}
+
+func (c *CachingProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
+	return c.analyze(ctx, content, func(ctx context.Context) (*AnalysisResult, error) {
+		return c.inner.AnalyzeContentWithRetry(ctx, content, cfg)
+	})
+}
+
+// Stats returns how many analyses were answered from the cache and how many
+// had to be asked for.
+func (c *CachingProcessor) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+func (c *CachingProcessor) analyze(ctx context.Context, content string, run func(context.Context) (*AnalysisResult, error)) (*AnalysisResult, error) {
+	version, ok := promptVersion(ctx)
+	if !ok {
+		return run(ctx)
+	}
+	key := database.GetAICacheEntryParams{
+		ContentHash:   ContentHash(content),
+		Model:         c.model,
+		PromptVersion: version,
+	}
+
+	data, err := c.queries.GetAICacheEntry(ctx, key)
+	if err == nil {
+		var result AnalysisResult
+		if err := json.Unmarshal([]byte(data), &result); err == nil {
+			c.hits.Add(1)
+			return &result, nil
+		}
+		logging.Warn("ai_cache", fmt.Sprintf("ignoring unreadable cache entry %s: %v", key.ContentHash, err))
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		logging.Warn("ai_cache", fmt.Sprintf("failed to read cache: %v", err))
+	}
+
+	c.misses.Add(1)
+	result, err := run(ctx)
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	encoded, err := json.Marshal(result)
+	if err == nil {
+		err = c.queries.PutAICacheEntry(ctx, database.PutAICacheEntryParams{
+			ContentHash:   key.ContentHash,
+			Model:         key.Model,
+			PromptVersion: key.PromptVersion,
+			Result:        string(encoded),
+			CreatedAt:     time.Now().Unix(),
+		})
+	}
+	if err != nil {
+		logging.Warn("ai_cache", fmt.Sprintf("failed to write cache: %v", err))
+	}
+	return result, nil
+}
+
+// ContentHash returns the cache key of content: a SHA-256 hash of the
+// content lowercased and with runs of whitespace collapsed, so that copies
+// of an article that differ only in formatting share an entry.
+func ContentHash(content string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(content), " "))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
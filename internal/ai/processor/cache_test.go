@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func setupCacheDB(t *testing.T) *database.Queries {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "cache.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, database.InitSchema(db))
+	return database.New(db)
+}
+
+func TestContentHash_IgnoresCaseAndWhitespace(t *testing.T) {
+	assert.Equal(t, ContentHash("Meta released  Llama 4.\n\nIt is open."), ContentHash("meta released Llama 4. It is open.  "))
+	assert.NotEqual(t, ContentHash("Meta released Llama 4."), ContentHash("Meta released Llama 3."))
+}
+
+func TestCachingProcessor_AnswersRepeatedContentFromCache(t *testing.T) {
+	queries := setupCacheDB(t)
+	inner := &stubProcessor{result: &AnalysisResult{Summary: "• Llama 4", Topics: []string{"LLMs"}, ContentType: "Product Launch"}}
+	cache := NewCachingProcessor(inner, queries, "gemini/gemini-2.5-flash")
+	cfg := &config.Config{}
+	prompt := DefaultPrompt()
+
+	first, err := AnalyzeChunked(context.Background(), cache, prompt, PromptData{URL: "https://a.example/llama", Content: "Meta released Llama 4."}, cfg)
+	require.NoError(t, err)
+	second, err := AnalyzeChunked(context.Background(), cache, prompt, PromptData{URL: "https://b.example/llama", Content: "Meta released\nLlama 4."}, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, inner.calls)
+	assert.Equal(t, first, second)
+	hits, misses := cache.Stats()
+	assert.Equal(t, int64(1), hits)
+	assert.Equal(t, int64(1), misses)
+
+	// Another prompt version or model is a different entry.
+	other, err := ParsePrompt("other", "Summarize: {{.Content}}")
+	require.NoError(t, err)
+	_, err = AnalyzeChunked(context.Background(), cache, other, PromptData{Content: "Meta released Llama 4."}, cfg)
+	require.NoError(t, err)
+	_, err = NewCachingProcessor(inner, queries, "openai/gpt-4o-mini").AnalyzeContent("Meta released Llama 4.")
+	require.NoError(t, err)
+	assert.Equal(t, 3, inner.calls)
+
+	removed, err := queries.DeleteAICache(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), removed)
+}
+
+func TestCachingProcessor_SkipsPromptsOfUnknownVersion(t *testing.T) {
+	inner := &stubProcessor{result: &AnalysisResult{Summary: "s", ContentType: "News Article"}}
+	cache := NewCachingProcessor(inner, setupCacheDB(t), "mock/")
+
+	ctx := WithPrompt(context.Background(), "hand-written prompt")
+	for i := 0; i < 2; i++ {
+		_, err := cache.AnalyzeContentWithRetry(ctx, "content", &config.Config{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, inner.calls)
+	hits, misses := cache.Stats()
+	assert.Zero(t, hits+misses)
+}
+
+func TestCachingProcessor_DoesNotCacheErrors(t *testing.T) {
+	inner := &stubProcessor{err: assert.AnError}
+	cache := NewCachingProcessor(inner, setupCacheDB(t), "mock/")
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.AnalyzeContent("content")
+		assert.ErrorIs(t, err, assert.AnError)
+	}
+	assert.Equal(t, 2, inner.calls)
+}
@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// runesPerToken is the rough number of characters in a token of English
+// text for the tokenizers of current models.
+const runesPerToken = 4
+
+// EstimateTokens estimates how many tokens s takes up in a prompt.
+func EstimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + runesPerToken - 1) / runesPerToken
+}
+
+// SplitMarkdown splits content into chunks of at most maxTokens estimated
+// tokens. Chunks break before headings where possible, else between
+// paragraphs, else between lines, and only split a line that is too long on
+// its own. Headings inside fenced code blocks are ignored. Content that fits,
+// or a maxTokens of zero or less, is returned whole.
+func SplitMarkdown(content string, maxTokens int) []string {
+	if maxTokens <= 0 || EstimateTokens(content) <= maxTokens {
+		return []string{content}
+	}
+
+	var chunks []string
+	for _, chunk := range pack(content, maxTokens*runesPerToken, splitSections, splitParagraphs, splitLines, splitRunes) {
+		if chunk = strings.TrimSpace(chunk); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+	}
+	return chunks
+}
+
+// splitter cuts text into pieces that concatenate back to it. splitRunes,
+// the last resort, is the only one that needs maxRunes.
+type splitter func(text string, maxRunes int) []string
+
+// pack cuts text with the first splitter and joins consecutive pieces into
+// chunks of at most maxRunes, cutting pieces that are too long on their own
+// with the next splitter.
+func pack(text string, maxRunes int, splitters ...splitter) []string {
+	if utf8.RuneCountInString(text) <= maxRunes || len(splitters) == 0 {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentRunes := 0
+	for _, piece := range splitters[0](text, maxRunes) {
+		n := utf8.RuneCountInString(piece)
+		if currentRunes+n <= maxRunes {
+			current.WriteString(piece)
+			currentRunes += n
+			continue
+		}
+		if currentRunes > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentRunes = 0
+		}
+		if n <= maxRunes {
+			current.WriteString(piece)
+			currentRunes = n
+			continue
+		}
+		// Start the next chunk with the tail of the piece, which the
+		// pieces after it may still fit with.
+		sub := pack(piece, maxRunes, splitters[1:]...)
+		chunks = append(chunks, sub[:len(sub)-1]...)
+		current.WriteString(sub[len(sub)-1])
+		currentRunes = utf8.RuneCountInString(sub[len(sub)-1])
+	}
+	if currentRunes > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// splitSections cuts Markdown before each ATX heading outside fenced code.
+func splitSections(text string, _ int) []string {
+	var sections []string
+	start, offset := 0, 0
+	inFence := false
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		} else if !inFence && isHeading(trimmed) && offset > start {
+			sections = append(sections, text[start:offset])
+			start = offset
+		}
+		offset += len(line)
+	}
+	return append(sections, text[start:])
+}
+
+// isHeading reports whether line is an ATX heading such as "## Results".
+func isHeading(line string) bool {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	return level >= 1 && level <= 6 && (len(line) == level || line[level] == ' ')
+}
+
+func splitParagraphs(text string, _ int) []string {
+	return strings.SplitAfter(text, "\n\n")
+}
+
+func splitLines(text string, _ int) []string {
+	return strings.SplitAfter(text, "\n")
+}
+
+func splitRunes(text string, maxRunes int) []string {
+	var pieces []string
+	for text != "" {
+		end, n := 0, 0
+		for end < len(text) && n < maxRunes {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+			n++
+		}
+		pieces = append(pieces, text[:end])
+		text = text[end:]
+	}
+	return pieces
+}
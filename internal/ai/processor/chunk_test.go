@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("abc"))
+	assert.Equal(t, 2, EstimateTokens("abcde"))
+	assert.Equal(t, 1, EstimateTokens("日本語"))
+}
+
+func TestSplitMarkdown_ReturnsShortContentWhole(t *testing.T) {
+	content := "# Title\n\nShort article.\n"
+	assert.Equal(t, []string{content}, SplitMarkdown(content, 100))
+	assert.Equal(t, []string{content}, SplitMarkdown(content, 0))
+}
+
+func TestSplitMarkdown_BreaksAtHeadings(t *testing.T) {
+	intro := "# Paper\n\n" + strings.Repeat("intro ", 10) + "\n\n"
+	methods := "## Methods\n\n" + strings.Repeat("method ", 10) + "\n\n"
+	results := "## Results\n\n" + strings.Repeat("result ", 10) + "\n"
+
+	chunks := SplitMarkdown(intro+methods+results, 30)
+	require.Len(t, chunks, 3)
+	assert.True(t, strings.HasPrefix(chunks[0], "# Paper"))
+	assert.True(t, strings.HasPrefix(chunks[1], "## Methods"))
+	assert.True(t, strings.HasPrefix(chunks[2], "## Results"))
+
+	// Sections that fit together share a chunk.
+	chunks = SplitMarkdown(intro+methods+results, 50)
+	require.Len(t, chunks, 2)
+	assert.Contains(t, chunks[0], "## Methods")
+	assert.True(t, strings.HasPrefix(chunks[1], "## Results"))
+}
+
+func TestSplitMarkdown_IgnoresHeadingsInCodeBlocks(t *testing.T) {
+	code := "```bash\n# install\npip install model\n```\n"
+	content := "## Setup\n\n" + strings.Repeat("text ", 20) + "\n\n" + code + "\n## Usage\n\n" + strings.Repeat("more ", 20) + "\n"
+
+	chunks := SplitMarkdown(content, 40)
+	require.Len(t, chunks, 2)
+	assert.Contains(t, chunks[0], "# install")
+	assert.True(t, strings.HasPrefix(chunks[1], "## Usage"))
+}
+
+func TestSplitMarkdown_SplitsLongSections(t *testing.T) {
+	paragraph := strings.Repeat("word ", 30)
+	content := "## Transcript\n\n" + paragraph + "\n\n" + paragraph + "\n\n" + paragraph + "\n\n" + strings.Repeat("x", 500)
+
+	chunks := SplitMarkdown(content, 50)
+	require.Greater(t, len(chunks), 3)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, EstimateTokens(chunk), 50)
+	}
+	assert.Equal(t, strings.Count(content, "word"), strings.Count(strings.Join(chunks, ""), "word"))
+	assert.Equal(t, 500, strings.Count(strings.Join(chunks, ""), "x"))
+}
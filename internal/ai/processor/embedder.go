@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+)
+
+// Embedder turns texts into vectors whose cosine similarity reflects how
+// close the texts are in meaning. Embedders that hold a connection, such as
+// GeminiEmbedder, also implement io.Closer.
+type Embedder interface {
+	// Embed returns one vector per text, in the order of texts.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model names the embedding model. Vectors from different models are
+	// not comparable.
+	Model() string
+}
+
+// NewEmbedder returns the embedder selected by cfg.Provider.
+func NewEmbedder(ctx context.Context, cfg config.EmbeddingConfig) (Embedder, error) {
+	switch strings.ToLower(cfg.Provider) {
+	case "", "gemini":
+		return NewGeminiEmbedder(ctx, cfg.Model)
+	case "openai":
+		return NewOpenAIEmbedder(cfg.BaseURL, cfg.Model), nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider %q", cfg.Provider)
+	}
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// geminiEmbedBatchSize is the most texts sent in one batch request.
+const geminiEmbedBatchSize = 100
+
+// GeminiEmbedder embeds texts with a Gemini embedding model.
+type GeminiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+func NewGeminiEmbedder(ctx context.Context, model string) (*GeminiEmbedder, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return nil, errors.New("GEMINI_API_KEY environment variable is not set")
+	}
+	return newGeminiEmbedder(ctx, apiKey, model)
+}
+
+func newGeminiEmbedder(ctx context.Context, apiKey, model string) (*GeminiEmbedder, error) {
+	if model == "" {
+		model = "text-embedding-004"
+	}
+
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+	}
+
+	return &GeminiEmbedder{
+		client: client,
+		model:  model,
+	}, nil
+}
+
+// Close releases the connection of the Gemini client.
+func (ge *GeminiEmbedder) Close() error {
+	return ge.client.Close()
+}
+
+func (ge *GeminiEmbedder) Model() string {
+	return ge.model
+}
+
+func (ge *GeminiEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	model := ge.client.EmbeddingModel(ge.model)
+
+	vectors := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiEmbedBatchSize {
+		end := min(start+geminiEmbedBatchSize, len(texts))
+
+		batch := model.NewBatch()
+		for _, text := range texts[start:end] {
+			batch.AddContent(genai.Text(text))
+		}
+		resp, err := model.BatchEmbedContents(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed content: %w", err)
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("gemini returned %d embeddings for %d texts", len(resp.Embeddings), end-start)
+		}
+		for _, embedding := range resp.Embeddings {
+			vectors = append(vectors, embedding.Values)
+		}
+	}
+	return vectors, nil
+}
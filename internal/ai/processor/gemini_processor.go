@@ -2,12 +2,10 @@ package processor
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/robertguss/rss-agent-cli/internal/config"
@@ -27,7 +25,10 @@ func NewGeminiProcessor(ctx context.Context, model string) (*GeminiProcessor, er
 	if apiKey == "" {
 		return nil, errors.New("GEMINI_API_KEY environment variable is not set")
 	}
+	return newGeminiProcessor(ctx, apiKey, model)
+}
 
+func newGeminiProcessor(ctx context.Context, apiKey, model string) (*GeminiProcessor, error) {
 	if model == "" {
 		model = "gemini-1.5-flash"
 	}
@@ -44,59 +45,7 @@ func NewGeminiProcessor(ctx context.Context, model string) (*GeminiProcessor, er
 }
 
 func (gp *GeminiProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
-	ctx := context.Background()
-
-	model := gp.client.GenerativeModel(gp.model)
-
-	prompt := fmt.Sprintf(`Analyze this AI news article and return a JSON response with the following structure:
-{
-  "summary": "• Bullet point summary\n• Key points\n• Important details",
-  "entities": {
-    "organizations": ["Company1", "Company2"],
-    "products": ["Product1", "Model1"],
-    "people": ["Person1", "Person2"]
-  },
-  "topics": ["Topic1", "Topic2"],
-  "content_type": "Research Paper|Product Launch|News Article|Opinion Piece|Tutorial",
-  "story_group_id": "unique-identifier-for-deduplication"
-}
-
-Article content:
-%s`, content)
-
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
-	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, errors.New("no response from Gemini API")
-	}
-
-	responseText := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-
-	// Clean the response by removing markdown code block formatting
-	cleanedResponse := cleanJSONResponse(responseText)
-
-	var geminiResponse struct {
-		Summary      string   `json:"summary"`
-		Entities     Entities `json:"entities"`
-		Topics       []string `json:"topics"`
-		ContentType  string   `json:"content_type"`
-		StoryGroupID string   `json:"story_group_id"`
-	}
-
-	if err := json.Unmarshal([]byte(cleanedResponse), &geminiResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse Gemini response: %w", err)
-	}
-
-	return &AnalysisResult{
-		Summary:      geminiResponse.Summary,
-		Entities:     geminiResponse.Entities,
-		Topics:       geminiResponse.Topics,
-		ContentType:  geminiResponse.ContentType,
-		StoryGroupID: generateStoryGroupID(content),
-	}, nil
+	return gp.analyzeContentInternal(context.Background(), content)
 }
 
 func (gp *GeminiProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
@@ -122,70 +71,31 @@ func (gp *GeminiProcessor) AnalyzeContentWithRetry(ctx context.Context, content
 }
 
 func (gp *GeminiProcessor) analyzeContentInternal(ctx context.Context, content string) (*AnalysisResult, error) {
-	model := gp.client.GenerativeModel(gp.model)
-
-	prompt := fmt.Sprintf(`Analyze this AI news article and return a JSON response with the following structure:
-{
-  "summary": "• Bullet point summary\n• Key points\n• Important details",
-  "entities": {
-    "organizations": ["Company1", "Company2"],
-    "products": ["Product1", "Model1"],
-    "people": ["Person1", "Person2"]
-  },
-  "topics": ["Topic1", "Topic2"],
-  "content_type": "Research Paper|Product Launch|News Article|Opinion Piece|Tutorial",
-  "story_group_id": "unique-identifier-for-deduplication"
+	return runAnalysis(ctx, "Gemini", content, gp.generate)
 }
 
-Article content:
-%s`, content)
+// generate answers prompt with JSON constrained to the analysis schema.
+func (gp *GeminiProcessor) generate(ctx context.Context, prompt string) (string, error) {
+	model := gp.client.GenerativeModel(gp.model)
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = geminiAnalysisSchema()
 
+	start := time.Now()
 	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
-	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, errors.New("no response from Gemini API")
+		return "", fmt.Errorf("failed to generate content: %w", err)
 	}
 
-	responseText := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-
-	// Clean the response by removing markdown code block formatting
-	cleanedResponse := cleanJSONResponse(responseText)
-
-	var geminiResponse struct {
-		Summary      string   `json:"summary"`
-		Entities     Entities `json:"entities"`
-		Topics       []string `json:"topics"`
-		ContentType  string   `json:"content_type"`
-		StoryGroupID string   `json:"story_group_id"`
+	usage := Usage{Provider: "gemini", Model: gp.model, Latency: time.Since(start)}
+	if resp.UsageMetadata != nil {
+		usage.PromptTokens = int64(resp.UsageMetadata.PromptTokenCount)
+		usage.OutputTokens = int64(resp.UsageMetadata.CandidatesTokenCount)
 	}
+	recordUsage(ctx, usage)
 
-	if err := json.Unmarshal([]byte(cleanedResponse), &geminiResponse); err != nil {
-		return nil, fmt.Errorf("failed to parse Gemini response: %w", err)
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", errors.New("no response from Gemini API")
 	}
 
-	return &AnalysisResult{
-		Summary:      geminiResponse.Summary,
-		Entities:     geminiResponse.Entities,
-		Topics:       geminiResponse.Topics,
-		ContentType:  geminiResponse.ContentType,
-		StoryGroupID: generateStoryGroupID(content),
-	}, nil
-}
-
-func generateStoryGroupID(content string) string {
-	hash := sha256.Sum256([]byte(content))
-	return fmt.Sprintf("%x", hash)[:16]
-}
-
-// cleanJSONResponse removes markdown code block formatting from the response.
-func cleanJSONResponse(response string) string {
-	// Remove markdown code block markers
-	response = strings.TrimSpace(response)
-	response = strings.TrimPrefix(response, "```json")
-	response = strings.TrimPrefix(response, "```")
-	response = strings.TrimSuffix(response, "```")
-	return strings.TrimSpace(response)
+	return fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0]), nil
 }
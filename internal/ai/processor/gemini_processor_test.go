@@ -67,7 +67,6 @@ func TestGeminiProcessor_AnalyzeContent_Success(t *testing.T) {
 	require.NotNil(t, result)
 	assert.NotEmpty(t, result.Summary)
 	assert.NotEmpty(t, result.ContentType)
-	assert.NotEmpty(t, result.StoryGroupID)
 }
 
 func TestGeminiProcessor_AnalyzeContent_NetworkError(t *testing.T) {
@@ -151,29 +150,6 @@ func TestAnalysisResult_DatabaseCompatibility(t *testing.T) {
 	assert.Equal(t, result.Topics, topics)
 }
 
-func TestStoryGroupID_Deterministic(t *testing.T) {
-	content1 := "This is test content for hashing"
-	content2 := "This is test content for hashing"
-	content3 := "This is different content"
-
-	id1 := generateStoryGroupID(content1)
-	id2 := generateStoryGroupID(content2)
-	id3 := generateStoryGroupID(content3)
-
-	assert.Equal(t, id1, id2, "Same content should produce same story group ID")
-	assert.NotEqual(t, id1, id3, "Different content should produce different story group ID")
-	assert.NotEmpty(t, id1)
-	assert.NotEmpty(t, id3)
-}
-
-func TestStoryGroupID_Format(t *testing.T) {
-	content := "Test content for ID format validation"
-	id := generateStoryGroupID(content)
-
-	assert.NotEmpty(t, id)
-	assert.Regexp(t, "^[a-f0-9]+$", id, "Story group ID should be hexadecimal")
-}
-
 func TestGeminiProcessor_ImplementsInterface(t *testing.T) {
 	var processor AIProcessor = &GeminiProcessor{}
 	assert.NotNil(t, processor)
@@ -0,0 +1,186 @@
+package processor
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+//go:embed prompts/reduce.tmpl
+var reducePromptText string
+
+var reducePrompt = sync.OnceValue(func() *Prompt {
+	p, err := ParsePrompt("reduce", reducePromptText)
+	if err != nil {
+		panic(err)
+	}
+	return p
+})
+
+// AnalyzeChunked has ai analyze an article with prompt. Content too long for
+// one prompt, as configured by cfg.AI.Chunking, is split with SplitMarkdown
+// and each chunk analyzed on its own; the chunk summaries are then condensed
+// by one more call and their topics and entities merged with MergeAnalyses.
+// If condensing fails, the chunk summaries are joined instead. Content that
+// fits is analyzed with a single call.
+func AnalyzeChunked(ctx context.Context, ai AIProcessor, prompt *Prompt, data PromptData, cfg *config.Config) (*AnalysisResult, error) {
+	chunks := SplitMarkdown(data.Content, cfg.AI.Chunking.MaxTokens)
+	if len(chunks) == 1 {
+		return analyzeWithPrompt(ctx, ai, prompt, data, cfg)
+	}
+	if limit := cfg.AI.Chunking.MaxChunks; limit > 0 && len(chunks) > limit {
+		logging.Warn("ai_analysis", fmt.Sprintf("Analyzing the first %d of %d chunks of %s", limit, len(chunks), data.URL))
+		chunks = chunks[:limit]
+	}
+
+	parts := make([]*AnalysisResult, 0, len(chunks))
+	for i, chunk := range chunks {
+		part := data
+		part.Content = chunk
+		part.Part = i + 1
+		part.Parts = len(chunks)
+
+		result, err := analyzeWithPrompt(ctx, ai, prompt, part, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("analyze part %d of %d: %w", i+1, len(chunks), err)
+		}
+		parts = append(parts, result)
+	}
+
+	merged := MergeAnalyses(parts)
+	reduced, err := reduceSummaries(ctx, ai, data, parts, cfg)
+	if err != nil {
+		logging.Warn("ai_analysis", fmt.Sprintf("Failed to combine the part summaries of %s, joining them instead: %v", data.URL, err))
+		return merged, nil
+	}
+	merged.Summary = reduced.Summary
+	merged.ContentType = reduced.ContentType
+	return merged, nil
+}
+
+// analyzeWithPrompt renders prompt for data and has ai analyze data.Content
+// with it.
+func analyzeWithPrompt(ctx context.Context, ai AIProcessor, prompt *Prompt, data PromptData, cfg *config.Config) (*AnalysisResult, error) {
+	text, err := prompt.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withPromptVersion(WithPrompt(ctx, text), prompt.Version)
+	return ai.AnalyzeContentWithRetry(ctx, data.Content, cfg)
+}
+
+// reduceSummaries asks ai for an analysis of the whole article from the
+// summaries of its parts.
+func reduceSummaries(ctx context.Context, ai AIProcessor, data PromptData, parts []*AnalysisResult, cfg *config.Config) (*AnalysisResult, error) {
+	var b strings.Builder
+	for i, part := range parts {
+		fmt.Fprintf(&b, "Part %d of %d:\n%s\n\n", i+1, len(parts), part.Summary)
+	}
+	data.Content = strings.TrimSpace(b.String())
+	data.Part, data.Parts = 0, 0
+	return analyzeWithPrompt(ctx, ai, reducePrompt(), data, cfg)
+}
+
+// MergeAnalyses combines the analyses of the parts of an article. Topics
+// and entities named by more parts come first and duplicates differing only
+// in case are dropped, the content type of most parts wins, and the
+// summaries are joined; all within the limits Validate enforces.
+func MergeAnalyses(parts []*AnalysisResult) *AnalysisResult {
+	var summaries, contentTypes []string
+	var topics, organizations, products, people [][]string
+	for _, part := range parts {
+		summaries = append(summaries, part.Summary)
+		contentTypes = append(contentTypes, part.ContentType)
+		topics = append(topics, part.Topics)
+		organizations = append(organizations, part.Entities.Organizations)
+		products = append(products, part.Entities.Products)
+		people = append(people, part.Entities.People)
+	}
+
+	contentType := ""
+	if ranked := rankStrings([][]string{contentTypes}); len(ranked) > 0 {
+		contentType = ranked[0]
+	}
+
+	return &AnalysisResult{
+		Summary: joinSummaries(summaries),
+		Entities: Entities{
+			Organizations: firstN(rankStrings(organizations), maxEntities),
+			Products:      firstN(rankStrings(products), maxEntities),
+			People:        firstN(rankStrings(people), maxEntities),
+		},
+		Topics:      firstN(rankStrings(topics), maxTopics),
+		ContentType: contentType,
+	}
+}
+
+// rankStrings returns the distinct non-empty strings of lists, ignoring
+// case, ordered by how often they occur and then by first occurrence. The
+// spelling of the first occurrence is kept.
+func rankStrings(lists [][]string) []string {
+	var ranked []string
+	counts := make(map[string]int)
+	for _, list := range lists {
+		for _, s := range list {
+			if s == "" {
+				continue
+			}
+			key := strings.ToLower(s)
+			if counts[key] == 0 {
+				ranked = append(ranked, s)
+			}
+			counts[key]++
+		}
+	}
+
+	// A stable insertion sort keeps first occurrences in order among equals.
+	for i := 1; i < len(ranked); i++ {
+		for j := i; j > 0 && counts[strings.ToLower(ranked[j])] > counts[strings.ToLower(ranked[j-1])]; j-- {
+			ranked[j], ranked[j-1] = ranked[j-1], ranked[j]
+		}
+	}
+	return ranked
+}
+
+func firstN(list []string, n int) []string {
+	if len(list) > n {
+		return list[:n]
+	}
+	return list
+}
+
+// joinSummaries joins summaries line by line, stopping before the line that
+// would make the result longer than maxSummaryLength.
+func joinSummaries(summaries []string) string {
+	var b strings.Builder
+	length := 0
+	for _, summary := range summaries {
+		for _, line := range strings.Split(summary, "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			n := utf8.RuneCountInString(line)
+			if length > 0 {
+				n++
+			}
+			if length+n > maxSummaryLength {
+				if length == 0 {
+					return string([]rune(line)[:maxSummaryLength])
+				}
+				return b.String()
+			}
+			if length > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(line)
+			length += n
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,125 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// promptProcessor answers with answer and records the prompts it is sent.
+type promptProcessor struct {
+	answer  func(prompt string) (*AnalysisResult, error)
+	prompts []string
+}
+
+func (p *promptProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
+	return p.AnalyzeContentWithRetry(context.Background(), content, nil)
+}
+
+func (p *promptProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
+	prompt := PromptFrom(ctx)
+	p.prompts = append(p.prompts, prompt)
+	return p.answer(prompt)
+}
+
+func chunkingConfig(maxTokens, maxChunks int) *config.Config {
+	return &config.Config{AI: config.AIConfig{Chunking: config.ChunkConfig{MaxTokens: maxTokens, MaxChunks: maxChunks}}}
+}
+
+func longArticle() string {
+	var b strings.Builder
+	for _, section := range []string{"Introduction", "Method", "Results"} {
+		b.WriteString("## " + section + "\n\n" + strings.Repeat(strings.ToLower(section)+" ", 15) + "\n\n")
+	}
+	return b.String()
+}
+
+func TestAnalyzeChunked_AnalyzesShortContentOnce(t *testing.T) {
+	ai := &promptProcessor{answer: func(prompt string) (*AnalysisResult, error) {
+		return &AnalysisResult{Summary: "whole", ContentType: "News Article"}, nil
+	}}
+
+	result, err := AnalyzeChunked(context.Background(), ai, DefaultPrompt(), PromptData{Content: "Short."}, chunkingConfig(1000, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "whole", result.Summary)
+	require.Len(t, ai.prompts, 1)
+	assert.NotContains(t, ai.prompts[0], "This is part")
+}
+
+func TestAnalyzeChunked_MapsAndReduces(t *testing.T) {
+	ai := &promptProcessor{answer: func(prompt string) (*AnalysisResult, error) {
+		switch {
+		case strings.Contains(prompt, "Part summaries:"):
+			return &AnalysisResult{Summary: "• the whole paper", ContentType: "Research Paper", Topics: []string{"ignored"}}, nil
+		case strings.Contains(prompt, "## Introduction"):
+			return &AnalysisResult{Summary: "• intro", ContentType: "Research Paper", Topics: []string{"LLMs"}, Entities: Entities{Organizations: []string{"OpenAI"}}}, nil
+		case strings.Contains(prompt, "## Method"):
+			return &AnalysisResult{Summary: "• method", ContentType: "Tutorial", Topics: []string{"Training", "llms"}}, nil
+		default:
+			return &AnalysisResult{Summary: "• results", ContentType: "Research Paper", Topics: []string{"Benchmarks", "LLMs"}, Entities: Entities{Organizations: []string{"Google", "OpenAI"}}}, nil
+		}
+	}}
+
+	data := PromptData{Title: "Scaling Laws", Content: longArticle()}
+	result, err := AnalyzeChunked(context.Background(), ai, DefaultPrompt(), data, chunkingConfig(60, 0))
+	require.NoError(t, err)
+
+	require.Len(t, ai.prompts, 4)
+	assert.Contains(t, ai.prompts[0], "This is part 1 of 3")
+	assert.Contains(t, ai.prompts[2], "This is part 3 of 3")
+	assert.Contains(t, ai.prompts[3], `"Scaling Laws"`)
+	assert.Contains(t, ai.prompts[3], "Part 2 of 3:\n• method")
+
+	assert.Equal(t, "• the whole paper", result.Summary)
+	assert.Equal(t, "Research Paper", result.ContentType)
+	assert.Equal(t, []string{"LLMs", "Training", "Benchmarks"}, result.Topics)
+	assert.Equal(t, []string{"OpenAI", "Google"}, result.Entities.Organizations)
+}
+
+func TestAnalyzeChunked_JoinsSummariesWhenReduceFails(t *testing.T) {
+	ai := &promptProcessor{answer: func(prompt string) (*AnalysisResult, error) {
+		if strings.Contains(prompt, "Part summaries:") {
+			return nil, errors.New("quota exceeded")
+		}
+		return &AnalysisResult{Summary: "• a part", ContentType: "News Article"}, nil
+	}}
+
+	result, err := AnalyzeChunked(context.Background(), ai, DefaultPrompt(), PromptData{Content: longArticle()}, chunkingConfig(60, 2))
+	require.NoError(t, err)
+	assert.Len(t, ai.prompts, 3, "two chunks and the reduce call")
+	assert.Equal(t, "• a part\n• a part", result.Summary)
+	assert.Equal(t, "News Article", result.ContentType)
+}
+
+func TestAnalyzeChunked_FailsWhenAPartFails(t *testing.T) {
+	ai := &promptProcessor{answer: func(prompt string) (*AnalysisResult, error) {
+		if strings.Contains(prompt, "part 2 of") {
+			return nil, errors.New("timeout")
+		}
+		return &AnalysisResult{Summary: "ok", ContentType: "News Article"}, nil
+	}}
+
+	_, err := AnalyzeChunked(context.Background(), ai, DefaultPrompt(), PromptData{Content: longArticle()}, chunkingConfig(60, 0))
+	assert.ErrorContains(t, err, "analyze part 2 of 3: timeout")
+}
+
+func TestMergeAnalyses_StaysWithinLimits(t *testing.T) {
+	var parts []*AnalysisResult
+	for i := 0; i < 30; i++ {
+		parts = append(parts, &AnalysisResult{
+			Summary:     strings.Repeat("s", 100),
+			ContentType: "News Article",
+			Topics:      []string{string(rune('A' + i))},
+		})
+	}
+
+	merged := MergeAnalyses(parts)
+	assert.Len(t, merged.Topics, maxTopics)
+	assert.LessOrEqual(t, len(merged.Summary), maxSummaryLength)
+	assert.NoError(t, merged.Validate())
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIEmbedder_Embed(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-key")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/embeddings", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "nomic-embed-text", req.Model)
+		assert.Equal(t, []string{"first", "second"}, req.Input)
+
+		// Results may arrive out of order; index says where each belongs.
+		_, _ = w.Write([]byte(`{"data": [
+			{"index": 1, "embedding": [0, 1]},
+			{"index": 0, "embedding": [1, 0.5]}
+		]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder(server.URL+"/v1/", "nomic-embed-text")
+	assert.Equal(t, "nomic-embed-text", embedder.Model())
+
+	vectors, err := embedder.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0.5}, {0, 1}}, vectors)
+}
+
+func TestOpenAIEmbedder_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "model not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAIEmbedder(server.URL, "missing").Embed(context.Background(), []string{"text"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+	assert.Contains(t, err.Error(), "model not found")
+}
+
+func TestNewEmbedder(t *testing.T) {
+	embedder, err := NewEmbedder(context.Background(), config.EmbeddingConfig{Provider: "openai", BaseURL: "http://localhost:11434/v1"})
+	require.NoError(t, err)
+	assert.Equal(t, "text-embedding-3-small", embedder.Model())
+
+	_, err = NewEmbedder(context.Background(), config.EmbeddingConfig{Provider: "unknown"})
+	assert.Error(t, err)
+}
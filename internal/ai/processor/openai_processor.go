@@ -0,0 +1,190 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+	"github.com/robertguss/rss-agent-cli/pkg/retry"
+)
+
+// OpenAIProcessor analyzes articles through an OpenAI-compatible
+// /chat/completions endpoint, which OpenAI provides as well as local model
+// servers such as llama.cpp and Ollama.
+type OpenAIProcessor struct {
+	baseURL    string
+	model      string
+	apiKey     string
+	jsonMode   bool
+	jsonSchema bool
+	client     *http.Client
+}
+
+func NewOpenAIProcessor(cfg config.OpenAIConfig) (*OpenAIProcessor, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.New("openai base URL is not set")
+	}
+	if cfg.Model == "" {
+		return nil, errors.New("openai model is not set")
+	}
+
+	return &OpenAIProcessor{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		model:      cfg.Model,
+		apiKey:     cfg.APIKey,
+		jsonMode:   cfg.UseJSONMode(),
+		jsonSchema: cfg.JSONSchema,
+		client:     http.DefaultClient,
+	}, nil
+}
+
+func (op *OpenAIProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
+	return op.analyzeContentInternal(context.Background(), content)
+}
+
+func (op *OpenAIProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.NetworkTimeout)
+	defer cancel()
+
+	var result *AnalysisResult
+	err := retry.DoWithCallback(ctx, cfg.RetryConfig(), func() error {
+		var e error
+		result, e = op.analyzeContentInternal(ctx, content)
+		return e
+	}, func(attempt int, err error) {
+		logging.Retry("ai_analysis", attempt, err)
+	})
+
+	if err != nil {
+		wrappedErr := errs.Wrap("analyze content with openai", err)
+		logging.Error("ai_analysis", wrappedErr)
+		return nil, wrappedErr
+	}
+
+	return result, nil
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Messages       []chatMessage   `json:"messages"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+}
+
+type responseFormat struct {
+	Type       string      `json:"type"`
+	JSONSchema *jsonSchema `json:"json_schema,omitempty"`
+}
+
+type jsonSchema struct {
+	Name   string         `json:"name"`
+	Strict bool           `json:"strict"`
+	Schema map[string]any `json:"schema"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (op *OpenAIProcessor) analyzeContentInternal(ctx context.Context, content string) (*AnalysisResult, error) {
+	return runAnalysis(ctx, "openai", content, op.generate)
+}
+
+// generate sends prompt as a chat completion request and returns the answer.
+func (op *OpenAIProcessor) generate(ctx context.Context, prompt string) (string, error) {
+	request := chatRequest{
+		Model: op.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: "You analyze news articles and answer only with JSON."},
+			{Role: "user", Content: prompt},
+		},
+	}
+	switch {
+	case op.jsonSchema:
+		request.ResponseFormat = &responseFormat{
+			Type:       "json_schema",
+			JSONSchema: &jsonSchema{Name: "analysis_result", Strict: true, Schema: analysisJSONSchema()},
+		}
+	case op.jsonMode:
+		request.ResponseFormat = &responseFormat{Type: "json_object"}
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, op.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if op.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+op.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := op.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp)
+	}
+
+	var completion chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("failed to decode openai response: %w", err)
+	}
+	recordUsage(ctx, Usage{
+		Provider:     "openai",
+		Model:        op.model,
+		PromptTokens: completion.Usage.PromptTokens,
+		OutputTokens: completion.Usage.CompletionTokens,
+		Latency:      time.Since(start),
+	})
+	if len(completion.Choices) == 0 || completion.Choices[0].Message.Content == "" {
+		return "", errors.New("no response from openai API")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
+
+// statusError describes a failed completions request in the terms pkg/errs
+// classifies by, so that rate limits and server errors are retried and
+// other failures are not.
+func statusError(resp *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	detail := strings.TrimSpace(string(message))
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("openai rate limit exceeded (status %d): %s", resp.StatusCode, detail)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("openai api key rejected (status %d): %s", resp.StatusCode, detail)
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("openai server error (status %d): %s", resp.StatusCode, detail)
+	default:
+		return fmt.Errorf("openai request failed (status %d): %s", resp.StatusCode, detail)
+	}
+}
@@ -0,0 +1,176 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chatCompletionAnalysis = `{
+	"choices": [{
+		"message": {
+			"role": "assistant",
+			"content": "{\"summary\": \"• A new model\", \"entities\": {\"organizations\": [\"Meta\"], \"products\": [\"Llama 4\"]}, \"topics\": [\"LLMs\"], \"content_type\": \"Product Launch\"}"
+		}
+	}]
+}`
+
+func newTestOpenAIProcessor(t *testing.T, serverURL string, jsonMode bool) *OpenAIProcessor {
+	t.Helper()
+	p, err := NewOpenAIProcessor(config.OpenAIConfig{
+		BaseURL:  serverURL + "/v1/",
+		Model:    "llama3",
+		APIKey:   "test-key",
+		JSONMode: &jsonMode,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func testRetryConfig() *config.Config {
+	return &config.Config{
+		NetworkTimeout: 5 * time.Second,
+		MaxRetries:     2,
+		BackoffBaseMs:  1,
+		BackoffMaxMs:   5,
+	}
+}
+
+func TestOpenAIProcessor_AnalyzeContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "llama3", req.Model)
+		require.NotNil(t, req.ResponseFormat)
+		assert.Equal(t, "json_object", req.ResponseFormat.Type)
+		require.Len(t, req.Messages, 2)
+		assert.Contains(t, req.Messages[1].Content, "Meta released Llama 4.")
+
+		_, _ = w.Write([]byte(chatCompletionAnalysis))
+	}))
+	defer server.Close()
+
+	result, err := newTestOpenAIProcessor(t, server.URL, true).AnalyzeContent("Meta released Llama 4.")
+	require.NoError(t, err)
+	assert.Equal(t, "• A new model", result.Summary)
+	assert.Equal(t, []string{"Meta"}, result.Entities.Organizations)
+	assert.Equal(t, []string{"Llama 4"}, result.Entities.Products)
+	assert.Equal(t, []string{"LLMs"}, result.Topics)
+	assert.Equal(t, "Product Launch", result.ContentType)
+}
+
+func TestOpenAIProcessor_WithoutJSONMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.NotContains(t, req, "response_format")
+
+		// Without JSON mode, models tend to wrap their answer in a code fence.
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]any{
+				"role":    "assistant",
+				"content": "```json\n{\"summary\": \"fenced\", \"content_type\": \"News Article\"}\n```",
+			}}},
+		})
+	}))
+	defer server.Close()
+
+	result, err := newTestOpenAIProcessor(t, server.URL, false).AnalyzeContent("text")
+	require.NoError(t, err)
+	assert.Equal(t, "fenced", result.Summary)
+}
+
+func TestOpenAIProcessor_JSONSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.NotNil(t, req.ResponseFormat)
+		assert.Equal(t, "json_schema", req.ResponseFormat.Type)
+		require.NotNil(t, req.ResponseFormat.JSONSchema)
+		assert.True(t, req.ResponseFormat.JSONSchema.Strict)
+		assert.Contains(t, req.ResponseFormat.JSONSchema.Schema["properties"], "content_type")
+
+		_, _ = w.Write([]byte(chatCompletionAnalysis))
+	}))
+	defer server.Close()
+
+	p, err := NewOpenAIProcessor(config.OpenAIConfig{BaseURL: server.URL, Model: "gpt-4o-mini", JSONSchema: true})
+	require.NoError(t, err)
+
+	result, err := p.AnalyzeContent("text")
+	require.NoError(t, err)
+	assert.Equal(t, "Product Launch", result.ContentType)
+}
+
+func TestOpenAIProcessor_RepairsInvalidResponse(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		prompts = append(prompts, req.Messages[1].Content)
+
+		answer := `{"summary": "• A new model", "content_type": "Press Release"}`
+		if len(prompts) > 1 {
+			answer = `{"summary": "• A new model", "content_type": "News Article"}`
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": answer}}},
+		})
+	}))
+	defer server.Close()
+
+	result, err := newTestOpenAIProcessor(t, server.URL, true).AnalyzeContent("text")
+	require.NoError(t, err)
+	assert.Equal(t, "News Article", result.ContentType)
+	require.Len(t, prompts, 2)
+	assert.Contains(t, prompts[1], `content_type "Press Release" is not one of`)
+}
+
+func TestOpenAIProcessor_RetriesServerErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(chatCompletionAnalysis))
+	}))
+	defer server.Close()
+
+	result, err := newTestOpenAIProcessor(t, server.URL, true).AnalyzeContentWithRetry(context.Background(), "text", testRetryConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "Product Launch", result.ContentType)
+	assert.Equal(t, 2, requests)
+}
+
+func TestOpenAIProcessor_DoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Error(w, `{"error": {"message": "model not found"}}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := newTestOpenAIProcessor(t, server.URL, true).AnalyzeContentWithRetry(context.Background(), "text", testRetryConfig())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "model not found")
+	assert.Equal(t, 1, requests)
+}
+
+func TestOpenAIProcessor_ImplementsInterface(t *testing.T) {
+	var _ AIProcessor = &OpenAIProcessor{}
+
+	_, err := NewOpenAIProcessor(config.OpenAIConfig{Model: "llama3"})
+	assert.Error(t, err)
+}
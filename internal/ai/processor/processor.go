@@ -15,6 +15,17 @@ type AIProcessor interface {
 	AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error)
 }
 
+// New returns the processor for the providers in cfg: the one selected by
+// cfg.Provider, or those in cfg.Providers, routed and chained for fallback
+// as described by cfg.Routes.
+func New(ctx context.Context, cfg config.AIConfig) (AIProcessor, error) {
+	registry, err := NewRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return registry.Processor(ctx)
+}
+
 // Entities represents extracted entities from content analysis.
 type Entities struct {
 	Organizations []string `json:"organizations,omitempty"`
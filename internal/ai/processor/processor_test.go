@@ -1,9 +1,12 @@
 package processor
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
+	"github.com/robertguss/rss-agent-cli/internal/config"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -70,3 +73,15 @@ func TestAIProcessor_InterfaceExists(t *testing.T) {
 		var _ func(string) (*AnalysisResult, error) = processor.AnalyzeContent
 	}
 }
+
+func TestNew_SelectsProvider(t *testing.T) {
+	p, err := New(context.Background(), config.AIConfig{
+		Provider: "OpenAI",
+		OpenAI:   config.OpenAIConfig{BaseURL: "http://localhost:8080/v1", Model: "llama3"},
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &OpenAIProcessor{}, p)
+
+	_, err = New(context.Background(), config.AIConfig{Provider: "unknown"})
+	assert.ErrorContains(t, err, `unsupported AI provider "unknown"`)
+}
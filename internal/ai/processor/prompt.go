@@ -0,0 +1,168 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+)
+
+// DefaultPromptName names the built-in analysis prompt.
+const DefaultPromptName = "analysis"
+
+//go:embed prompts/analysis.tmpl
+var defaultPromptText string
+
+var defaultPrompt = sync.OnceValue(func() *Prompt {
+	p, err := ParsePrompt(DefaultPromptName, defaultPromptText)
+	if err != nil {
+		panic(err)
+	}
+	return p
+})
+
+// PromptData is what prompt templates are executed with. When a long
+// article is analyzed in chunks, Content is one chunk and Part counts the
+// chunks from 1 to Parts; otherwise Parts is zero.
+type PromptData struct {
+	Title        string
+	URL          string
+	Source       string
+	Content      string
+	Part         int
+	Parts        int
+	ContentTypes []string // filled in by Render
+}
+
+// Prompt is a text/template that asks a model to analyze an article. Besides
+// the fields of PromptData, templates can use join, the strings.Join
+// function. Version is a hash of the template text, so it changes whenever
+// the template does.
+type Prompt struct {
+	Name    string
+	Version string
+	tmpl    *template.Template
+}
+
+// DefaultPrompt returns the built-in analysis prompt.
+func DefaultPrompt() *Prompt {
+	return defaultPrompt()
+}
+
+// ParsePrompt parses a prompt template.
+func ParsePrompt(name, text string) (*Prompt, error) {
+	tmpl, err := template.New(name).
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	return &Prompt{
+		Name:    name,
+		Version: hex.EncodeToString(sum[:])[:12],
+		tmpl:    tmpl,
+	}, nil
+}
+
+// LoadPrompt reads and parses the prompt template at path, which is named
+// after the file without its extension.
+func LoadPrompt(path string) (*Prompt, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read prompt: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParsePrompt(name, string(text))
+}
+
+// Render executes the template for an article.
+func (p *Prompt) Render(data PromptData) (string, error) {
+	data.ContentTypes = ContentTypes
+
+	var b strings.Builder
+	if err := p.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render prompt %s: %w", p.Name, err)
+	}
+	return b.String(), nil
+}
+
+// Prompts loads the prompt templates named in the config and keeps them for
+// reuse. A nil *Prompts loads templates on every call.
+type Prompts struct {
+	mu     sync.Mutex
+	loaded map[string]*Prompt
+}
+
+func NewPrompts() *Prompts {
+	return &Prompts{loaded: make(map[string]*Prompt)}
+}
+
+// For returns the prompt for source's articles: the template configured for
+// the source, else the one configured for all sources, else the default.
+func (ps *Prompts) For(cfg *config.Config, source config.Source) (*Prompt, error) {
+	path := cfg.PromptFor(source)
+	if path == "" {
+		return DefaultPrompt(), nil
+	}
+	if ps == nil {
+		return LoadPrompt(path)
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if p, ok := ps.loaded[path]; ok {
+		return p, nil
+	}
+	p, err := LoadPrompt(path)
+	if err != nil {
+		return nil, err
+	}
+	ps.loaded[path] = p
+	return p, nil
+}
+
+type promptKey struct{}
+
+// WithPrompt returns a context that has processors send prompt, a rendered
+// Prompt, instead of rendering the default prompt for the content they are
+// given.
+func WithPrompt(ctx context.Context, prompt string) context.Context {
+	return context.WithValue(ctx, promptKey{}, prompt)
+}
+
+// PromptFrom returns the prompt set by WithPrompt, or "" if there is none.
+func PromptFrom(ctx context.Context) string {
+	prompt, _ := ctx.Value(promptKey{}).(string)
+	return prompt
+}
+
+type promptVersionKey struct{}
+
+// withPromptVersion records the version of the Prompt that the prompt set by
+// WithPrompt was rendered from, for CachingProcessor.
+func withPromptVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, promptVersionKey{}, version)
+}
+
+// promptVersion returns the version of the prompt processors will send with
+// ctx, and false if it is not known: when WithPrompt set a prompt without
+// withPromptVersion.
+func promptVersion(ctx context.Context) (string, bool) {
+	if version, _ := ctx.Value(promptVersionKey{}).(string); version != "" {
+		return version, true
+	}
+	if PromptFrom(ctx) == "" {
+		return DefaultPrompt().Version, true
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPrompt(t *testing.T) {
+	prompt := DefaultPrompt()
+	assert.Equal(t, DefaultPromptName, prompt.Name)
+	assert.Len(t, prompt.Version, 12)
+
+	text, err := prompt.Render(PromptData{Content: "Meta released Llama 4."})
+	require.NoError(t, err)
+	assert.Contains(t, text, `"content_type": "Research Paper|Product Launch|News Article|Opinion Piece|Tutorial"`)
+	assert.Contains(t, text, "Article content:\nMeta released Llama 4.")
+}
+
+func TestParsePrompt_VersionFollowsText(t *testing.T) {
+	a, err := ParsePrompt("a", "Summarize {{.Title}}")
+	require.NoError(t, err)
+	b, err := ParsePrompt("b", "Summarize {{.Title}}")
+	require.NoError(t, err)
+	c, err := ParsePrompt("a", "Summarize {{.Title}} briefly")
+	require.NoError(t, err)
+
+	assert.Equal(t, a.Version, b.Version)
+	assert.NotEqual(t, a.Version, c.Version)
+
+	_, err = ParsePrompt("broken", "{{.Title")
+	assert.ErrorContains(t, err, "parse prompt broken")
+}
+
+func TestPrompts_For(t *testing.T) {
+	dir := t.TempDir()
+	globalPath := filepath.Join(dir, "global.tmpl")
+	sourcePath := filepath.Join(dir, "papers.tmpl")
+	require.NoError(t, os.WriteFile(globalPath, []byte("Global: {{.Source}}"), 0644))
+	require.NoError(t, os.WriteFile(sourcePath, []byte("Paper: {{.Title}} ({{.URL}})"), 0644))
+
+	cfg := &config.Config{AI: config.AIConfig{Prompt: globalPath}}
+	prompts := NewPrompts()
+
+	prompt, err := prompts.For(cfg, config.Source{Name: "Blog"})
+	require.NoError(t, err)
+	assert.Equal(t, "global", prompt.Name)
+	text, err := prompt.Render(PromptData{Source: "Blog"})
+	require.NoError(t, err)
+	assert.Equal(t, "Global: Blog", text)
+
+	prompt, err = prompts.For(cfg, config.Source{Name: "arXiv", Prompt: sourcePath})
+	require.NoError(t, err)
+	assert.Equal(t, "papers", prompt.Name)
+	text, err = prompt.Render(PromptData{Title: "Attention", URL: "https://arxiv.org/abs/1"})
+	require.NoError(t, err)
+	assert.Equal(t, "Paper: Attention (https://arxiv.org/abs/1)", text)
+
+	prompt, err = prompts.For(&config.Config{}, config.Source{Name: "Blog"})
+	require.NoError(t, err)
+	assert.Same(t, DefaultPrompt(), prompt)
+
+	_, err = prompts.For(&config.Config{}, config.Source{Prompt: filepath.Join(dir, "missing.tmpl")})
+	assert.Error(t, err)
+}
+
+func TestRunAnalysis_UsesPromptFromContext(t *testing.T) {
+	var sent string
+	generate := func(ctx context.Context, prompt string) (string, error) {
+		sent = prompt
+		return `{"summary": "s", "content_type": "Tutorial"}`, nil
+	}
+
+	_, err := runAnalysis(WithPrompt(context.Background(), "custom prompt"), "test", "content", generate)
+	require.NoError(t, err)
+	assert.Equal(t, "custom prompt", sent)
+}
@@ -0,0 +1,351 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// Tasks that providers can be routed to in config.AIConfig.Routes.
+const (
+	TaskSummarize = "summarize"
+	TaskClassify  = "classify"
+	TaskEmbed     = "embed"
+)
+
+// Registry holds the AI providers from the config by name and the order in
+// which they are tried for each task.
+type Registry struct {
+	providers map[string]config.ProviderConfig
+	order     []string
+	routes    map[string][]string
+	fallback  config.EmbeddingConfig
+}
+
+// NewRegistry validates the providers and routes in cfg.
+func NewRegistry(cfg config.AIConfig) (*Registry, error) {
+	r := &Registry{
+		providers: make(map[string]config.ProviderConfig),
+		routes:    make(map[string][]string),
+		fallback:  cfg.Embeddings,
+	}
+
+	for _, p := range cfg.Registry() {
+		if p.Name == "" {
+			return nil, errors.New("AI provider without a name")
+		}
+		if _, ok := r.providers[p.Name]; ok {
+			return nil, fmt.Errorf("AI provider %q is defined twice", p.Name)
+		}
+		switch strings.ToLower(p.Type) {
+		case "", "gemini", "openai", "mock":
+		default:
+			return nil, fmt.Errorf("unsupported AI provider %q", p.Type)
+		}
+		r.providers[p.Name] = p
+		r.order = append(r.order, p.Name)
+	}
+
+	for task, names := range cfg.Routes {
+		task = strings.ToLower(task)
+		if task != TaskSummarize && task != TaskClassify && task != TaskEmbed {
+			return nil, fmt.Errorf("unknown AI task %q in routes", task)
+		}
+		for _, name := range names {
+			if _, ok := r.providers[name]; !ok {
+				return nil, fmt.Errorf("route %q names unknown AI provider %q", task, name)
+			}
+		}
+		r.routes[task] = names
+	}
+	return r, nil
+}
+
+// NewRoutedEmbedder returns the embedder for the embed task in cfg.
+func NewRoutedEmbedder(ctx context.Context, cfg config.AIConfig) (Embedder, error) {
+	registry, err := NewRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return registry.Embedder(ctx)
+}
+
+// Chain returns the names of the providers used for task, in the order
+// they are tried.
+func (r *Registry) Chain(task string) []string {
+	if names, ok := r.routes[task]; ok && len(names) > 0 {
+		return names
+	}
+	if task == TaskEmbed {
+		return nil
+	}
+	return r.order
+}
+
+// Processor returns the processor that analyzes articles. When summarize
+// and classify are routed to different providers, each is asked for the
+// full analysis and only its part of the answer is kept, so every article
+// costs two analysis calls. Routes that differ only in providers that
+// cannot be created, and so resolve to the same chain, use one call.
+func (r *Registry) Processor(ctx context.Context) (AIProcessor, error) {
+	summarize, summarizeNames, err := r.chainProcessor(ctx, r.Chain(TaskSummarize))
+	if err != nil {
+		return nil, err
+	}
+	if slices.Equal(r.Chain(TaskSummarize), r.Chain(TaskClassify)) {
+		return summarize, nil
+	}
+
+	classify, classifyNames, err := r.chainProcessor(ctx, r.Chain(TaskClassify))
+	if err != nil {
+		return nil, err
+	}
+	if slices.Equal(summarizeNames, classifyNames) {
+		return summarize, nil
+	}
+	logging.Info("ai_provider", fmt.Sprintf("summarize (%s) and classify (%s) are routed to different providers: each article is analyzed twice",
+		strings.Join(summarizeNames, ", "), strings.Join(classifyNames, ", ")))
+	return &routedProcessor{summarize: summarize, classify: classify}, nil
+}
+
+// Models identifies the models that analyze articles, as "type/model" in
+// the order they are tried, followed after "|" by those of the classify
+// task when it is routed differently. Cached analyses are keyed by it.
+func (r *Registry) Models() string {
+	models := r.models(r.Chain(TaskSummarize))
+	if classify := r.Chain(TaskClassify); !slices.Equal(r.Chain(TaskSummarize), classify) {
+		models += "|" + r.models(classify)
+	}
+	return models
+}
+
+func (r *Registry) models(names []string) string {
+	var models []string
+	for _, name := range names {
+		p := r.providers[name]
+		providerType := strings.ToLower(p.Type)
+		if providerType == "" {
+			providerType = "gemini"
+		}
+		models = append(models, providerType+"/"+p.Model)
+	}
+	return strings.Join(models, ",")
+}
+
+// Embedder returns the embedder for the embed task: the first provider on
+// its route that can be created, or the one described by cfg.Embeddings
+// when the task is not routed. Unlike analysis, embedding does not fall
+// back on request errors, since vectors from different models cannot be
+// compared.
+func (r *Registry) Embedder(ctx context.Context) (Embedder, error) {
+	names := r.Chain(TaskEmbed)
+	if len(names) == 0 {
+		return NewEmbedder(ctx, r.fallback)
+	}
+
+	var errList []error
+	for _, name := range names {
+		embedder, err := newProviderEmbedder(ctx, r.providers[name])
+		if err == nil {
+			return embedder, nil
+		}
+		logging.Warn("ai_provider", fmt.Sprintf("skipping provider %s: %v", name, err))
+		errList = append(errList, fmt.Errorf("%s: %w", name, err))
+	}
+	return nil, errors.Join(errList...)
+}
+
+// chainProcessor creates the providers in names, skipping those that cannot
+// be created, such as ones without credentials, as long as one can. It
+// returns the names of the providers created along with the processor.
+func (r *Registry) chainProcessor(ctx context.Context, names []string) (AIProcessor, []string, error) {
+	chain := &FallbackProcessor{}
+	var errList []error
+	for _, name := range names {
+		p, err := newProviderProcessor(ctx, r.providers[name])
+		if err != nil {
+			if len(names) == 1 {
+				return nil, nil, err
+			}
+			logging.Warn("ai_provider", fmt.Sprintf("skipping provider %s: %v", name, err))
+			errList = append(errList, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		chain.names = append(chain.names, name)
+		chain.processors = append(chain.processors, p)
+	}
+
+	switch len(chain.processors) {
+	case 0:
+		return nil, nil, errors.Join(errList...)
+	case 1:
+		return chain.processors[0], chain.names, nil
+	default:
+		return chain, chain.names, nil
+	}
+}
+
+func newProviderProcessor(ctx context.Context, p config.ProviderConfig) (AIProcessor, error) {
+	switch strings.ToLower(p.Type) {
+	case "", "gemini":
+		apiKey, err := providerKey(p, "GEMINI_API_KEY", true)
+		if err != nil {
+			return nil, err
+		}
+		return newGeminiProcessor(ctx, apiKey, p.Model)
+	case "openai":
+		apiKey, _ := providerKey(p, "OPENAI_API_KEY", false)
+		model := p.Model
+		if model == "" {
+			model = "gpt-4o-mini"
+		}
+		baseURL := p.BaseURL
+		if baseURL == "" {
+			baseURL = "https://api.openai.com/v1"
+		}
+		return NewOpenAIProcessor(config.OpenAIConfig{
+			BaseURL:    baseURL,
+			Model:      model,
+			APIKey:     apiKey,
+			JSONMode:   p.JSONMode,
+			JSONSchema: p.JSONSchema,
+		})
+	case "mock":
+		return MockProcessor{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported AI provider %q", p.Type)
+	}
+}
+
+func newProviderEmbedder(ctx context.Context, p config.ProviderConfig) (Embedder, error) {
+	switch strings.ToLower(p.Type) {
+	case "", "gemini":
+		apiKey, err := providerKey(p, "GEMINI_API_KEY", true)
+		if err != nil {
+			return nil, err
+		}
+		return newGeminiEmbedder(ctx, apiKey, p.Model)
+	case "openai":
+		embedder := NewOpenAIEmbedder(p.BaseURL, p.Model)
+		embedder.apiKey, _ = providerKey(p, "OPENAI_API_KEY", false)
+		return embedder, nil
+	default:
+		return nil, fmt.Errorf("unsupported embedding provider %q", p.Type)
+	}
+}
+
+// providerKey returns the API key of p, from the config or from the
+// environment variable it names, defaultEnv by default.
+func providerKey(p config.ProviderConfig, defaultEnv string, required bool) (string, error) {
+	if p.APIKey != "" {
+		return p.APIKey, nil
+	}
+	env := p.APIKeyEnv
+	if env == "" {
+		env = defaultEnv
+	}
+	apiKey := os.Getenv(env)
+	if apiKey == "" && required {
+		return "", fmt.Errorf("%s environment variable is not set", env)
+	}
+	return apiKey, nil
+}
+
+// FallbackProcessor tries a chain of providers in order. A provider is
+// given up on for the next one when it fails with an error that retrying
+// does not fix or with a quota error, or, from AnalyzeContentWithRetry, with
+// any error, as the provider has used up its retries by then: a local server
+// that is down or a service failing with 5xx errors is skipped. Other errors
+// from AnalyzeContent end the chain and are left to the caller to retry.
+type FallbackProcessor struct {
+	names      []string
+	processors []AIProcessor
+}
+
+func (fp *FallbackProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
+	return fp.run(context.Background(), false, func(p AIProcessor) (*AnalysisResult, error) {
+		return p.AnalyzeContent(content)
+	})
+}
+
+func (fp *FallbackProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
+	return fp.run(ctx, true, func(p AIProcessor) (*AnalysisResult, error) {
+		return p.AnalyzeContentWithRetry(ctx, content, cfg)
+	})
+}
+
+// run analyzes with each processor in turn until one succeeds; retried tells
+// whether analyze has already retried the errors it returns.
+func (fp *FallbackProcessor) run(ctx context.Context, retried bool, analyze func(AIProcessor) (*AnalysisResult, error)) (*AnalysisResult, error) {
+	var err error
+	for i, p := range fp.processors {
+		var result *AnalysisResult
+		result, err = analyze(p)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil || !shouldFallBack(err, retried) {
+			return nil, err
+		}
+		if i+1 < len(fp.processors) {
+			logging.Warn("ai_fallback", fmt.Sprintf("provider %s failed, trying %s: %v", fp.names[i], fp.names[i+1], err))
+		}
+	}
+	return nil, err
+}
+
+func shouldFallBack(err error, retried bool) bool {
+	return retried || !errs.IsRetryable(err) || errs.IsQuota(err)
+}
+
+// routedProcessor takes the summary from one processor and the entities,
+// topics and content type from another. Both are asked for the full
+// analysis, so it makes two calls per analysis.
+type routedProcessor struct {
+	summarize AIProcessor
+	classify  AIProcessor
+}
+
+func (rp *routedProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
+	return rp.run(func(p AIProcessor) (*AnalysisResult, error) {
+		return p.AnalyzeContent(content)
+	})
+}
+
+func (rp *routedProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
+	return rp.run(func(p AIProcessor) (*AnalysisResult, error) {
+		return p.AnalyzeContentWithRetry(ctx, content, cfg)
+	})
+}
+
+func (rp *routedProcessor) run(analyze func(AIProcessor) (*AnalysisResult, error)) (*AnalysisResult, error) {
+	summary, err := analyze(rp.summarize)
+	if err != nil {
+		return nil, err
+	}
+	result, err := analyze(rp.classify)
+	if err != nil {
+		return nil, err
+	}
+	result.Summary = summary.Summary
+	return result, nil
+}
+
+// MockProcessor answers every request with a fixed summary, for trying
+// out the pipeline without calling a provider.
+type MockProcessor struct{}
+
+func (MockProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
+	return &AnalysisResult{Summary: "mock summary"}, nil
+}
+
+func (MockProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
+	return &AnalysisResult{Summary: "mock summary with retry"}, nil
+}
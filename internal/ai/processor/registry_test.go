@@ -0,0 +1,202 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubProcessor struct {
+	result *AnalysisResult
+	err    error
+	calls  int
+}
+
+func (s *stubProcessor) AnalyzeContent(content string) (*AnalysisResult, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func (s *stubProcessor) AnalyzeContentWithRetry(ctx context.Context, content string, cfg *config.Config) (*AnalysisResult, error) {
+	return s.AnalyzeContent(content)
+}
+
+func TestNewRegistry_Validates(t *testing.T) {
+	_, err := NewRegistry(config.AIConfig{Providers: []config.ProviderConfig{
+		{Name: "a", Type: "mock"}, {Name: "a", Type: "mock"},
+	}})
+	assert.ErrorContains(t, err, `"a" is defined twice`)
+
+	_, err = NewRegistry(config.AIConfig{Providers: []config.ProviderConfig{{Name: "a", Type: "claude"}}})
+	assert.ErrorContains(t, err, `unsupported AI provider "claude"`)
+
+	_, err = NewRegistry(config.AIConfig{
+		Providers: []config.ProviderConfig{{Name: "a", Type: "mock"}},
+		Routes:    map[string][]string{"summarize": {"b"}},
+	})
+	assert.ErrorContains(t, err, `unknown AI provider "b"`)
+
+	_, err = NewRegistry(config.AIConfig{
+		Providers: []config.ProviderConfig{{Name: "a", Type: "mock"}},
+		Routes:    map[string][]string{"translate": {"a"}},
+	})
+	assert.ErrorContains(t, err, `unknown AI task "translate"`)
+}
+
+func TestRegistry_Chain(t *testing.T) {
+	registry, err := NewRegistry(config.AIConfig{
+		Providers: []config.ProviderConfig{{Name: "a", Type: "mock"}, {Name: "b", Type: "mock"}},
+		Routes:    map[string][]string{"Classify": {"b"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, registry.Chain(TaskSummarize))
+	assert.Equal(t, []string{"b"}, registry.Chain(TaskClassify))
+	assert.Empty(t, registry.Chain(TaskEmbed))
+}
+
+func TestRegistry_Models(t *testing.T) {
+	registry, err := NewRegistry(config.AIConfig{Provider: "gemini", GeminiModel: "gemini-2.5-flash"})
+	require.NoError(t, err)
+	assert.Equal(t, "gemini/gemini-2.5-flash", registry.Models())
+
+	registry, err = NewRegistry(config.AIConfig{
+		Providers: []config.ProviderConfig{
+			{Name: "local", Type: "openai", Model: "llama3.1"},
+			{Name: "cloud", Model: "gemini-2.5-flash"},
+		},
+		Routes: map[string][]string{"classify": {"cloud"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "openai/llama3.1,gemini/gemini-2.5-flash|gemini/gemini-2.5-flash", registry.Models())
+}
+
+func TestRegistry_ProcessorSkipsProvidersWithoutCredentials(t *testing.T) {
+	t.Setenv("MISSING_KEY", "")
+	registry, err := NewRegistry(config.AIConfig{Providers: []config.ProviderConfig{
+		{Name: "cloud", Type: "gemini", APIKeyEnv: "MISSING_KEY"},
+		{Name: "mock", Type: "mock"},
+	}})
+	require.NoError(t, err)
+
+	p, err := registry.Processor(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, MockProcessor{}, p)
+
+	registry, err = NewRegistry(config.AIConfig{Providers: []config.ProviderConfig{
+		{Name: "cloud", Type: "gemini", APIKeyEnv: "MISSING_KEY"},
+	}})
+	require.NoError(t, err)
+	_, err = registry.Processor(context.Background())
+	assert.ErrorContains(t, err, "MISSING_KEY environment variable is not set")
+}
+
+func TestRegistry_RoutesTasksToProviders(t *testing.T) {
+	registry, err := NewRegistry(config.AIConfig{
+		Providers: []config.ProviderConfig{{Name: "a", Type: "mock"}, {Name: "b", Type: "mock"}},
+		Routes:    map[string][]string{"summarize": {"a"}, "classify": {"b"}},
+	})
+	require.NoError(t, err)
+
+	p, err := registry.Processor(context.Background())
+	require.NoError(t, err)
+	assert.IsType(t, &routedProcessor{}, p)
+
+	routed := &routedProcessor{
+		summarize: &stubProcessor{result: &AnalysisResult{Summary: "from a", ContentType: "Opinion"}},
+		classify:  &stubProcessor{result: &AnalysisResult{Summary: "from b", ContentType: "News", Topics: []string{"AI"}}},
+	}
+	result, err := routed.AnalyzeContent("content")
+	require.NoError(t, err)
+	assert.Equal(t, "from a", result.Summary)
+	assert.Equal(t, "News", result.ContentType)
+	assert.Equal(t, []string{"AI"}, result.Topics)
+}
+
+func TestRegistry_RoutesResolvingToSameProvidersAnalyzeOnce(t *testing.T) {
+	registry, err := NewRegistry(config.AIConfig{
+		Providers: []config.ProviderConfig{
+			{Name: "local", Type: "mock"},
+			{Name: "cloud", Type: "gemini", APIKeyEnv: "MISSING_KEY"},
+		},
+		Routes: map[string][]string{"summarize": {"cloud", "local"}, "classify": {"local"}},
+	})
+	require.NoError(t, err)
+
+	p, err := registry.Processor(context.Background())
+	require.NoError(t, err)
+	assert.IsType(t, MockProcessor{}, p, "without cloud both routes resolve to local")
+}
+
+func TestFallbackProcessor(t *testing.T) {
+	cfg := testRetryConfig()
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "non-retryable error", err: errs.Wrap("analyze", errors.New("gemini api key rejected"))},
+		{name: "quota error", err: errs.Wrap("analyze", errors.New("gemini quota exceeded"))},
+		{name: "retryable error after retries", err: errs.Wrap("analyze", errors.New("openai server error (status 503)"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := &stubProcessor{err: tt.err}
+			second := &stubProcessor{result: &AnalysisResult{Summary: "second"}}
+			fp := &FallbackProcessor{names: []string{"first", "second"}, processors: []AIProcessor{first, second}}
+
+			result, err := fp.AnalyzeContentWithRetry(context.Background(), "content", cfg)
+			require.NoError(t, err)
+			assert.Equal(t, "second", result.Summary)
+			assert.Equal(t, 1, first.calls)
+			assert.Equal(t, 1, second.calls)
+		})
+	}
+}
+
+func TestFallbackProcessor_LeavesRetryableErrorsToCallerWithoutRetry(t *testing.T) {
+	first := &stubProcessor{err: errs.Wrap("analyze", errors.New("openai server error (status 503)"))}
+	second := &stubProcessor{result: &AnalysisResult{Summary: "second"}}
+	fp := &FallbackProcessor{names: []string{"first", "second"}, processors: []AIProcessor{first, second}}
+
+	_, err := fp.AnalyzeContent("content")
+	assert.ErrorContains(t, err, "server error")
+	assert.Zero(t, second.calls)
+}
+
+func TestRegistry_FallsBackWhenProviderIsDown(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	registry, err := NewRegistry(config.AIConfig{Providers: []config.ProviderConfig{
+		{Name: "local", Type: "openai", BaseURL: server.URL, Model: "llama3.1"},
+		{Name: "mock", Type: "mock"},
+	}})
+	require.NoError(t, err)
+	p, err := registry.Processor(context.Background())
+	require.NoError(t, err)
+
+	result, err := p.AnalyzeContentWithRetry(context.Background(), "content", testRetryConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "mock summary with retry", result.Summary)
+}
+
+func TestFallbackProcessor_ReturnsLastError(t *testing.T) {
+	fp := &FallbackProcessor{
+		names: []string{"first", "second"},
+		processors: []AIProcessor{
+			&stubProcessor{err: errors.New("first failed")},
+			&stubProcessor{err: errors.New("second failed")},
+		},
+	}
+
+	_, err := fp.AnalyzeContent("content")
+	assert.EqualError(t, err, "second failed")
+}
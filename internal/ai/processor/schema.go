@@ -0,0 +1,136 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// ContentTypes are the values allowed for AnalysisResult.ContentType.
+var ContentTypes = []string{
+	"Research Paper",
+	"Product Launch",
+	"News Article",
+	"Opinion Piece",
+	"Tutorial",
+}
+
+// Limits on the size of an analysis, which keep a rambling model from
+// filling the database.
+const (
+	maxSummaryLength = 2000
+	maxTopics        = 10
+	maxTopicLength   = 60
+	maxEntities      = 25
+	maxEntityLength  = 100
+)
+
+// Validate reports everything that keeps a from being a usable analysis:
+// a missing summary or content type, a content type outside ContentTypes,
+// and summaries, topics or entities over the size limits.
+func (a *AnalysisResult) Validate() error {
+	var problems []string
+
+	if strings.TrimSpace(a.Summary) == "" {
+		problems = append(problems, "summary is required")
+	} else if n := utf8.RuneCountInString(a.Summary); n > maxSummaryLength {
+		problems = append(problems, fmt.Sprintf("summary is %d characters, at most %d are allowed", n, maxSummaryLength))
+	}
+
+	switch {
+	case a.ContentType == "":
+		problems = append(problems, "content_type is required")
+	case !slices.Contains(ContentTypes, a.ContentType):
+		problems = append(problems, fmt.Sprintf("content_type %q is not one of %s", a.ContentType, strings.Join(ContentTypes, ", ")))
+	}
+
+	problems = append(problems, checkList("topics", a.Topics, maxTopics, maxTopicLength)...)
+	problems = append(problems, checkList("entities.organizations", a.Entities.Organizations, maxEntities, maxEntityLength)...)
+	problems = append(problems, checkList("entities.products", a.Entities.Products, maxEntities, maxEntityLength)...)
+	problems = append(problems, checkList("entities.people", a.Entities.People, maxEntities, maxEntityLength)...)
+
+	if len(problems) > 0 {
+		return errors.New("analysis failed validation: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func checkList(field string, values []string, maxItems, maxLength int) []string {
+	var problems []string
+	if len(values) > maxItems {
+		problems = append(problems, fmt.Sprintf("%s has %d entries, at most %d are allowed", field, len(values), maxItems))
+	}
+	for _, value := range values {
+		if utf8.RuneCountInString(value) > maxLength {
+			problems = append(problems, fmt.Sprintf("%s entry %q is longer than %d characters", field, value, maxLength))
+		}
+	}
+	return problems
+}
+
+// canonicalContentType returns the entry of ContentTypes that contentType
+// names regardless of case and surrounding space, or contentType itself if
+// there is none.
+func canonicalContentType(contentType string) string {
+	trimmed := strings.TrimSpace(contentType)
+	for _, allowed := range ContentTypes {
+		if strings.EqualFold(trimmed, allowed) {
+			return allowed
+		}
+	}
+	return contentType
+}
+
+// analysisJSONSchema describes an AnalysisResult for providers that take a
+// JSON schema for structured output. All properties are required and no
+// others are allowed, as strict schema modes demand; limits on lengths are
+// left to Validate since not every provider supports them.
+func analysisJSONSchema() map[string]any {
+	stringList := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"summary": map[string]any{"type": "string"},
+			"entities": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"organizations": stringList,
+					"products":      stringList,
+					"people":        stringList,
+				},
+				"required":             []string{"organizations", "products", "people"},
+				"additionalProperties": false,
+			},
+			"topics":       stringList,
+			"content_type": map[string]any{"type": "string", "enum": ContentTypes},
+		},
+		"required":             []string{"summary", "entities", "topics", "content_type"},
+		"additionalProperties": false,
+	}
+}
+
+// geminiAnalysisSchema is analysisJSONSchema in the form Gemini takes.
+func geminiAnalysisSchema() *genai.Schema {
+	stringList := &genai.Schema{Type: genai.TypeArray, Items: &genai.Schema{Type: genai.TypeString}}
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"summary": {Type: genai.TypeString},
+			"entities": {
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"organizations": stringList,
+					"products":      stringList,
+					"people":        stringList,
+				},
+			},
+			"topics":       stringList,
+			"content_type": {Type: genai.TypeString, Format: "enum", Enum: ContentTypes},
+		},
+		Required: []string{"summary", "entities", "topics", "content_type"},
+	}
+}
@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Usage describes one call to an AI provider: the tokens it was billed for
+// and how long it took.
+type Usage struct {
+	Provider     string
+	Model        string
+	PromptTokens int64
+	OutputTokens int64
+	Latency      time.Duration
+}
+
+// UsageRecorder collects the Usage of the calls processors make with a
+// context from WithUsageRecorder. It is safe for concurrent use.
+type UsageRecorder struct {
+	mu    sync.Mutex
+	calls []Usage
+}
+
+// Calls returns the usage recorded so far.
+func (r *UsageRecorder) Calls() []Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Usage(nil), r.calls...)
+}
+
+func (r *UsageRecorder) add(u Usage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, u)
+}
+
+type usageKey struct{}
+
+// WithUsageRecorder returns a context that has processors report each call
+// they make to r.
+func WithUsageRecorder(ctx context.Context, r *UsageRecorder) context.Context {
+	return context.WithValue(ctx, usageKey{}, r)
+}
+
+// recordUsage reports a call to the recorder in ctx, if there is one.
+func recordUsage(ctx context.Context, u Usage) {
+	if r, ok := ctx.Value(usageKey{}).(*UsageRecorder); ok && r != nil {
+		r.add(u)
+	}
+}
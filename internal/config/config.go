@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/robertguss/rss-agent-cli/pkg/retry"
@@ -11,23 +12,219 @@ import (
 
 // Source represents a news source configuration with metadata for fetching and prioritization.
 type Source struct {
-	Name     string `mapstructure:"name"`
-	URL      string `mapstructure:"url"`
-	Type     string `mapstructure:"type"`
-	Priority int    `mapstructure:"priority"`
+	Name     string        `mapstructure:"name"`
+	URL      string        `mapstructure:"url"`
+	Type     string        `mapstructure:"type"`
+	Priority int           `mapstructure:"priority"`
+	Enabled  *bool         `mapstructure:"enabled"` // nil means enabled; see IsEnabled
+	Group    string        `mapstructure:"group"`   // optional folder, e.g. from an OPML import
+	HTML     HTMLSelectors `mapstructure:"html"`    // only used by type "html"
+	Scraper  string        `mapstructure:"scraper"` // overrides Config.Scraper
+	Prompt   string        `mapstructure:"prompt"`  // overrides AIConfig.Prompt
 }
 
-// AIConfig holds AI-related configuration settings.
+// HTMLSelectors describes how to find articles on an HTML listing page for
+// sources of type "html". Item matches one element per article; Title, Link
+// and Date are evaluated inside each item. An empty Link uses the item itself
+// when it is an <a> element, otherwise its first link; an empty Title uses
+// the link text. Dates are read from a datetime attribute when present, else
+// from the element text, using DateLayouts (Go reference-time layouts) or a
+// set of common layouts when none are given.
+type HTMLSelectors struct {
+	Item        string   `mapstructure:"item"`
+	Title       string   `mapstructure:"title"`
+	Link        string   `mapstructure:"link"`
+	Date        string   `mapstructure:"date"`
+	DateLayouts []string `mapstructure:"date_layouts"`
+}
+
+// AIConfig holds AI-related configuration settings. Provider selects the
+// processor that analyzes articles: "gemini" (the default) or "openai".
+//
+// Prompt is the path of a text/template file to analyze articles with
+// instead of the built-in prompt; sources can override it.
+//
+// Providers, when set, replaces Provider with a registry of named providers.
+// Routes maps a task ("summarize", "classify" or "embed") to the names of
+// the providers to use for it, in order of preference; a task without a
+// route uses every provider in the order they are listed, except "embed",
+// which then uses Embeddings.
+// Routing summarize and classify to different providers doubles the cost of
+// analysis: both providers are sent the whole article with the full
+// analysis prompt, and the summary of one is combined with the
+// classification of the other.
 type AIConfig struct {
-	GeminiModel string `mapstructure:"gemini_model"`
+	Provider    string              `mapstructure:"provider"`
+	GeminiModel string              `mapstructure:"gemini_model"`
+	OpenAI      OpenAIConfig        `mapstructure:"openai"`
+	Embeddings  EmbeddingConfig     `mapstructure:"embeddings"`
+	Providers   []ProviderConfig    `mapstructure:"providers"`
+	Routes      map[string][]string `mapstructure:"routes"`
+	Prompt      string              `mapstructure:"prompt"`
+	Chunking    ChunkConfig         `mapstructure:"chunking"`
+}
+
+// ProviderConfig registers an AI provider under Name. Type is "gemini",
+// "openai" or "mock", the latter answering with a canned analysis for
+// testing. Model defaults per type; BaseURL, JSONMode and JSONSchema apply
+// to "openai" only and mean the same as in OpenAIConfig. The API key is
+// APIKey if set, else read from the environment variable named by
+// APIKeyEnv, which defaults to GEMINI_API_KEY or OPENAI_API_KEY.
+type ProviderConfig struct {
+	Name       string `mapstructure:"name"`
+	Type       string `mapstructure:"type"`
+	Model      string `mapstructure:"model"`
+	BaseURL    string `mapstructure:"base_url"`
+	APIKey     string `mapstructure:"api_key"`
+	APIKeyEnv  string `mapstructure:"api_key_env"`
+	JSONMode   *bool  `mapstructure:"json_mode"`
+	JSONSchema bool   `mapstructure:"json_schema"`
+}
+
+// Registry returns the configured providers, or when none are, the single
+// provider described by Provider, GeminiModel and OpenAI.
+func (c AIConfig) Registry() []ProviderConfig {
+	if len(c.Providers) > 0 {
+		return c.Providers
+	}
+	if strings.EqualFold(c.Provider, "openai") {
+		return []ProviderConfig{{
+			Name:       "openai",
+			Type:       "openai",
+			Model:      c.OpenAI.Model,
+			BaseURL:    c.OpenAI.BaseURL,
+			APIKey:     c.OpenAI.APIKey,
+			JSONMode:   c.OpenAI.JSONMode,
+			JSONSchema: c.OpenAI.JSONSchema,
+		}}
+	}
+	provider := c.Provider
+	if provider == "" {
+		provider = "gemini"
+	}
+	return []ProviderConfig{{Name: provider, Type: provider, Model: c.GeminiModel}}
+}
+
+// OpenAIConfig configures the "openai" provider, which works with OpenAI and
+// with any server that speaks its chat completions protocol, such as
+// llama.cpp or Ollama. APIKey falls back to OPENAI_API_KEY and may be empty
+// for local servers. JSONMode asks the server to answer with a JSON object
+// (response_format json_object); it is on unless set to false, for servers
+// that do not support it. JSONSchema goes further and has the server
+// follow the analysis JSON schema (response_format json_schema), which
+// OpenAI and some local servers support.
+type OpenAIConfig struct {
+	BaseURL    string `mapstructure:"base_url"`
+	Model      string `mapstructure:"model"`
+	APIKey     string `mapstructure:"api_key"`
+	JSONMode   *bool  `mapstructure:"json_mode"`
+	JSONSchema bool   `mapstructure:"json_schema"`
+}
+
+// UseJSONMode reports whether requests should ask for a JSON object answer.
+func (c OpenAIConfig) UseJSONMode() bool {
+	return c.JSONMode == nil || *c.JSONMode
+}
+
+// ChunkConfig controls how articles too long for one prompt are analyzed.
+// Content estimated at more than MaxTokens tokens is split at Markdown
+// headings into chunks of at most MaxTokens, which are analyzed one by one
+// and merged into a single analysis. Only the first MaxChunks chunks are
+// analyzed. A MaxTokens of -1 sends every article whole, and a MaxChunks of
+// -1 analyzes every chunk.
+type ChunkConfig struct {
+	MaxTokens int `mapstructure:"max_tokens"`
+	MaxChunks int `mapstructure:"max_chunks"`
+}
+
+// EmbeddingConfig selects the model that turns articles into vectors for
+// semantic search. Provider is "gemini" or "openai"; the latter talks to any
+// OpenAI-compatible /embeddings endpoint at BaseURL, such as a local server,
+// and sends OPENAI_API_KEY if it is set.
+type EmbeddingConfig struct {
+	Provider string `mapstructure:"provider"`
+	Model    string `mapstructure:"model"`
+	BaseURL  string `mapstructure:"base_url"`
+}
+
+// HealthConfig controls when repeatedly failing sources are quarantined.
+// A source is skipped for Cooldown after FailureThreshold consecutive fetch
+// failures; a threshold of zero or less disables quarantine.
+type HealthConfig struct {
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	Cooldown         time.Duration `mapstructure:"cooldown"`
+}
+
+// URLConfig controls how article URLs are canonicalized before they are
+// stored and compared. StripParams adds query parameters to remove on top of
+// the built-in tracking parameters; a trailing "*" matches a prefix. With
+// UseCanonicalLink, the page of each new article is checked for a
+// <link rel="canonical"> and the URL it names is stored instead.
+type URLConfig struct {
+	StripParams      []string `mapstructure:"strip_params"`
+	UseCanonicalLink bool     `mapstructure:"use_canonical_link"`
+}
+
+// StoryConfig controls how near-duplicate articles are grouped into stories.
+// An article joins the story of the most similar article published within
+// Window of it when their MinHash similarity is at least MinSimilarity or
+// their SimHash fingerprints differ in at most MaxDistance bits. A negative
+// MinSimilarity or MaxDistance disables that test. ShingleSize is the number
+// of words hashed together when fingerprinting.
+type StoryConfig struct {
+	Window        time.Duration `mapstructure:"window"`
+	MinSimilarity float64       `mapstructure:"min_similarity"`
+	MaxDistance   int           `mapstructure:"max_distance"`
+	ShingleSize   int           `mapstructure:"shingle_size"`
+}
+
+// UsageConfig prices AI calls and caps what they may cost. Prices are in US
+// dollars per million tokens; calls to a model without a price count as
+// free. With MonthlyBudget above zero, fetch stops analyzing articles once
+// the calls of the current calendar month have cost that much and leaves
+// the articles pending.
+type UsageConfig struct {
+	Prices        []ModelPrice `mapstructure:"prices"`
+	MonthlyBudget float64      `mapstructure:"monthly_budget"`
+}
+
+// ProcessConfig controls how articles awaiting analysis are worked through,
+// by the process command and by the workers that run queued jobs. An article
+// that failed MaxAttempts times is marked failed and no longer picked up; -1
+// retries without limit. A worker leases a job for Lease and renews the
+// lease while the job runs; a job whose lease runs out is taken over by
+// another worker on the assumption that the first one died.
+type ProcessConfig struct {
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	Lease       time.Duration `mapstructure:"lease"`
+}
+
+// ModelPrice is what a model costs per million prompt (Input) and output
+// tokens. Model is matched without regard to case.
+type ModelPrice struct {
+	Model  string  `mapstructure:"model"`
+	Input  float64 `mapstructure:"input"`
+	Output float64 `mapstructure:"output"`
 }
 
 // Config holds the complete application configuration including database settings,
 // news sources, network timeouts, retry policies, and logging configuration.
 type Config struct {
-	DSN     string   `mapstructure:"dsn"`
-	Sources []Source `mapstructure:"sources"`
-	AI      AIConfig `mapstructure:"ai"`
+	DSN     string        `mapstructure:"dsn"`
+	Sources []Source      `mapstructure:"sources"`
+	AI      AIConfig      `mapstructure:"ai"`
+	Health  HealthConfig  `mapstructure:"health"`
+	URLs    URLConfig     `mapstructure:"urls"`
+	Stories StoryConfig   `mapstructure:"stories"`
+	Usage   UsageConfig   `mapstructure:"usage"`
+	Process ProcessConfig `mapstructure:"process"`
+	Scraper string        `mapstructure:"scraper"` // "auto", "jina" or "readability"
+
+	// FeedContentMinLength is how many characters of Markdown a feed item
+	// must embed for its content to be used instead of scraping the page.
+	// Zero or less always scrapes; since an unset value defaults to 1000,
+	// set a negative value in the config file to turn this off.
+	FeedContentMinLength int `mapstructure:"feed_content_min_length"`
 
 	NetworkTimeout time.Duration `mapstructure:"network_timeout"`
 	MaxRetries     int           `mapstructure:"max_retries"`
@@ -46,16 +243,7 @@ func Load() (*Config, error) {
 // LoadFromPath loads the application configuration from a specific file path.
 // If configPath is empty, it uses the default search behavior.
 func LoadFromPath(configPath string) (*Config, error) {
-	v := viper.New()
-
-	if configPath != "" {
-		v.SetConfigFile(configPath)
-	} else {
-		v.SetConfigName("config")
-		v.SetConfigType("yaml")
-		v.AddConfigPath(".")
-		v.AddConfigPath("./configs")
-	}
+	v := newViper(configPath)
 
 	if err := v.ReadInConfig(); err != nil {
 		return nil, err
@@ -74,6 +262,22 @@ func LoadFromPath(configPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// newViper returns a viper instance for configPath, or for the default
+// config.yaml search path if configPath is empty.
+func newViper(configPath string) *viper.Viper {
+	v := viper.New()
+
+	if configPath != "" {
+		v.SetConfigFile(configPath)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+		v.AddConfigPath("./configs")
+	}
+	return v
+}
+
 func setDefaults(cfg *Config) {
 	if cfg.NetworkTimeout == 0 {
 		if timeoutStr := os.Getenv("NETWORK_TIMEOUT"); timeoutStr != "" {
@@ -139,6 +343,91 @@ func setDefaults(cfg *Config) {
 		}
 	}
 
+	if cfg.Health.FailureThreshold == 0 {
+		if thresholdStr := os.Getenv("SOURCE_FAILURE_THRESHOLD"); thresholdStr != "" {
+			if threshold, err := strconv.Atoi(thresholdStr); err == nil {
+				cfg.Health.FailureThreshold = threshold
+			}
+		}
+		if cfg.Health.FailureThreshold == 0 {
+			cfg.Health.FailureThreshold = 5
+		}
+	}
+
+	if cfg.Health.Cooldown == 0 {
+		if cooldownStr := os.Getenv("SOURCE_COOLDOWN"); cooldownStr != "" {
+			if cooldown, err := time.ParseDuration(cooldownStr); err == nil {
+				cfg.Health.Cooldown = cooldown
+			}
+		}
+		if cfg.Health.Cooldown == 0 {
+			cfg.Health.Cooldown = time.Hour
+		}
+	}
+
+	if cfg.Stories.Window == 0 {
+		if windowStr := os.Getenv("STORY_WINDOW"); windowStr != "" {
+			if window, err := time.ParseDuration(windowStr); err == nil {
+				cfg.Stories.Window = window
+			}
+		}
+		if cfg.Stories.Window == 0 {
+			cfg.Stories.Window = 48 * time.Hour
+		}
+	}
+
+	if cfg.Stories.MinSimilarity == 0 {
+		if similarityStr := os.Getenv("STORY_MIN_SIMILARITY"); similarityStr != "" {
+			if similarity, err := strconv.ParseFloat(similarityStr, 64); err == nil {
+				cfg.Stories.MinSimilarity = similarity
+			}
+		}
+		if cfg.Stories.MinSimilarity == 0 {
+			cfg.Stories.MinSimilarity = 0.5
+		}
+	}
+
+	if cfg.Stories.MaxDistance == 0 {
+		if distanceStr := os.Getenv("STORY_MAX_DISTANCE"); distanceStr != "" {
+			if distance, err := strconv.Atoi(distanceStr); err == nil {
+				cfg.Stories.MaxDistance = distance
+			}
+		}
+		if cfg.Stories.MaxDistance == 0 {
+			cfg.Stories.MaxDistance = 3
+		}
+	}
+
+	if cfg.Stories.ShingleSize == 0 {
+		if sizeStr := os.Getenv("STORY_SHINGLE_SIZE"); sizeStr != "" {
+			if size, err := strconv.Atoi(sizeStr); err == nil {
+				cfg.Stories.ShingleSize = size
+			}
+		}
+		if cfg.Stories.ShingleSize <= 0 {
+			cfg.Stories.ShingleSize = 3
+		}
+	}
+
+	if cfg.Scraper == "" {
+		if scraper := os.Getenv("SCRAPER"); scraper != "" {
+			cfg.Scraper = scraper
+		} else {
+			cfg.Scraper = "auto"
+		}
+	}
+
+	if cfg.FeedContentMinLength == 0 {
+		if lengthStr := os.Getenv("FEED_CONTENT_MIN_LENGTH"); lengthStr != "" {
+			if length, err := strconv.Atoi(lengthStr); err == nil {
+				cfg.FeedContentMinLength = length
+			}
+		}
+		if cfg.FeedContentMinLength == 0 {
+			cfg.FeedContentMinLength = 1000
+		}
+	}
+
 	if cfg.AI.GeminiModel == "" {
 		if model := os.Getenv("GEMINI_MODEL"); model != "" {
 			cfg.AI.GeminiModel = model
@@ -146,6 +435,102 @@ func setDefaults(cfg *Config) {
 			cfg.AI.GeminiModel = "gemini-1.5-flash"
 		}
 	}
+
+	if cfg.AI.Provider == "" {
+		if provider := os.Getenv("AI_PROVIDER"); provider != "" {
+			cfg.AI.Provider = provider
+		} else {
+			cfg.AI.Provider = "gemini"
+		}
+	}
+
+	if cfg.AI.OpenAI.BaseURL == "" {
+		if baseURL := os.Getenv("OPENAI_BASE_URL"); baseURL != "" {
+			cfg.AI.OpenAI.BaseURL = baseURL
+		} else {
+			cfg.AI.OpenAI.BaseURL = "https://api.openai.com/v1"
+		}
+	}
+
+	if cfg.AI.OpenAI.Model == "" {
+		if model := os.Getenv("OPENAI_MODEL"); model != "" {
+			cfg.AI.OpenAI.Model = model
+		} else {
+			cfg.AI.OpenAI.Model = "gpt-4o-mini"
+		}
+	}
+
+	if cfg.AI.OpenAI.APIKey == "" {
+		cfg.AI.OpenAI.APIKey = os.Getenv("OPENAI_API_KEY")
+	}
+
+	if cfg.AI.Chunking.MaxTokens == 0 {
+		cfg.AI.Chunking.MaxTokens = 8000
+	}
+
+	if cfg.AI.Chunking.MaxChunks == 0 {
+		cfg.AI.Chunking.MaxChunks = 16
+	}
+
+	if cfg.Process.MaxAttempts == 0 {
+		cfg.Process.MaxAttempts = 5
+	}
+
+	if cfg.Process.Lease == 0 {
+		cfg.Process.Lease = 10 * time.Minute
+	}
+
+	if cfg.Usage.MonthlyBudget == 0 {
+		if budgetStr := os.Getenv("AI_MONTHLY_BUDGET"); budgetStr != "" {
+			if budget, err := strconv.ParseFloat(budgetStr, 64); err == nil {
+				cfg.Usage.MonthlyBudget = budget
+			}
+		}
+	}
+
+	if cfg.AI.Embeddings.Provider == "" {
+		if provider := os.Getenv("EMBEDDING_PROVIDER"); provider != "" {
+			cfg.AI.Embeddings.Provider = provider
+		} else {
+			cfg.AI.Embeddings.Provider = "gemini"
+		}
+	}
+
+	if cfg.AI.Embeddings.Model == "" {
+		if model := os.Getenv("EMBEDDING_MODEL"); model != "" {
+			cfg.AI.Embeddings.Model = model
+		} else if cfg.AI.Embeddings.Provider == "openai" {
+			cfg.AI.Embeddings.Model = "text-embedding-3-small"
+		} else {
+			cfg.AI.Embeddings.Model = "text-embedding-004"
+		}
+	}
+
+	if cfg.AI.Embeddings.BaseURL == "" {
+		if baseURL := os.Getenv("EMBEDDING_BASE_URL"); baseURL != "" {
+			cfg.AI.Embeddings.BaseURL = baseURL
+		} else if cfg.AI.Embeddings.Provider == "openai" {
+			cfg.AI.Embeddings.BaseURL = "https://api.openai.com/v1"
+		}
+	}
+}
+
+// ScraperFor returns the name of the scraper used for source's articles: the
+// source's own scraper setting if it has one, else the global one.
+func (c *Config) ScraperFor(source Source) string {
+	if source.Scraper != "" {
+		return source.Scraper
+	}
+	return c.Scraper
+}
+
+// PromptFor returns the path of the prompt template used for source's
+// articles, or "" for the built-in one.
+func (c *Config) PromptFor(source Source) string {
+	if source.Prompt != "" {
+		return source.Prompt
+	}
+	return c.AI.Prompt
 }
 
 func (c *Config) RetryConfig() retry.Config {
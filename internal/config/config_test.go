@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -85,3 +86,217 @@ func TestLoad_InvalidYAML(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, config)
 }
+
+func TestLoadFromPath_URLSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("urls:\n  strip_params: [ref, \"share_*\"]\n  use_canonical_link: true\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ref", "share_*"}, config.URLs.StripParams)
+	assert.True(t, config.URLs.UseCanonicalLink)
+}
+
+func TestLoadFromPath_StorySettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("stories:\n  window: \"24h\"\n  min_similarity: 0.7\n  max_distance: -1\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, config.Stories.Window)
+	assert.Equal(t, 0.7, config.Stories.MinSimilarity)
+	assert.Equal(t, -1, config.Stories.MaxDistance)
+	assert.Equal(t, 3, config.Stories.ShingleSize)
+
+	err = os.WriteFile(configPath, []byte("sources: []\n"), 0644)
+	require.NoError(t, err)
+
+	config, err = LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, config.Stories.Window)
+	assert.Equal(t, 0.5, config.Stories.MinSimilarity)
+	assert.Equal(t, 3, config.Stories.MaxDistance)
+}
+
+func TestLoadFromPath_ChunkSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("ai:\n  chunking:\n    max_tokens: -1\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, -1, config.AI.Chunking.MaxTokens)
+	assert.Equal(t, 16, config.AI.Chunking.MaxChunks)
+
+	err = os.WriteFile(configPath, []byte("sources: []\n"), 0644)
+	require.NoError(t, err)
+
+	config, err = LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 8000, config.AI.Chunking.MaxTokens)
+}
+
+func TestLoadFromPath_ProcessSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("sources: []\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 5, config.Process.MaxAttempts)
+	assert.Equal(t, 10*time.Minute, config.Process.Lease)
+
+	err = os.WriteFile(configPath, []byte("process:\n  max_attempts: -1\n  lease: 90s\n"), 0644)
+	require.NoError(t, err)
+
+	config, err = LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, -1, config.Process.MaxAttempts)
+	assert.Equal(t, 90*time.Second, config.Process.Lease)
+}
+
+func TestLoadFromPath_OpenAISettings(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "env-key")
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("sources: []\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "gemini", config.AI.Provider)
+	assert.Equal(t, "https://api.openai.com/v1", config.AI.OpenAI.BaseURL)
+	assert.Equal(t, "env-key", config.AI.OpenAI.APIKey)
+	assert.True(t, config.AI.OpenAI.UseJSONMode())
+
+	err = os.WriteFile(configPath, []byte("ai:\n  provider: openai\n  openai:\n    base_url: http://localhost:8080/v1\n    model: llama3\n    api_key: file-key\n    json_mode: false\n"), 0644)
+	require.NoError(t, err)
+
+	config, err = LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "openai", config.AI.Provider)
+	assert.Equal(t, "http://localhost:8080/v1", config.AI.OpenAI.BaseURL)
+	assert.Equal(t, "llama3", config.AI.OpenAI.Model)
+	assert.Equal(t, "file-key", config.AI.OpenAI.APIKey)
+	assert.False(t, config.AI.OpenAI.UseJSONMode())
+}
+
+func TestLoadFromPath_EmbeddingSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("sources: []\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "gemini", config.AI.Embeddings.Provider)
+	assert.Equal(t, "text-embedding-004", config.AI.Embeddings.Model)
+	assert.Empty(t, config.AI.Embeddings.BaseURL)
+
+	err = os.WriteFile(configPath, []byte("ai:\n  embeddings:\n    provider: openai\n"), 0644)
+	require.NoError(t, err)
+
+	config, err = LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "text-embedding-3-small", config.AI.Embeddings.Model)
+	assert.Equal(t, "https://api.openai.com/v1", config.AI.Embeddings.BaseURL)
+}
+
+func TestLoadFromPath_AIProviders(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("sources: []\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, []ProviderConfig{{Name: "gemini", Type: "gemini", Model: "gemini-1.5-flash"}}, config.AI.Registry())
+
+	err = os.WriteFile(configPath, []byte(`ai:
+  providers:
+    - name: local
+      type: openai
+      base_url: http://localhost:11434/v1
+      model: llama3.1
+      json_mode: false
+    - name: cloud
+      type: gemini
+      api_key_env: MY_GEMINI_KEY
+  routes:
+    summarize: [local, cloud]
+    classify: [cloud]
+`), 0644)
+	require.NoError(t, err)
+
+	config, err = LoadFromPath(configPath)
+	require.NoError(t, err)
+	registry := config.AI.Registry()
+	require.Len(t, registry, 2)
+	assert.Equal(t, "local", registry[0].Name)
+	assert.Equal(t, "http://localhost:11434/v1", registry[0].BaseURL)
+	require.NotNil(t, registry[0].JSONMode)
+	assert.False(t, *registry[0].JSONMode)
+	assert.Equal(t, "MY_GEMINI_KEY", registry[1].APIKeyEnv)
+	assert.Equal(t, []string{"local", "cloud"}, config.AI.Routes["summarize"])
+	assert.Equal(t, []string{"cloud"}, config.AI.Routes["classify"])
+}
+
+func TestLoadFromPath_UsageSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte(`sources: []
+usage:
+  monthly_budget: 12.5
+  prices:
+    - model: gemini-2.5-flash
+      input: 0.30
+      output: 2.50
+    - model: gpt-4o-mini
+      input: 0.15
+      output: 0.60
+`), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 12.5, config.Usage.MonthlyBudget)
+	assert.Equal(t, []ModelPrice{
+		{Model: "gemini-2.5-flash", Input: 0.30, Output: 2.50},
+		{Model: "gpt-4o-mini", Input: 0.15, Output: 0.60},
+	}, config.Usage.Prices)
+}
+
+func TestLoadFromPath_HealthSettings(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	err := os.WriteFile(configPath, []byte("health:\n  failure_threshold: 3\n  cooldown: \"30m\"\n"), 0644)
+	require.NoError(t, err)
+
+	config, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, config.Health.FailureThreshold)
+	assert.Equal(t, 30*time.Minute, config.Health.Cooldown)
+
+	err = os.WriteFile(configPath, []byte("sources: []\n"), 0644)
+	require.NoError(t, err)
+
+	config, err = LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 5, config.Health.FailureThreshold)
+	assert.Equal(t, time.Hour, config.Health.Cooldown)
+}
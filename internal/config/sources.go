@@ -0,0 +1,192 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrSourceNotFound  = errors.New("source not found")
+	ErrDuplicateSource = errors.New("source already exists")
+)
+
+// IsEnabled reports whether the source should be fetched. Sources are
+// enabled unless the config sets enabled: false.
+func (s Source) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// EnabledSources returns the configured sources that fetch should process.
+func (c *Config) EnabledSources() []Source {
+	var sources []Source
+	for _, source := range c.Sources {
+		if source.IsEnabled() {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// SourceNamed returns the configured source called name, or a source with
+// only that name if it is no longer configured, as for articles stored from
+// a source since removed.
+func (c *Config) SourceNamed(name string) Source {
+	for _, source := range c.Sources {
+		if source.Name == name {
+			return source
+		}
+	}
+	return Source{Name: name}
+}
+
+// SourcesFile is a config file opened for editing its sources list. Only the
+// sources key is rewritten on Save; every other setting is written back as it
+// was read, and unknown keys on existing sources are kept.
+type SourcesFile struct {
+	v       *viper.Viper
+	sources []map[string]interface{}
+}
+
+// OpenSourcesFile reads the config file at configPath, or the default
+// config.yaml search path if configPath is empty.
+func OpenSourcesFile(configPath string) (*SourcesFile, error) {
+	v := newViper(configPath)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	f := &SourcesFile{v: v}
+	raw, _ := v.Get("sources").([]interface{})
+	for _, item := range raw {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid sources entry in %s: %v", v.ConfigFileUsed(), item)
+		}
+		f.sources = append(f.sources, entry)
+	}
+	return f, nil
+}
+
+// Path returns the config file being edited.
+func (f *SourcesFile) Path() string {
+	return f.v.ConfigFileUsed()
+}
+
+// Sources decodes the current sources list.
+func (f *SourcesFile) Sources() ([]Source, error) {
+	sources := make([]Source, 0, len(f.sources))
+	for _, entry := range f.sources {
+		var source Source
+		if err := mapstructure.WeakDecode(entry, &source); err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// Add appends a source. Names compare case-insensitively and URLs exactly;
+// a clash with an existing source returns ErrDuplicateSource.
+func (f *SourcesFile) Add(source Source) error {
+	for _, entry := range f.sources {
+		if strings.EqualFold(entryString(entry, "name"), source.Name) || entryString(entry, "url") == source.URL {
+			return fmt.Errorf("%w: %s", ErrDuplicateSource, entryString(entry, "name"))
+		}
+	}
+
+	entry := map[string]interface{}{
+		"name":     source.Name,
+		"url":      source.URL,
+		"type":     source.Type,
+		"priority": source.Priority,
+	}
+	if source.Enabled != nil {
+		entry["enabled"] = *source.Enabled
+	}
+	if source.Group != "" {
+		entry["group"] = source.Group
+	}
+	if html := source.HTML.entry(); len(html) > 0 {
+		entry["html"] = html
+	}
+	if source.Scraper != "" {
+		entry["scraper"] = source.Scraper
+	}
+	if source.Prompt != "" {
+		entry["prompt"] = source.Prompt
+	}
+	f.sources = append(f.sources, entry)
+	return nil
+}
+
+// Remove deletes the named source.
+func (f *SourcesFile) Remove(name string) error {
+	i, err := f.find(name)
+	if err != nil {
+		return err
+	}
+	f.sources = append(f.sources[:i], f.sources[i+1:]...)
+	return nil
+}
+
+// SetEnabled enables or disables the named source.
+func (f *SourcesFile) SetEnabled(name string, enabled bool) error {
+	i, err := f.find(name)
+	if err != nil {
+		return err
+	}
+	f.sources[i]["enabled"] = enabled
+	return nil
+}
+
+// SetPriority changes the priority of the named source.
+func (f *SourcesFile) SetPriority(name string, priority int) error {
+	i, err := f.find(name)
+	if err != nil {
+		return err
+	}
+	f.sources[i]["priority"] = priority
+	return nil
+}
+
+// Save writes the edited sources list back to the config file.
+func (f *SourcesFile) Save() error {
+	sources := make([]interface{}, len(f.sources))
+	for i, entry := range f.sources {
+		sources[i] = entry
+	}
+	f.v.Set("sources", sources)
+	return f.v.WriteConfig()
+}
+
+func (f *SourcesFile) find(name string) (int, error) {
+	for i, entry := range f.sources {
+		if strings.EqualFold(entryString(entry, "name"), name) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: %s", ErrSourceNotFound, name)
+}
+
+// entry returns the selectors that are set, keyed as in the config file.
+func (h HTMLSelectors) entry() map[string]interface{} {
+	entry := make(map[string]interface{})
+	for key, value := range map[string]string{"item": h.Item, "title": h.Title, "link": h.Link, "date": h.Date} {
+		if value != "" {
+			entry[key] = value
+		}
+	}
+	if len(h.DateLayouts) > 0 {
+		entry["date_layouts"] = h.DateLayouts
+	}
+	return entry
+}
+
+func entryString(entry map[string]interface{}, key string) string {
+	s, _ := entry[key].(string)
+	return s
+}
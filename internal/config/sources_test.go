@@ -0,0 +1,147 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sourcesTestConfig = `dsn: custom.db
+ai:
+  gemini_model: gemini-custom
+sources:
+  - name: "First Blog"
+    url: "https://example.com/first.xml"
+    type: "rss"
+    priority: 1
+  - name: "Second Blog"
+    url: "https://example.com/second.xml"
+    type: "rss"
+    priority: 2
+    enabled: false
+`
+
+func writeSourcesConfig(t *testing.T) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(sourcesTestConfig), 0644))
+	return configPath
+}
+
+func TestEnabledSources(t *testing.T) {
+	configPath := writeSourcesConfig(t)
+
+	cfg, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Sources, 2)
+	assert.True(t, cfg.Sources[0].IsEnabled())
+	assert.False(t, cfg.Sources[1].IsEnabled())
+
+	enabled := cfg.EnabledSources()
+	require.Len(t, enabled, 1)
+	assert.Equal(t, "First Blog", enabled[0].Name)
+}
+
+func TestSourcesFile_EditsPreserveOtherSettings(t *testing.T) {
+	configPath := writeSourcesConfig(t)
+
+	file, err := OpenSourcesFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, configPath, file.Path())
+
+	require.NoError(t, file.Add(Source{Name: "Third Blog", URL: "https://example.com/third.xml", Type: "rss", Priority: 3}))
+	require.NoError(t, file.Remove("first blog"))
+	require.NoError(t, file.SetEnabled("Second Blog", true))
+	require.NoError(t, file.SetPriority("Third Blog", 1))
+	require.NoError(t, file.Save())
+
+	cfg, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+
+	assert.Equal(t, "custom.db", cfg.DSN)
+	assert.Equal(t, "gemini-custom", cfg.AI.GeminiModel)
+
+	require.Len(t, cfg.Sources, 2)
+	assert.Equal(t, "Second Blog", cfg.Sources[0].Name)
+	assert.True(t, cfg.Sources[0].IsEnabled())
+	assert.Equal(t, 2, cfg.Sources[0].Priority)
+	assert.Equal(t, "Third Blog", cfg.Sources[1].Name)
+	assert.Equal(t, "https://example.com/third.xml", cfg.Sources[1].URL)
+	assert.Equal(t, 1, cfg.Sources[1].Priority)
+	assert.True(t, cfg.Sources[1].IsEnabled())
+}
+
+func TestSourcesFile_Errors(t *testing.T) {
+	configPath := writeSourcesConfig(t)
+
+	file, err := OpenSourcesFile(configPath)
+	require.NoError(t, err)
+
+	err = file.Add(Source{Name: "FIRST BLOG", URL: "https://example.com/other.xml"})
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+
+	err = file.Add(Source{Name: "Other", URL: "https://example.com/second.xml"})
+	assert.ErrorIs(t, err, ErrDuplicateSource)
+
+	assert.ErrorIs(t, file.Remove("Missing"), ErrSourceNotFound)
+	assert.ErrorIs(t, file.SetEnabled("Missing", false), ErrSourceNotFound)
+	assert.ErrorIs(t, file.SetPriority("Missing", 1), ErrSourceNotFound)
+
+	_, err = OpenSourcesFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestLoadFromPath_HTMLSelectors(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`sources:
+  - name: "Lab Blog"
+    url: "https://example.com/blog/"
+    type: "html"
+    priority: 2
+    html:
+      item: "article.post"
+      title: "h2"
+      link: "h2 a"
+      date: "time"
+      date_layouts: ["2006-01-02", "Jan 2, 2006"]
+`), 0644))
+
+	cfg, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Sources, 1)
+	assert.Equal(t, HTMLSelectors{
+		Item:        "article.post",
+		Title:       "h2",
+		Link:        "h2 a",
+		Date:        "time",
+		DateLayouts: []string{"2006-01-02", "Jan 2, 2006"},
+	}, cfg.Sources[0].HTML)
+}
+
+func TestScraperFor(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`scraper: readability
+sources:
+  - name: "Default"
+    url: "https://example.com/a.xml"
+  - name: "Override"
+    url: "https://example.com/b.xml"
+    scraper: jina
+`), 0644))
+
+	cfg, err := LoadFromPath(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "readability", cfg.ScraperFor(cfg.Sources[0]))
+	assert.Equal(t, "jina", cfg.ScraperFor(cfg.Sources[1]))
+
+	t.Setenv("SCRAPER", "")
+	t.Setenv("FEED_CONTENT_MIN_LENGTH", "")
+	cfg, err = LoadFromPath(writeSourcesConfig(t))
+	require.NoError(t, err)
+	assert.Equal(t, "auto", cfg.Scraper, "the fallback chain is the default")
+	assert.Equal(t, 1000, cfg.FeedContentMinLength)
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/robertguss/rss-agent-cli/internal/urlnorm"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+)
+
+// CanonicalizeResult summarizes a CanonicalizeURLs run.
+type CanonicalizeResult struct {
+	Updated int // articles whose url or canonical_url was rewritten
+	Merged  int // duplicate articles removed
+}
+
+// CanonicalizeURLs brings articles stored before URL canonicalization up to
+// date: each url is rewritten to its canonical form and canonical_url is set
+// to its key. Articles whose URLs share a key are merged into one; the kept
+// article is the oldest one with a completed analysis, or the oldest of all
+// if none has one, and it is marked read if any of its duplicates was. With
+// dryRun the result is computed but nothing is written.
+func CanonicalizeURLs(ctx context.Context, db *sql.DB, norm *urlnorm.Normalizer, dryRun bool) (CanonicalizeResult, error) {
+	var result CanonicalizeResult
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, errs.Wrap("begin canonicalize transaction", err)
+	}
+	defer tx.Rollback()
+	q := New(tx)
+
+	rows, err := q.ListArticleURLs(ctx)
+	if err != nil {
+		return result, errs.Wrap("list article urls", err)
+	}
+
+	var keys []string
+	groups := make(map[string][]ListArticleURLsRow)
+	for _, row := range rows {
+		key := norm.Key(row.Url.String)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	// Remove duplicates before rewriting URLs, so that no rewritten url
+	// collides with a duplicate that still holds it.
+	var keep []ListArticleURLsRow
+	for _, key := range keys {
+		group := groups[key]
+		kept := group[0]
+		for _, row := range group {
+			if row.AnalysisStatus.String == "completed" {
+				kept = row
+				break
+			}
+		}
+
+		read := false
+		for _, row := range group {
+			if row.ID == kept.ID {
+				continue
+			}
+			read = read || row.Status.String == "read"
+			result.Merged++
+			if !dryRun {
+				if err := q.DeleteArticle(ctx, row.ID); err != nil {
+					return result, errs.Wrap("delete duplicate article", err)
+				}
+			}
+		}
+		if read && kept.Status.String != "read" && !dryRun {
+			err := q.UpdateArticleStatus(ctx, UpdateArticleStatusParams{
+				Status: sql.NullString{String: "read", Valid: true},
+				ID:     kept.ID,
+			})
+			if err != nil {
+				return result, errs.Wrap("mark merged article read", err)
+			}
+		}
+		keep = append(keep, kept)
+	}
+
+	for _, row := range keep {
+		url := norm.Canonical(row.Url.String)
+		key := norm.Key(row.Url.String)
+		if url == row.Url.String && key == row.CanonicalUrl.String {
+			continue
+		}
+		result.Updated++
+		if dryRun {
+			continue
+		}
+		err := q.UpdateArticleURL(ctx, UpdateArticleURLParams{
+			Url:          sql.NullString{String: url, Valid: true},
+			CanonicalUrl: sql.NullString{String: key, Valid: true},
+			ID:           row.ID,
+		})
+		if err != nil {
+			return result, errs.Wrap("update article url", err)
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return result, errs.Wrap("commit canonicalize transaction", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/urlnorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createURLArticle(t *testing.T, queries *Queries, url, status, analysisStatus string) Article {
+	t.Helper()
+	article, err := queries.CreateArticle(context.Background(), CreateArticleParams{
+		Title:          sql.NullString{String: url, Valid: true},
+		Url:            sql.NullString{String: url, Valid: true},
+		Status:         sql.NullString{String: status, Valid: true},
+		AnalysisStatus: sql.NullString{String: analysisStatus, Valid: true},
+	})
+	require.NoError(t, err)
+	return article
+}
+
+func TestCanonicalizeURLs(t *testing.T) {
+	db, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	first := createURLArticle(t, queries, "http://example.com/post?utm_source=rss", "read", "pending")
+	analyzed := createURLArticle(t, queries, "https://www.example.com/post/", "unread", "completed")
+	createURLArticle(t, queries, "https://example.com/post/amp", "unread", "failed")
+	other := createURLArticle(t, queries, "https://Example.com/other#top", "unread", "completed")
+	clean := createURLArticle(t, queries, "https://example.com/clean", "unread", "completed")
+	require.NoError(t, queries.TagArticle(ctx, first.ID, []string{"AI"}, nil))
+
+	norm := urlnorm.New()
+
+	result, err := CanonicalizeURLs(ctx, db, norm, true)
+	require.NoError(t, err)
+	assert.Equal(t, CanonicalizeResult{Updated: 3, Merged: 2}, result)
+	articles, err := queries.ListArticles(ctx)
+	require.NoError(t, err)
+	assert.Len(t, articles, 5, "a dry run changes nothing")
+
+	result, err = CanonicalizeURLs(ctx, db, norm, false)
+	require.NoError(t, err)
+	assert.Equal(t, CanonicalizeResult{Updated: 3, Merged: 2}, result)
+
+	articles, err = queries.ListArticles(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{analyzed.ID, other.ID, clean.ID}, articleIDs(articles))
+
+	kept, err := queries.GetArticleByCanonicalUrl(ctx, sql.NullString{String: "https://example.com/post", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, analyzed.ID, kept.ID, "the analyzed duplicate is kept")
+	assert.Equal(t, "https://www.example.com/post/", kept.Url.String)
+	assert.Equal(t, "read", kept.Status.String, "read state carries over from a merged duplicate")
+
+	otherNow, err := queries.GetArticle(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/other", otherNow.Url.String)
+
+	topics, err := queries.ListArticleTopics(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Empty(t, topics, "tags of removed duplicates are cleaned up")
+
+	result, err = CanonicalizeURLs(ctx, db, norm, false)
+	require.NoError(t, err)
+	assert.Equal(t, CanonicalizeResult{}, result, "a second run has nothing to do")
+}
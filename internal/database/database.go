@@ -3,7 +3,6 @@ package database
 import (
 	"context"
 	"database/sql"
-	_ "embed"
 	"fmt"
 	"time"
 
@@ -12,13 +11,10 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-//go:embed schema.sql
-var schemaSQL string
-
 func Open(dataSource string) (*sql.DB, *Queries, error) {
 	dsn := dataSource
 	if dsn != ":memory:" && dsn != "" {
-		dsn = fmt.Sprintf("%s?_busy_timeout=3000&_journal_mode=WAL&_synchronous=NORMAL", dataSource)
+		dsn = fmt.Sprintf("%s?_pragma=busy_timeout(3000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)", dataSource)
 	}
 
 	db, err := sql.Open("sqlite", dsn)
@@ -45,17 +41,19 @@ func Open(dataSource string) (*sql.DB, *Queries, error) {
 	return db, New(db), nil
 }
 
+// InitSchema brings the database up to the latest schema version by applying
+// any pending migrations. Every command calls it after opening the database.
 func InitSchema(db *sql.DB) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	_, err := db.ExecContext(ctx, schemaSQL)
+	applied, err := MigrateUp(ctx, db)
 	if err != nil {
 		wrappedErr := errs.Wrap("initialize database schema", err)
 		logging.Error("database_init_schema", wrappedErr)
 		return wrappedErr
 	}
 
-	logging.Info("database_init_schema", "Database schema initialized successfully")
+	logging.Info("database_init_schema", fmt.Sprintf("Database schema initialized successfully (%d migrations applied)", len(applied)))
 	return nil
 }
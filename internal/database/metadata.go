@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// EnclosureRef describes a media file attached to a feed item.
+type EnclosureRef struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// ArticleMetadata is the feed-supplied metadata stored alongside an article.
+type ArticleMetadata struct {
+	Authors    []string
+	Categories []string
+	Enclosures []EnclosureRef
+}
+
+// SetArticleMetadata links an article to its authors, categories and
+// enclosures, creating authors as needed. Blank names and URLs are skipped,
+// and repeated values are stored once.
+func (q *Queries) SetArticleMetadata(ctx context.Context, articleID int64, meta ArticleMetadata) error {
+	for _, name := range meta.Authors {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		authorID, err := q.UpsertAuthor(ctx, name)
+		if err != nil {
+			return err
+		}
+		err = q.LinkArticleAuthor(ctx, LinkArticleAuthorParams{ArticleID: articleID, AuthorID: authorID})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, category := range meta.Categories {
+		category = strings.TrimSpace(category)
+		if category == "" {
+			continue
+		}
+
+		err := q.AddArticleCategory(ctx, AddArticleCategoryParams{ArticleID: articleID, Category: category})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, enclosure := range meta.Enclosures {
+		url := strings.TrimSpace(enclosure.URL)
+		if url == "" {
+			continue
+		}
+
+		err := q.AddArticleEnclosure(ctx, AddArticleEnclosureParams{
+			ArticleID: articleID,
+			Url:       url,
+			MimeType:  sql.NullString{String: enclosure.Type, Valid: enclosure.Type != ""},
+			Length:    sql.NullInt64{Int64: enclosure.Length, Valid: enclosure.Length > 0},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
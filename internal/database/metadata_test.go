@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetArticleMetadata(t *testing.T) {
+	db, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	article := createSearchArticle(t, queries, "Episode 12", "Podcast", "", "", "", "unread")
+
+	require.NoError(t, queries.SetArticleMetadata(ctx, article.ID, ArticleMetadata{
+		Authors:    []string{"Jane Doe", " jane doe ", "", "John Roe"},
+		Categories: []string{"Audio", "audio", " "},
+		Enclosures: []EnclosureRef{
+			{URL: "https://example.com/ep12.mp3", Type: "audio/mpeg", Length: 1234},
+			{URL: "https://example.com/ep12.mp3", Type: "audio/mpeg", Length: 1234},
+			{URL: ""},
+		},
+	}))
+
+	authors, err := queries.ListArticleAuthors(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Jane Doe", "John Roe"}, authors)
+
+	categories, err := queries.ListArticleCategories(ctx, article.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Audio"}, categories)
+
+	enclosures, err := queries.ListArticleEnclosures(ctx, article.ID)
+	require.NoError(t, err)
+	require.Len(t, enclosures, 1)
+	assert.Equal(t, "https://example.com/ep12.mp3", enclosures[0].Url)
+	assert.Equal(t, "audio/mpeg", enclosures[0].MimeType.String)
+	assert.Equal(t, int64(1234), enclosures[0].Length.Int64)
+
+	_, err = db.ExecContext(ctx, "DELETE FROM articles WHERE id = ?", article.ID)
+	require.NoError(t, err)
+	var remaining int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT
+		(SELECT COUNT(*) FROM article_authors) +
+		(SELECT COUNT(*) FROM article_categories) +
+		(SELECT COUNT(*) FROM article_enclosures)`).Scan(&remaining))
+	assert.Zero(t, remaining, "metadata rows are removed with the article")
+}
+
+func TestListArticlesByAuthor(t *testing.T) {
+	_, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	unread := createSearchArticle(t, queries, "Gemini launch", "Google Blog", "", "", "", "unread")
+	read := createSearchArticle(t, queries, "Gemini recap", "News", "", "", "", "read")
+	other := createSearchArticle(t, queries, "Claude launch", "News", "", "", "", "unread")
+
+	require.NoError(t, queries.SetArticleMetadata(ctx, unread.ID, ArticleMetadata{Authors: []string{"Jane Doe"}}))
+	require.NoError(t, queries.SetArticleMetadata(ctx, read.ID, ArticleMetadata{Authors: []string{"jane doe"}}))
+	require.NoError(t, queries.SetArticleMetadata(ctx, other.ID, ArticleMetadata{Authors: []string{"John Roe"}}))
+	require.NoError(t, queries.TagArticle(ctx, read.ID, nil, []EntityRef{{Name: "Gemini", Kind: EntityProduct}}))
+
+	articles, err := queries.ListArticlesByAuthor(ctx, ListArticlesByAuthorParams{Author: "JANE DOE"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{unread.ID}, articleIDs(articles))
+
+	articles, err = queries.ListArticlesByAuthor(ctx, ListArticlesByAuthorParams{Author: "Jane Doe", IncludeRead: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{unread.ID, read.ID}, articleIDs(articles))
+
+	articles, err = queries.ListArticlesByAuthor(ctx, ListArticlesByAuthorParams{
+		Author:      "Jane Doe",
+		IncludeRead: true,
+		Entity:      sql.NullString{String: "gemini", Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{read.ID}, articleIDs(articles))
+}
+
+func TestGetArticleBySourceGuid(t *testing.T) {
+	_, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	created, err := queries.CreateArticle(ctx, CreateArticleParams{
+		Title:      sql.NullString{String: "Post", Valid: true},
+		Url:        sql.NullString{String: "https://example.com/post", Valid: true},
+		SourceName: sql.NullString{String: "Blog", Valid: true},
+		Guid:       sql.NullString{String: "tag:example.com,2024:1", Valid: true},
+		Author:     sql.NullString{String: "Jane Doe", Valid: true},
+		ImageUrl:   sql.NullString{String: "https://example.com/lead.jpg", Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", created.Author.String)
+	assert.Equal(t, "https://example.com/lead.jpg", created.ImageUrl.String)
+
+	found, err := queries.GetArticleBySourceGuid(ctx, GetArticleBySourceGuidParams{
+		SourceName: sql.NullString{String: "Blog", Valid: true},
+		Guid:       sql.NullString{String: "tag:example.com,2024:1", Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, found.ID)
+
+	_, err = queries.GetArticleBySourceGuid(ctx, GetArticleBySourceGuidParams{
+		SourceName: sql.NullString{String: "Other", Valid: true},
+		Guid:       sql.NullString{String: "tag:example.com,2024:1", Valid: true},
+	})
+	assert.ErrorIs(t, err, sql.ErrNoRows, "GUIDs are only unique within a source")
+}
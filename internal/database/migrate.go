@@ -0,0 +1,388 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrNoDownMigration is returned when rolling back a migration that has no down step.
+var ErrNoDownMigration = errors.New("migration has no down step")
+
+// Migration is a single versioned schema change with its up and down SQL.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus reports whether a migration has been applied to a database.
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt sql.NullTime
+}
+
+// legacyMarkers describe how to recognise migrations that were already applied
+// to databases created before schema_migrations existed: by the table they
+// create or, if columns is set, every column they add to it.
+var legacyMarkers = []struct {
+	version int64
+	table   string
+	columns []string
+}{
+	{version: 1, table: "articles"},
+	{version: 2, table: "articles", columns: []string{"analysis_status"}},
+	{version: 3, table: "articles", columns: []string{"content"}},
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+		base := strings.TrimSuffix(fileName, ".sql")
+
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s: missing .up or .down suffix", fileName)
+		}
+		base = strings.TrimSuffix(base, "."+direction)
+
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s: expected <version>_<name>", fileName)
+		}
+		version, err := strconv.ParseInt(versionStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version: %w", fileName, err)
+		}
+
+		body, err := fs.ReadFile(migrationFiles, path.Join("migrations", fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, m.Name, name)
+		}
+
+		if direction == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up step", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// MigrateUp applies every pending migration in order and returns the ones applied.
+// Each migration runs in its own transaction together with its schema_migrations row.
+func MigrateUp(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, errs.Wrap("load migrations", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, errs.Wrap("acquire migration connection", err)
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		done, err := runMigration(ctx, conn, m, true)
+		if err != nil {
+			return applied, errs.Wrap(fmt.Sprintf("apply migration %04d_%s", m.Version, m.Name), err)
+		}
+		if done {
+			logging.Info("database_migrate", fmt.Sprintf("Applied migration %04d_%s", m.Version, m.Name))
+			applied = append(applied, m)
+		}
+	}
+
+	return applied, nil
+}
+
+// MigrateDown rolls back the most recently applied migrations, up to steps of them,
+// and returns the ones rolled back.
+func MigrateDown(ctx context.Context, db *sql.DB, steps int) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, errs.Wrap("load migrations", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, errs.Wrap("acquire migration connection", err)
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	appliedVersions, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledBack []Migration
+	for i := len(migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		m := migrations[i]
+		if _, ok := appliedVersions[m.Version]; !ok {
+			continue
+		}
+		if m.Down == "" {
+			return rolledBack, errs.Wrap(fmt.Sprintf("roll back migration %04d_%s", m.Version, m.Name), ErrNoDownMigration)
+		}
+		if _, err := runMigration(ctx, conn, m, false); err != nil {
+			return rolledBack, errs.Wrap(fmt.Sprintf("roll back migration %04d_%s", m.Version, m.Name), err)
+		}
+		logging.Info("database_migrate", fmt.Sprintf("Rolled back migration %04d_%s", m.Version, m.Name))
+		rolledBack = append(rolledBack, m)
+	}
+
+	return rolledBack, nil
+}
+
+// Status lists every known migration together with whether it has been applied.
+func Status(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, errs.Wrap("load migrations", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, errs.Wrap("acquire migration connection", err)
+	}
+	defer conn.Close()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	appliedVersions, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		appliedAt, ok := appliedVersions[m.Version]
+		statuses = append(statuses, MigrationStatus{
+			Migration: m,
+			Applied:   ok,
+			AppliedAt: appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+// SchemaVersion returns the highest applied migration version, or 0 for an
+// empty database.
+func SchemaVersion(ctx context.Context, db *sql.DB) (int64, error) {
+	statuses, err := Status(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	for _, s := range statuses {
+		if s.Applied && s.Version > version {
+			version = s.Version
+		}
+	}
+	return version, nil
+}
+
+func ensureMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at DATETIME NOT NULL
+)`)
+	if err != nil {
+		return errs.Wrap("create schema_migrations table", err)
+	}
+
+	if err := baselineLegacySchema(ctx, conn); err != nil {
+		return errs.Wrap("baseline legacy schema", err)
+	}
+	return nil
+}
+
+// baselineLegacySchema records the migrations already reflected in a database
+// that predates schema_migrations, so they are not applied a second time.
+// Each migration is checked on its own: one whose changes are all present is
+// recorded, and one whose changes are missing is left for MigrateUp to apply,
+// even if a later one is recorded.
+func baselineLegacySchema(ctx context.Context, conn *sql.Conn) error {
+	var recorded int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations").Scan(&recorded); err != nil {
+		return err
+	}
+	if recorded > 0 {
+		return nil
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	names := make(map[int64]string, len(migrations))
+	for _, m := range migrations {
+		names[m.Version] = m.Name
+	}
+
+	now := time.Now().UTC()
+	for _, marker := range legacyMarkers {
+		present, err := schemaHas(ctx, conn, marker.table, marker.columns)
+		if err != nil {
+			return err
+		}
+		if !present {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			marker.version, names[marker.version], now); err != nil {
+			return err
+		}
+		logging.Info("database_migrate", fmt.Sprintf("Baselined existing schema at migration %04d_%s", marker.version, names[marker.version]))
+	}
+	return nil
+}
+
+// schemaHas reports whether table exists with all of columns.
+func schemaHas(ctx context.Context, conn *sql.Conn, table string, columns []string) (bool, error) {
+	var count int
+	err := conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	if err != nil || count == 0 {
+		return false, err
+	}
+	for _, column := range columns {
+		err := conn.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&count)
+		if err != nil || count == 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[int64]sql.NullTime, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, errs.Wrap("list applied migrations", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]sql.NullTime)
+	for rows.Next() {
+		var version int64
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, errs.Wrap("scan applied migration", err)
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// runMigration applies (up) or reverts (down) a single migration inside an
+// immediate transaction so concurrent processes cannot apply it twice. It
+// reports false when there was nothing to do.
+func runMigration(ctx context.Context, conn *sql.Conn, m Migration, up bool) (done bool, err error) {
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil || !done {
+			_, _ = conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+	}()
+
+	var count int
+	if err := conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM schema_migrations WHERE version = ?", m.Version).Scan(&count); err != nil {
+		return false, err
+	}
+	if (count > 0) == up {
+		return false, nil
+	}
+
+	if up {
+		if _, err := conn.ExecContext(ctx, m.Up); err != nil {
+			return false, err
+		}
+		if _, err := conn.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+			m.Version, m.Name, time.Now().UTC()); err != nil {
+			return false, err
+		}
+	} else {
+		if _, err := conn.ExecContext(ctx, m.Down); err != nil {
+			return false, err
+		}
+		if _, err := conn.ExecContext(ctx,
+			"DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,213 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openRawTestDB(t *testing.T) *sql.DB {
+	db, _, err := Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func columnNames(t *testing.T, db *sql.DB, table string) []string {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		names = append(names, name)
+	}
+	require.NoError(t, rows.Err())
+	return names
+}
+
+func TestMigrations_OrderedAndComplete(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.NotEmpty(t, m.Name)
+		assert.NotEmpty(t, m.Up, "migration %d has no up step", m.Version)
+		assert.NotEmpty(t, m.Down, "migration %d has no down step", m.Version)
+		if i > 0 {
+			assert.Greater(t, m.Version, migrations[i-1].Version)
+		}
+	}
+}
+
+func TestMigrateUp_FreshDatabase(t *testing.T) {
+	db := openRawTestDB(t)
+	ctx := context.Background()
+
+	migrations, err := Migrations()
+	require.NoError(t, err)
+
+	applied, err := MigrateUp(ctx, db)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+
+	version, err := SchemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, migrations[len(migrations)-1].Version, version)
+
+	columns := columnNames(t, db, "articles")
+	assert.Contains(t, columns, "analysis_status")
+	assert.Contains(t, columns, "content")
+
+	applied, err = MigrateUp(ctx, db)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "second run should be a no-op")
+}
+
+func TestMigrateDown_RollsBackLatest(t *testing.T) {
+	db := openRawTestDB(t)
+	ctx := context.Background()
+
+	_, err := MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	before, err := SchemaVersion(ctx, db)
+	require.NoError(t, err)
+
+	rolledBack, err := MigrateDown(ctx, db, 1)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, 1)
+	assert.Equal(t, before, rolledBack[0].Version)
+
+	after, err := SchemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Less(t, after, before)
+
+	statuses, err := Status(ctx, db)
+	require.NoError(t, err)
+	last := statuses[len(statuses)-1]
+	assert.False(t, last.Applied)
+	assert.True(t, statuses[0].Applied)
+	assert.True(t, statuses[0].AppliedAt.Valid)
+
+	applied, err := MigrateUp(ctx, db)
+	require.NoError(t, err)
+	assert.Len(t, applied, 1)
+}
+
+func TestMigrateDown_AllTheWay(t *testing.T) {
+	db := openRawTestDB(t)
+	ctx := context.Background()
+
+	applied, err := MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	rolledBack, err := MigrateDown(ctx, db, len(applied)+5)
+	require.NoError(t, err)
+	assert.Len(t, rolledBack, len(applied))
+
+	version, err := SchemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+	assert.Empty(t, columnNames(t, db, "articles"))
+}
+
+func TestMigrateUp_BaselinesLegacyDatabase(t *testing.T) {
+	db := openRawTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE articles (
+		id INTEGER PRIMARY KEY,
+		title TEXT,
+		url TEXT UNIQUE,
+		source_name TEXT,
+		published_date DATETIME,
+		summary TEXT,
+		entities JSON,
+		content_type TEXT,
+		topics JSON,
+		status TEXT DEFAULT 'unread',
+		story_group_id TEXT
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO articles (title, url, status) VALUES ('Old', 'https://example.com/old', 'pending')`)
+	require.NoError(t, err)
+
+	applied, err := MigrateUp(ctx, db)
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+	assert.Equal(t, "add_analysis_status", applied[0].Name)
+
+	var status, analysisStatus string
+	err = db.QueryRow(`SELECT status, analysis_status FROM articles WHERE url = 'https://example.com/old'`).Scan(&status, &analysisStatus)
+	require.NoError(t, err)
+	assert.Equal(t, "unread", status)
+	assert.Equal(t, "pending", analysisStatus)
+
+	assert.Contains(t, columnNames(t, db, "articles"), "content")
+}
+
+func TestMigrateUp_BaselinesEachLegacyMigrationOnItsOwn(t *testing.T) {
+	db := openRawTestDB(t)
+	ctx := context.Background()
+
+	// content without analysis_status: 0003 ran, 0002 never did.
+	_, err := db.Exec(`CREATE TABLE articles (
+		id INTEGER PRIMARY KEY,
+		title TEXT,
+		url TEXT UNIQUE,
+		source_name TEXT,
+		published_date DATETIME,
+		summary TEXT,
+		entities JSON,
+		content_type TEXT,
+		topics JSON,
+		status TEXT DEFAULT 'unread',
+		story_group_id TEXT,
+		content TEXT
+	)`)
+	require.NoError(t, err)
+
+	applied, err := MigrateUp(ctx, db)
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+	assert.Equal(t, int64(2), applied[0].Version)
+	for _, m := range applied[1:] {
+		assert.Greater(t, m.Version, int64(3))
+	}
+	assert.Contains(t, columnNames(t, db, "articles"), "analysis_status")
+}
+
+func TestMigrateUp_BaselinesCurrentUnversionedDatabase(t *testing.T) {
+	db := openRawTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE articles (
+		id INTEGER PRIMARY KEY,
+		title TEXT,
+		url TEXT UNIQUE,
+		source_name TEXT,
+		published_date DATETIME,
+		summary TEXT,
+		entities JSON,
+		content_type TEXT,
+		topics JSON,
+		status TEXT DEFAULT 'unread',
+		analysis_status TEXT DEFAULT 'unprocessed',
+		story_group_id TEXT,
+		content TEXT
+	)`)
+	require.NoError(t, err)
+
+	applied, err := MigrateUp(ctx, db)
+	require.NoError(t, err)
+	for _, m := range applied {
+		assert.Greater(t, m.Version, int64(3), "legacy migration %d_%s should have been baselined", m.Version, m.Name)
+	}
+}
@@ -1,3 +1,4 @@
+-- Baseline articles table as shipped before versioned migrations existed.
 CREATE TABLE IF NOT EXISTS articles (
     id INTEGER PRIMARY KEY,
     title TEXT,
@@ -9,7 +10,5 @@ CREATE TABLE IF NOT EXISTS articles (
     content_type TEXT,
     topics JSON,
     status TEXT DEFAULT 'unread',
-    analysis_status TEXT DEFAULT 'unprocessed',
-    story_group_id TEXT,
-    content TEXT
+    story_group_id TEXT
 );
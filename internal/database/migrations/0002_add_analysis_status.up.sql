@@ -0,0 +1,11 @@
+-- Add the analysis_status column and repair articles that had their
+-- read/unread status corrupted by the AI analysis status.
+ALTER TABLE articles ADD COLUMN analysis_status TEXT DEFAULT 'unprocessed';
+
+-- Articles with status 'pending', 'completed', or 'unprocessed' get that value
+-- moved to analysis_status and their status reset to 'unread', since they
+-- were never actually read.
+UPDATE articles
+SET analysis_status = status,
+    status = 'unread'
+WHERE status IN ('unprocessed', 'pending', 'completed');
@@ -0,0 +1,3 @@
+-- Store full article content so the TUI can show articles without
+-- fetching them in real time.
+ALTER TABLE articles ADD COLUMN content TEXT;
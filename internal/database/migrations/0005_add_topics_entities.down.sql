@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS articles_taxonomy_after_delete;
+DROP TABLE IF EXISTS article_entities;
+DROP TABLE IF EXISTS entities;
+DROP TABLE IF EXISTS article_topics;
+DROP TABLE IF EXISTS topics;
@@ -0,0 +1,74 @@
+-- Normalized topics and entities extracted by AI analysis. Names are unique
+-- case-insensitively so "AI" and "ai" share a row, and lookups are exact
+-- instead of substring matches over the JSON columns.
+CREATE TABLE IF NOT EXISTS topics (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL COLLATE NOCASE UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS article_topics (
+    article_id INTEGER NOT NULL REFERENCES articles (id),
+    topic_id INTEGER NOT NULL REFERENCES topics (id),
+    PRIMARY KEY (article_id, topic_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_topics_topic_id ON article_topics (topic_id);
+
+-- kind is one of 'organization', 'product' or 'person'.
+CREATE TABLE IF NOT EXISTS entities (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL COLLATE NOCASE,
+    kind TEXT NOT NULL,
+    UNIQUE (name, kind)
+);
+
+CREATE TABLE IF NOT EXISTS article_entities (
+    article_id INTEGER NOT NULL REFERENCES articles (id),
+    entity_id INTEGER NOT NULL REFERENCES entities (id),
+    PRIMARY KEY (article_id, entity_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_entities_entity_id ON article_entities (entity_id);
+
+CREATE TRIGGER IF NOT EXISTS articles_taxonomy_after_delete AFTER DELETE ON articles BEGIN
+    DELETE FROM article_topics WHERE article_id = old.id;
+    DELETE FROM article_entities WHERE article_id = old.id;
+END;
+
+-- Backfill from the JSON columns. They may hold text or blobs, and older rows
+-- may contain invalid JSON, so every value is cast and validated first.
+INSERT OR IGNORE INTO topics (name)
+SELECT trim(j.value)
+FROM articles a,
+    json_each(CASE WHEN json_valid(CAST(a.topics AS TEXT)) THEN CAST(a.topics AS TEXT) ELSE '[]' END) j
+WHERE j.type = 'text' AND trim(j.value) != '';
+
+INSERT OR IGNORE INTO article_topics (article_id, topic_id)
+SELECT a.id, t.id
+FROM articles a,
+    json_each(CASE WHEN json_valid(CAST(a.topics AS TEXT)) THEN CAST(a.topics AS TEXT) ELSE '[]' END) j
+JOIN topics t ON t.name = trim(j.value)
+WHERE j.type = 'text';
+
+INSERT OR IGNORE INTO entities (name, kind)
+SELECT trim(j.value), k.kind
+FROM articles a
+JOIN (
+    SELECT 'organization' AS kind, '$.organizations' AS path
+    UNION ALL SELECT 'product', '$.products'
+    UNION ALL SELECT 'person', '$.people'
+) k,
+    json_each(CASE WHEN json_valid(CAST(a.entities AS TEXT)) THEN CAST(a.entities AS TEXT) ELSE '{}' END, k.path) j
+WHERE j.type = 'text' AND trim(j.value) != '';
+
+INSERT OR IGNORE INTO article_entities (article_id, entity_id)
+SELECT a.id, e.id
+FROM articles a
+JOIN (
+    SELECT 'organization' AS kind, '$.organizations' AS path
+    UNION ALL SELECT 'product', '$.products'
+    UNION ALL SELECT 'person', '$.people'
+) k,
+    json_each(CASE WHEN json_valid(CAST(a.entities AS TEXT)) THEN CAST(a.entities AS TEXT) ELSE '{}' END, k.path) j
+JOIN entities e ON e.name = trim(j.value) AND e.kind = k.kind
+WHERE j.type = 'text';
@@ -0,0 +1,9 @@
+-- HTTP cache validators per feed URL, used to send conditional requests and
+-- to skip feeds whose body has not changed since the last fetch.
+CREATE TABLE IF NOT EXISTS feeds (
+    source_url TEXT NOT NULL PRIMARY KEY,
+    etag TEXT,
+    last_modified TEXT,
+    body_hash TEXT,
+    checked_at DATETIME
+);
@@ -0,0 +1,8 @@
+ALTER TABLE feeds DROP COLUMN quarantined_until;
+ALTER TABLE feeds DROP COLUMN items_seen;
+ALTER TABLE feeds DROP COLUMN total_latency_ms;
+ALTER TABLE feeds DROP COLUMN fetch_count;
+ALTER TABLE feeds DROP COLUMN consecutive_failures;
+ALTER TABLE feeds DROP COLUMN last_error_at;
+ALTER TABLE feeds DROP COLUMN last_error;
+ALTER TABLE feeds DROP COLUMN last_success_at;
@@ -0,0 +1,10 @@
+-- Fetch health per feed URL. Average latency is total_latency_ms / fetch_count.
+-- A feed is skipped until quarantined_until after too many consecutive failures.
+ALTER TABLE feeds ADD COLUMN last_success_at DATETIME;
+ALTER TABLE feeds ADD COLUMN last_error TEXT;
+ALTER TABLE feeds ADD COLUMN last_error_at DATETIME;
+ALTER TABLE feeds ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD COLUMN fetch_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD COLUMN total_latency_ms INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD COLUMN items_seen INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD COLUMN quarantined_until DATETIME;
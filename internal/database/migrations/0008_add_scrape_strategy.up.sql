@@ -0,0 +1,3 @@
+-- Record which extraction strategy produced an article's content:
+-- 'jina', 'readability' or 'feed' (the feed item's own content).
+ALTER TABLE articles ADD COLUMN scrape_strategy TEXT;
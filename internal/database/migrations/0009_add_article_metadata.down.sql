@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS articles_metadata_after_delete;
+DROP TABLE IF EXISTS article_enclosures;
+DROP TABLE IF EXISTS article_categories;
+DROP TABLE IF EXISTS article_authors;
+DROP TABLE IF EXISTS authors;
+DROP INDEX IF EXISTS idx_articles_source_guid;
+ALTER TABLE articles DROP COLUMN image_url;
+ALTER TABLE articles DROP COLUMN author;
+ALTER TABLE articles DROP COLUMN guid;
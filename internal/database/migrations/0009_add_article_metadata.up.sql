@@ -0,0 +1,45 @@
+-- Metadata carried by feed items. guid is the item's own identifier (RSS
+-- <guid>, Atom <id>, JSON Feed id) and is used alongside the URL to spot
+-- items already stored. author is the display string of all authors; the
+-- normalized names live in authors/article_authors for filtering.
+ALTER TABLE articles ADD COLUMN guid TEXT;
+ALTER TABLE articles ADD COLUMN author TEXT;
+ALTER TABLE articles ADD COLUMN image_url TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_articles_source_guid ON articles (source_name, guid);
+
+CREATE TABLE IF NOT EXISTS authors (
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL COLLATE NOCASE UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS article_authors (
+    article_id INTEGER NOT NULL REFERENCES articles (id),
+    author_id INTEGER NOT NULL REFERENCES authors (id),
+    PRIMARY KEY (article_id, author_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_authors_author_id ON article_authors (author_id);
+
+-- Categories are the feed's own tags, kept as published rather than merged
+-- into the AI-assigned topics.
+CREATE TABLE IF NOT EXISTS article_categories (
+    article_id INTEGER NOT NULL REFERENCES articles (id),
+    category TEXT NOT NULL COLLATE NOCASE,
+    PRIMARY KEY (article_id, category)
+);
+
+CREATE TABLE IF NOT EXISTS article_enclosures (
+    id INTEGER PRIMARY KEY,
+    article_id INTEGER NOT NULL REFERENCES articles (id),
+    url TEXT NOT NULL,
+    mime_type TEXT,
+    length INTEGER,
+    UNIQUE (article_id, url)
+);
+
+CREATE TRIGGER IF NOT EXISTS articles_metadata_after_delete AFTER DELETE ON articles BEGIN
+    DELETE FROM article_authors WHERE article_id = old.id;
+    DELETE FROM article_categories WHERE article_id = old.id;
+    DELETE FROM article_enclosures WHERE article_id = old.id;
+END;
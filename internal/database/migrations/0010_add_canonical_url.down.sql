@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_articles_canonical_url;
+ALTER TABLE articles DROP COLUMN canonical_url;
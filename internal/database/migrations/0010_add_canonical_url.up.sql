@@ -0,0 +1,8 @@
+-- Duplicate-detection key of the article URL (see internal/urlnorm): the
+-- same post reached through http/https, tracking parameters or AMP variants
+-- shares one key. Existing rows are left NULL, which the unique index allows
+-- any number of; 'ai-news db canonicalize' fills them in and merges the
+-- duplicates it finds.
+ALTER TABLE articles ADD COLUMN canonical_url TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_canonical_url ON articles (canonical_url);
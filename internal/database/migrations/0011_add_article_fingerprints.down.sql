@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS articles_fingerprint_after_delete;
+DROP INDEX IF EXISTS idx_article_fingerprints_published_at;
+DROP TABLE IF EXISTS article_fingerprints;
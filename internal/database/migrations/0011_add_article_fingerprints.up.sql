@@ -0,0 +1,16 @@
+-- Locality-sensitive fingerprints of each article's normalized title and
+-- content (see internal/story), used to group near-duplicate articles from
+-- different sources into stories. published_at is the article's publish time
+-- in Unix seconds, so that the clustering window is a plain range scan.
+CREATE TABLE IF NOT EXISTS article_fingerprints (
+    article_id INTEGER PRIMARY KEY REFERENCES articles (id),
+    simhash INTEGER NOT NULL,
+    minhash BLOB NOT NULL,
+    published_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_fingerprints_published_at ON article_fingerprints (published_at);
+
+CREATE TRIGGER IF NOT EXISTS articles_fingerprint_after_delete AFTER DELETE ON articles BEGIN
+    DELETE FROM article_fingerprints WHERE article_id = old.id;
+END;
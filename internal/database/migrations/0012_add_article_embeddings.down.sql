@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS articles_embedding_after_delete;
+DROP INDEX IF EXISTS idx_article_embeddings_model;
+DROP TABLE IF EXISTS article_embeddings;
@@ -0,0 +1,17 @@
+-- Embedding vector of each article for semantic search, stored as
+-- little-endian float32 values. Vectors from different models cannot be
+-- compared, so each is stored with the model that produced it; an article
+-- embedded again with another model replaces its old vector.
+CREATE TABLE IF NOT EXISTS article_embeddings (
+    article_id INTEGER PRIMARY KEY REFERENCES articles (id),
+    model TEXT NOT NULL,
+    dimensions INTEGER NOT NULL,
+    vector BLOB NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_embeddings_model ON article_embeddings (model);
+
+CREATE TRIGGER IF NOT EXISTS articles_embedding_after_delete AFTER DELETE ON articles BEGIN
+    DELETE FROM article_embeddings WHERE article_id = old.id;
+END;
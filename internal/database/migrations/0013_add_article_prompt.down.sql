@@ -0,0 +1,2 @@
+ALTER TABLE articles DROP COLUMN prompt_version;
+ALTER TABLE articles DROP COLUMN prompt_name;
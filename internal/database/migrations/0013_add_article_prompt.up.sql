@@ -0,0 +1,4 @@
+-- Record which prompt template analyzed an article: its name and a hash of
+-- its text, so that analyses from different prompt versions can be told apart.
+ALTER TABLE articles ADD COLUMN prompt_name TEXT;
+ALTER TABLE articles ADD COLUMN prompt_version TEXT;
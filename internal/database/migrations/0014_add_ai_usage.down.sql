@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_ai_usage_created_at;
+DROP TABLE IF EXISTS ai_usage;
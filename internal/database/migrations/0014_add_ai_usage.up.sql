@@ -0,0 +1,18 @@
+-- One row per AI call: the tokens it used and how long it took. run_id
+-- groups the calls of one fetch; article_id is the article analyzed, if it
+-- was stored. created_at is a unix timestamp so that reports can select
+-- periods by comparing integers.
+CREATE TABLE IF NOT EXISTS ai_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    run_id TEXT NOT NULL,
+    article_id INTEGER,
+    source_name TEXT NOT NULL DEFAULT '',
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    output_tokens INTEGER NOT NULL DEFAULT 0,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ai_usage_created_at ON ai_usage (created_at);
@@ -0,0 +1,12 @@
+-- Analyses keyed by a hash of the normalized content, the models that
+-- produced them and the version of the prompt, so that copies of an article
+-- found under other URLs are not analyzed again. result is the analysis as
+-- JSON; created_at is a unix timestamp.
+CREATE TABLE IF NOT EXISTS ai_cache (
+    content_hash TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_version TEXT NOT NULL,
+    result TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (content_hash, model, prompt_version)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE articles DROP COLUMN analysis_error;
+ALTER TABLE articles DROP COLUMN analysis_attempts;
@@ -0,0 +1,4 @@
+-- Count failed attempts to analyze an article and keep the last error, so
+-- that the backlog processor can give up on articles that keep failing.
+ALTER TABLE articles ADD COLUMN analysis_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE articles ADD COLUMN analysis_error TEXT;
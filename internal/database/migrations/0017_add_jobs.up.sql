@@ -0,0 +1,24 @@
+-- Durable queue of work on stored articles. A job is 'queued' until a
+-- worker leases it, 'running' while lease_owner holds it, which lasts until
+-- lease_expires_at, and 'failed' once it has run out of attempts; finished
+-- jobs are deleted. A running job whose lease has expired is taken to have
+-- been abandoned and may be leased again. Times are unix timestamps.
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    article_id INTEGER NOT NULL REFERENCES articles (id),
+    state TEXT NOT NULL DEFAULT 'queued',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_run_at INTEGER NOT NULL,
+    lease_owner TEXT,
+    lease_expires_at INTEGER,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_state_next_run_at ON jobs (state, next_run_at);
+
+-- An article has at most one queued or running job of each kind.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_active ON jobs (kind, article_id)
+WHERE state IN ('queued', 'running');
@@ -0,0 +1 @@
+ALTER TABLE feeds DROP COLUMN item_limit;
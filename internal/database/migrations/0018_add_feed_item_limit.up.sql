@@ -0,0 +1,5 @@
+-- Remember how many items of a feed were taken when its validators were
+-- saved, so that a fetch with a larger limit does not skip the feed as
+-- unchanged. Zero means all of them; NULL, for feeds fetched before this
+-- was recorded, that it is not known.
+ALTER TABLE feeds ADD COLUMN item_limit INTEGER;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS canonical_links;
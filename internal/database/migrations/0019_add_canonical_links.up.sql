@@ -0,0 +1,8 @@
+-- Canonical links read from article pages, keyed like articles.canonical_url
+-- by the feed link they were found for, so that an item whose feed link
+-- differs from its canonical URL is recognized without downloading its page
+-- on every fetch.
+CREATE TABLE IF NOT EXISTS canonical_links (
+    link_key TEXT NOT NULL PRIMARY KEY,
+    canonical_url TEXT NOT NULL
+);
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS articles_embedding_after_update;
@@ -0,0 +1,8 @@
+-- An article's vector is computed from its title, summary and content, so
+-- it is dropped when any of them changes, as when a pending article is
+-- scraped or analyzed, and the next semantic search embeds it again.
+CREATE TRIGGER IF NOT EXISTS articles_embedding_after_update AFTER UPDATE OF title, summary, content ON articles
+WHEN old.title IS NOT new.title OR old.summary IS NOT new.summary OR old.content IS NOT new.content
+BEGIN
+    DELETE FROM article_embeddings WHERE article_id = old.id;
+END;
@@ -8,18 +8,138 @@ import (
 	"database/sql"
 )
 
+type AiCache struct {
+	ContentHash   string
+	Model         string
+	PromptVersion string
+	Result        string
+	CreatedAt     int64
+}
+
+type AiUsage struct {
+	ID           int64
+	RunID        string
+	ArticleID    sql.NullInt64
+	SourceName   string
+	Provider     string
+	Model        string
+	PromptTokens int64
+	OutputTokens int64
+	LatencyMs    int64
+	CreatedAt    int64
+}
+
 type Article struct {
+	ID               int64
+	Title            sql.NullString
+	Url              sql.NullString
+	SourceName       sql.NullString
+	PublishedDate    sql.NullTime
+	Summary          sql.NullString
+	Entities         interface{}
+	ContentType      sql.NullString
+	Topics           interface{}
+	Status           sql.NullString
+	StoryGroupID     sql.NullString
+	AnalysisStatus   sql.NullString
+	Content          sql.NullString
+	ScrapeStrategy   sql.NullString
+	Guid             sql.NullString
+	Author           sql.NullString
+	ImageUrl         sql.NullString
+	CanonicalUrl     sql.NullString
+	PromptName       sql.NullString
+	PromptVersion    sql.NullString
+	AnalysisAttempts int64
+	AnalysisError    sql.NullString
+}
+
+type ArticleAuthor struct {
+	ArticleID int64
+	AuthorID  int64
+}
+
+type ArticleCategory struct {
+	ArticleID int64
+	Category  string
+}
+
+type ArticleEmbedding struct {
+	ArticleID  int64
+	Model      string
+	Dimensions int64
+	Vector     []byte
+	CreatedAt  sql.NullTime
+}
+
+type ArticleEnclosure struct {
+	ID        int64
+	ArticleID int64
+	Url       string
+	MimeType  sql.NullString
+	Length    sql.NullInt64
+}
+
+type ArticleEntity struct {
+	ArticleID int64
+	EntityID  int64
+}
+
+type ArticleFingerprint struct {
+	ArticleID   int64
+	Simhash     int64
+	Minhash     []byte
+	PublishedAt int64
+}
+
+type ArticleTopic struct {
+	ArticleID int64
+	TopicID   int64
+}
+
+type Author struct {
+	ID   int64
+	Name string
+}
+
+type Entity struct {
+	ID   int64
+	Name string
+	Kind string
+}
+
+type Feed struct {
+	SourceUrl           string
+	Etag                sql.NullString
+	LastModified        sql.NullString
+	BodyHash            sql.NullString
+	CheckedAt           sql.NullTime
+	LastSuccessAt       sql.NullTime
+	LastError           sql.NullString
+	LastErrorAt         sql.NullTime
+	ConsecutiveFailures int64
+	FetchCount          int64
+	TotalLatencyMs      int64
+	ItemsSeen           int64
+	QuarantinedUntil    sql.NullTime
+	ItemLimit           sql.NullInt64
+}
+
+type Job struct {
 	ID             int64
-	Title          sql.NullString
-	Url            sql.NullString
-	SourceName     sql.NullString
-	PublishedDate  sql.NullTime
-	Summary        sql.NullString
-	Entities       interface{}
-	ContentType    sql.NullString
-	Topics         interface{}
-	Status         sql.NullString
-	AnalysisStatus sql.NullString
-	StoryGroupID   sql.NullString
-	Content        sql.NullString
+	Kind           string
+	ArticleID      int64
+	State          string
+	Attempts       int64
+	LastError      sql.NullString
+	NextRunAt      int64
+	LeaseOwner     sql.NullString
+	LeaseExpiresAt sql.NullInt64
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+type Topic struct {
+	ID   int64
+	Name string
 }
@@ -11,9 +11,18 @@ INSERT INTO articles (
     status,
     analysis_status,
     story_group_id,
-    content
+    content,
+    scrape_strategy,
+    guid,
+    author,
+    image_url,
+    canonical_url,
+    prompt_name,
+    prompt_version,
+    analysis_attempts,
+    analysis_error
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 ) RETURNING *;
 
 -- name: GetArticleByUrl :one
@@ -32,19 +41,125 @@ SELECT * FROM articles ORDER BY published_date DESC;
 SELECT * FROM articles WHERE status != 'read' AND source_name = ? ORDER BY published_date DESC;
 
 -- name: ListArticlesByTopic :many
-SELECT * FROM articles WHERE status != 'read' AND JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC;
+SELECT * FROM articles WHERE status != 'read' AND id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC;
 
 -- name: ListArticlesBySourceAndTopic :many
-SELECT * FROM articles WHERE status != 'read' AND source_name = ? AND JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC;
+SELECT * FROM articles WHERE status != 'read' AND source_name = ? AND id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC;
 
 -- name: ListAllArticlesBySource :many
 SELECT * FROM articles WHERE source_name = ? ORDER BY published_date DESC;
 
 -- name: ListAllArticlesByTopic :many
-SELECT * FROM articles WHERE JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC;
+SELECT * FROM articles WHERE id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC;
 
 -- name: ListAllArticlesBySourceAndTopic :many
-SELECT * FROM articles WHERE source_name = ? AND JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC;
+SELECT * FROM articles WHERE source_name = ? AND id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC;
+
+-- name: ListArticlesByEntity :many
+SELECT * FROM articles
+WHERE (CAST(sqlc.arg(include_read) AS BOOLEAN) OR status != 'read')
+    AND (sqlc.narg(source_name) IS NULL OR source_name = sqlc.narg(source_name))
+    AND (sqlc.narg(topic) IS NULL OR id IN (
+        SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = sqlc.narg(topic)
+    ))
+    AND id IN (
+        SELECT ae.article_id FROM article_entities ae JOIN entities e ON e.id = ae.entity_id WHERE e.name = sqlc.arg(entity)
+    )
+ORDER BY published_date DESC;
+
+-- name: UpsertTopic :one
+INSERT INTO topics (name) VALUES (?)
+ON CONFLICT (name) DO UPDATE SET name = topics.name
+RETURNING id;
+
+-- name: UpsertEntity :one
+INSERT INTO entities (name, kind) VALUES (?, ?)
+ON CONFLICT (name, kind) DO UPDATE SET name = entities.name
+RETURNING id;
+
+-- name: LinkArticleTopic :exec
+INSERT OR IGNORE INTO article_topics (article_id, topic_id) VALUES (?, ?);
+
+-- name: LinkArticleEntity :exec
+INSERT OR IGNORE INTO article_entities (article_id, entity_id) VALUES (?, ?);
+
+-- name: ListArticleTopics :many
+SELECT t.name FROM topics t
+JOIN article_topics at ON at.topic_id = t.id
+WHERE at.article_id = ?
+ORDER BY t.name;
+
+-- name: ListArticleEntities :many
+SELECT e.name, e.kind FROM entities e
+JOIN article_entities ae ON ae.entity_id = e.id
+WHERE ae.article_id = ?
+ORDER BY e.kind, e.name;
+
+-- name: GetArticleByCanonicalUrl :one
+SELECT * FROM articles WHERE canonical_url = ? LIMIT 1;
+
+-- name: ListArticleURLs :many
+SELECT id, url, canonical_url, status, analysis_status FROM articles
+WHERE url IS NOT NULL AND url != ''
+ORDER BY id;
+
+-- name: UpdateArticleURL :exec
+UPDATE articles SET url = ?, canonical_url = ? WHERE id = ?;
+
+-- name: DeleteArticle :exec
+DELETE FROM articles WHERE id = ?;
+
+-- name: GetArticleBySourceGuid :one
+SELECT * FROM articles WHERE source_name = ? AND guid = ? LIMIT 1;
+
+-- name: ListArticlesByAuthor :many
+SELECT * FROM articles
+WHERE (CAST(sqlc.arg(include_read) AS BOOLEAN) OR status != 'read')
+    AND (sqlc.narg(source_name) IS NULL OR source_name = sqlc.narg(source_name))
+    AND (sqlc.narg(topic) IS NULL OR id IN (
+        SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = sqlc.narg(topic)
+    ))
+    AND (sqlc.narg(entity) IS NULL OR id IN (
+        SELECT ae.article_id FROM article_entities ae JOIN entities e ON e.id = ae.entity_id WHERE e.name = sqlc.narg(entity)
+    ))
+    AND id IN (
+        SELECT aa.article_id FROM article_authors aa JOIN authors au ON au.id = aa.author_id WHERE au.name = sqlc.arg(author)
+    )
+ORDER BY published_date DESC;
+
+-- name: UpsertAuthor :one
+INSERT INTO authors (name) VALUES (?)
+ON CONFLICT (name) DO UPDATE SET name = authors.name
+RETURNING id;
+
+-- name: LinkArticleAuthor :exec
+INSERT OR IGNORE INTO article_authors (article_id, author_id) VALUES (?, ?);
+
+-- name: AddArticleCategory :exec
+INSERT OR IGNORE INTO article_categories (article_id, category) VALUES (?, ?);
+
+-- name: AddArticleEnclosure :exec
+INSERT OR IGNORE INTO article_enclosures (article_id, url, mime_type, length) VALUES (?, ?, ?, ?);
+
+-- name: ListArticleAuthors :many
+SELECT au.name FROM authors au
+JOIN article_authors aa ON aa.author_id = au.id
+WHERE aa.article_id = ?
+ORDER BY au.name;
+
+-- name: ListArticleCategories :many
+SELECT category FROM article_categories WHERE article_id = ? ORDER BY category;
+
+-- name: ListArticleEnclosures :many
+SELECT * FROM article_enclosures WHERE article_id = ? ORDER BY id;
 
 -- name: MarkArticlesAsRead :exec
 UPDATE articles SET status = 'read' WHERE id IN (sqlc.slice('ids'));
@@ -66,3 +181,197 @@ SELECT * FROM articles WHERE analysis_status = 'unprocessed' ORDER BY published_
 
 -- name: ListPendingArticles :many
 SELECT * FROM articles WHERE analysis_status = 'pending' ORDER BY published_date DESC;
+
+-- name: ListBacklogArticles :many
+SELECT * FROM articles
+WHERE analysis_status IN ('pending', 'unprocessed', 'failed') AND analysis_attempts < ?
+ORDER BY analysis_status = 'unprocessed', published_date DESC;
+
+-- name: GetFeed :one
+SELECT * FROM feeds WHERE source_url = ? LIMIT 1;
+
+-- name: UpsertFeed :exec
+INSERT INTO feeds (source_url, etag, last_modified, body_hash, item_limit, checked_at)
+VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (source_url) DO UPDATE SET
+    etag = excluded.etag,
+    last_modified = excluded.last_modified,
+    body_hash = excluded.body_hash,
+    item_limit = excluded.item_limit,
+    checked_at = excluded.checked_at;
+
+-- name: ListFeeds :many
+SELECT * FROM feeds ORDER BY source_url;
+
+-- name: RecordFeedSuccess :exec
+INSERT INTO feeds (source_url, last_success_at, fetch_count, total_latency_ms, items_seen)
+VALUES (?, ?, 1, ?, ?)
+ON CONFLICT (source_url) DO UPDATE SET
+    last_success_at = excluded.last_success_at,
+    consecutive_failures = 0,
+    quarantined_until = NULL,
+    fetch_count = feeds.fetch_count + 1,
+    total_latency_ms = feeds.total_latency_ms + excluded.total_latency_ms,
+    items_seen = feeds.items_seen + excluded.items_seen;
+
+-- name: RecordFeedFailure :one
+INSERT INTO feeds (source_url, last_error, last_error_at, consecutive_failures, fetch_count, total_latency_ms)
+VALUES (?, ?, ?, 1, 1, ?)
+ON CONFLICT (source_url) DO UPDATE SET
+    last_error = excluded.last_error,
+    last_error_at = excluded.last_error_at,
+    consecutive_failures = feeds.consecutive_failures + 1,
+    fetch_count = feeds.fetch_count + 1,
+    total_latency_ms = feeds.total_latency_ms + excluded.total_latency_ms
+RETURNING consecutive_failures;
+
+-- name: QuarantineFeed :exec
+UPDATE feeds SET quarantined_until = ? WHERE source_url = ?;
+
+-- name: ListUnclusteredArticles :many
+SELECT * FROM articles
+WHERE id NOT IN (SELECT article_id FROM article_fingerprints)
+ORDER BY published_date, id;
+
+-- name: ListStoryCandidates :many
+SELECT f.article_id, f.simhash, f.minhash, a.story_group_id
+FROM article_fingerprints f JOIN articles a ON a.id = f.article_id
+WHERE f.published_at BETWEEN ?1 AND ?2 AND f.article_id != ?3
+ORDER BY f.published_at, f.article_id;
+
+-- name: UpsertArticleFingerprint :exec
+INSERT INTO article_fingerprints (article_id, simhash, minhash, published_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (article_id) DO UPDATE SET
+    simhash = excluded.simhash,
+    minhash = excluded.minhash,
+    published_at = excluded.published_at;
+
+-- name: UpdateArticleStoryGroup :exec
+UPDATE articles SET story_group_id = ? WHERE id = ?;
+
+-- name: DeleteArticleFingerprints :exec
+DELETE FROM article_fingerprints;
+
+-- name: ListArticlesWithoutEmbedding :many
+SELECT * FROM articles
+WHERE id NOT IN (SELECT article_id FROM article_embeddings WHERE model = ?)
+ORDER BY id;
+
+-- name: ListArticleEmbeddings :many
+SELECT article_id, vector FROM article_embeddings WHERE model = ? ORDER BY article_id;
+
+-- name: UpsertArticleEmbedding :exec
+INSERT INTO article_embeddings (article_id, model, dimensions, vector, created_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (article_id) DO UPDATE SET
+    model = excluded.model,
+    dimensions = excluded.dimensions,
+    vector = excluded.vector,
+    created_at = excluded.created_at;
+
+-- name: CreateAIUsage :exec
+INSERT INTO ai_usage (
+    run_id, article_id, source_name, provider, model,
+    prompt_tokens, output_tokens, latency_ms, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListAIUsageSince :many
+SELECT source_name, model, prompt_tokens, output_tokens, latency_ms, created_at
+FROM ai_usage
+WHERE created_at >= ?
+ORDER BY created_at, id;
+
+-- name: SumAIUsageByModelSince :many
+SELECT model, CAST(SUM(prompt_tokens) AS INTEGER) AS prompt_tokens, CAST(SUM(output_tokens) AS INTEGER) AS output_tokens
+FROM ai_usage
+WHERE created_at >= ?
+GROUP BY model
+ORDER BY model;
+
+-- name: GetAICacheEntry :one
+SELECT result FROM ai_cache
+WHERE content_hash = ? AND model = ? AND prompt_version = ?;
+
+-- name: PutAICacheEntry :exec
+INSERT INTO ai_cache (content_hash, model, prompt_version, result, created_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (content_hash, model, prompt_version)
+DO UPDATE SET result = excluded.result, created_at = excluded.created_at;
+
+-- name: DeleteAICache :execrows
+DELETE FROM ai_cache;
+
+-- name: GetCanonicalLink :one
+SELECT canonical_url FROM canonical_links WHERE link_key = ?;
+
+-- name: PutCanonicalLink :exec
+INSERT INTO canonical_links (link_key, canonical_url)
+VALUES (?, ?)
+ON CONFLICT (link_key) DO UPDATE SET canonical_url = excluded.canonical_url;
+
+-- name: UpdateArticleContent :exec
+UPDATE articles SET content = ?, scrape_strategy = ? WHERE id = ?;
+
+-- name: CompleteArticleAnalysis :exec
+UPDATE articles SET
+    summary = ?,
+    entities = ?,
+    topics = ?,
+    content_type = ?,
+    prompt_name = ?,
+    prompt_version = ?,
+    analysis_status = 'completed',
+    analysis_error = NULL
+WHERE id = ?;
+
+-- name: RecordArticleAnalysisFailure :exec
+UPDATE articles SET analysis_status = ?, analysis_attempts = ?, analysis_error = ?
+WHERE id = ?;
+
+-- name: EnqueueJob :execrows
+INSERT INTO jobs (kind, article_id, next_run_at, created_at, updated_at)
+VALUES (sqlc.arg(kind), sqlc.arg(article_id), sqlc.arg(now), sqlc.arg(now), sqlc.arg(now))
+ON CONFLICT (kind, article_id) WHERE state IN ('queued', 'running') DO NOTHING;
+
+-- name: LeaseJob :one
+UPDATE jobs SET
+    state = 'running',
+    attempts = attempts + 1,
+    lease_owner = sqlc.arg(owner),
+    lease_expires_at = sqlc.arg(lease_expires_at),
+    updated_at = sqlc.arg(now)
+WHERE id = (
+    SELECT id FROM jobs
+    WHERE (state = 'queued' AND next_run_at <= sqlc.arg(now))
+        OR (state = 'running' AND lease_expires_at <= sqlc.arg(now))
+    ORDER BY next_run_at, id
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: RenewJobLease :execrows
+UPDATE jobs SET lease_expires_at = sqlc.arg(lease_expires_at), updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND state = 'running' AND lease_owner = sqlc.arg(owner);
+
+-- name: CompleteJob :execrows
+DELETE FROM jobs WHERE id = ? AND state = 'running' AND lease_owner = ?;
+
+-- name: ReleaseJob :execrows
+UPDATE jobs SET
+    state = sqlc.arg(state),
+    attempts = sqlc.arg(attempts),
+    last_error = sqlc.arg(last_error),
+    next_run_at = sqlc.arg(next_run_at),
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    updated_at = sqlc.arg(now)
+WHERE id = sqlc.arg(id) AND state = 'running' AND lease_owner = sqlc.arg(owner);
+
+-- name: ListActiveJobArticleIDs :many
+SELECT DISTINCT article_id FROM jobs WHERE state IN ('queued', 'running') ORDER BY article_id;
+
+-- name: CountJobs :many
+SELECT kind, state, COUNT(*) AS count FROM jobs
+GROUP BY kind, state
+ORDER BY kind, state;
@@ -11,6 +11,163 @@ import (
 	"strings"
 )
 
+const addArticleCategory = `-- name: AddArticleCategory :exec
+INSERT OR IGNORE INTO article_categories (article_id, category) VALUES (?, ?)
+`
+
+type AddArticleCategoryParams struct {
+	ArticleID int64
+	Category  string
+}
+
+func (q *Queries) AddArticleCategory(ctx context.Context, arg AddArticleCategoryParams) error {
+	_, err := q.db.ExecContext(ctx, addArticleCategory, arg.ArticleID, arg.Category)
+	return err
+}
+
+const addArticleEnclosure = `-- name: AddArticleEnclosure :exec
+INSERT OR IGNORE INTO article_enclosures (article_id, url, mime_type, length) VALUES (?, ?, ?, ?)
+`
+
+type AddArticleEnclosureParams struct {
+	ArticleID int64
+	Url       string
+	MimeType  sql.NullString
+	Length    sql.NullInt64
+}
+
+func (q *Queries) AddArticleEnclosure(ctx context.Context, arg AddArticleEnclosureParams) error {
+	_, err := q.db.ExecContext(ctx, addArticleEnclosure,
+		arg.ArticleID,
+		arg.Url,
+		arg.MimeType,
+		arg.Length,
+	)
+	return err
+}
+
+const createAIUsage = `-- name: CreateAIUsage :exec
+INSERT INTO ai_usage (
+    run_id, article_id, source_name, provider, model,
+    prompt_tokens, output_tokens, latency_ms, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateAIUsageParams struct {
+	RunID        string
+	ArticleID    sql.NullInt64
+	SourceName   string
+	Provider     string
+	Model        string
+	PromptTokens int64
+	OutputTokens int64
+	LatencyMs    int64
+	CreatedAt    int64
+}
+
+func (q *Queries) CreateAIUsage(ctx context.Context, arg CreateAIUsageParams) error {
+	_, err := q.db.ExecContext(ctx, createAIUsage,
+		arg.RunID,
+		arg.ArticleID,
+		arg.SourceName,
+		arg.Provider,
+		arg.Model,
+		arg.PromptTokens,
+		arg.OutputTokens,
+		arg.LatencyMs,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const completeArticleAnalysis = `-- name: CompleteArticleAnalysis :exec
+UPDATE articles SET
+    summary = ?,
+    entities = ?,
+    topics = ?,
+    content_type = ?,
+    prompt_name = ?,
+    prompt_version = ?,
+    analysis_status = 'completed',
+    analysis_error = NULL
+WHERE id = ?
+`
+
+type CompleteArticleAnalysisParams struct {
+	Summary       sql.NullString
+	Entities      interface{}
+	Topics        interface{}
+	ContentType   sql.NullString
+	PromptName    sql.NullString
+	PromptVersion sql.NullString
+	ID            int64
+}
+
+func (q *Queries) CompleteArticleAnalysis(ctx context.Context, arg CompleteArticleAnalysisParams) error {
+	_, err := q.db.ExecContext(ctx, completeArticleAnalysis,
+		arg.Summary,
+		arg.Entities,
+		arg.Topics,
+		arg.ContentType,
+		arg.PromptName,
+		arg.PromptVersion,
+		arg.ID,
+	)
+	return err
+}
+
+const completeJob = `-- name: CompleteJob :execrows
+DELETE FROM jobs WHERE id = ? AND state = 'running' AND lease_owner = ?
+`
+
+type CompleteJobParams struct {
+	ID         int64
+	LeaseOwner sql.NullString
+}
+
+func (q *Queries) CompleteJob(ctx context.Context, arg CompleteJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeJob, arg.ID, arg.LeaseOwner)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countJobs = `-- name: CountJobs :many
+SELECT kind, state, COUNT(*) AS count FROM jobs
+GROUP BY kind, state
+ORDER BY kind, state
+`
+
+type CountJobsRow struct {
+	Kind  string
+	State string
+	Count int64
+}
+
+func (q *Queries) CountJobs(ctx context.Context) ([]CountJobsRow, error) {
+	rows, err := q.db.QueryContext(ctx, countJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountJobsRow
+	for rows.Next() {
+		var i CountJobsRow
+		if err := rows.Scan(&i.Kind, &i.State, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createArticle = `-- name: CreateArticle :one
 INSERT INTO articles (
     title,
@@ -24,25 +181,43 @@ INSERT INTO articles (
     status,
     analysis_status,
     story_group_id,
-    content
+    content,
+    scrape_strategy,
+    guid,
+    author,
+    image_url,
+    canonical_url,
+    prompt_name,
+    prompt_version,
+    analysis_attempts,
+    analysis_error
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
-) RETURNING id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+) RETURNING id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error
 `
 
 type CreateArticleParams struct {
-	Title          sql.NullString
-	Url            sql.NullString
-	SourceName     sql.NullString
-	PublishedDate  sql.NullTime
-	Summary        sql.NullString
-	Entities       interface{}
-	ContentType    sql.NullString
-	Topics         interface{}
-	Status         sql.NullString
-	AnalysisStatus sql.NullString
-	StoryGroupID   sql.NullString
-	Content        sql.NullString
+	Title            sql.NullString
+	Url              sql.NullString
+	SourceName       sql.NullString
+	PublishedDate    sql.NullTime
+	Summary          sql.NullString
+	Entities         interface{}
+	ContentType      sql.NullString
+	Topics           interface{}
+	Status           sql.NullString
+	AnalysisStatus   sql.NullString
+	StoryGroupID     sql.NullString
+	Content          sql.NullString
+	ScrapeStrategy   sql.NullString
+	Guid             sql.NullString
+	Author           sql.NullString
+	ImageUrl         sql.NullString
+	CanonicalUrl     sql.NullString
+	PromptName       sql.NullString
+	PromptVersion    sql.NullString
+	AnalysisAttempts int64
+	AnalysisError    sql.NullString
 }
 
 func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (Article, error) {
@@ -59,6 +234,15 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (A
 		arg.AnalysisStatus,
 		arg.StoryGroupID,
 		arg.Content,
+		arg.ScrapeStrategy,
+		arg.Guid,
+		arg.Author,
+		arg.ImageUrl,
+		arg.CanonicalUrl,
+		arg.PromptName,
+		arg.PromptVersion,
+		arg.AnalysisAttempts,
+		arg.AnalysisError,
 	)
 	var i Article
 	err := row.Scan(
@@ -72,15 +256,92 @@ func (q *Queries) CreateArticle(ctx context.Context, arg CreateArticleParams) (A
 		&i.ContentType,
 		&i.Topics,
 		&i.Status,
-		&i.AnalysisStatus,
 		&i.StoryGroupID,
+		&i.AnalysisStatus,
 		&i.Content,
+		&i.ScrapeStrategy,
+		&i.Guid,
+		&i.Author,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+		&i.PromptName,
+		&i.PromptVersion,
+		&i.AnalysisAttempts,
+		&i.AnalysisError,
 	)
 	return i, err
 }
 
+const deleteAICache = `-- name: DeleteAICache :execrows
+DELETE FROM ai_cache
+`
+
+func (q *Queries) DeleteAICache(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAICache)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteArticle = `-- name: DeleteArticle :exec
+DELETE FROM articles WHERE id = ?
+`
+
+func (q *Queries) DeleteArticle(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteArticle, id)
+	return err
+}
+
+const deleteArticleFingerprints = `-- name: DeleteArticleFingerprints :exec
+DELETE FROM article_fingerprints
+`
+
+func (q *Queries) DeleteArticleFingerprints(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteArticleFingerprints)
+	return err
+}
+
+const enqueueJob = `-- name: EnqueueJob :execrows
+INSERT INTO jobs (kind, article_id, next_run_at, created_at, updated_at)
+VALUES (?1, ?2, ?3, ?3, ?3)
+ON CONFLICT (kind, article_id) WHERE state IN ('queued', 'running') DO NOTHING
+`
+
+type EnqueueJobParams struct {
+	Kind      string
+	ArticleID int64
+	Now       int64
+}
+
+func (q *Queries) EnqueueJob(ctx context.Context, arg EnqueueJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enqueueJob, arg.Kind, arg.ArticleID, arg.Now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAICacheEntry = `-- name: GetAICacheEntry :one
+SELECT result FROM ai_cache
+WHERE content_hash = ? AND model = ? AND prompt_version = ?
+`
+
+type GetAICacheEntryParams struct {
+	ContentHash   string
+	Model         string
+	PromptVersion string
+}
+
+func (q *Queries) GetAICacheEntry(ctx context.Context, arg GetAICacheEntryParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAICacheEntry, arg.ContentHash, arg.Model, arg.PromptVersion)
+	var result string
+	err := row.Scan(&result)
+	return result, err
+}
+
 const getArticle = `-- name: GetArticle :one
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE id = ? LIMIT 1
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE id = ? LIMIT 1
 `
 
 func (q *Queries) GetArticle(ctx context.Context, id int64) (Article, error) {
@@ -97,15 +358,97 @@ func (q *Queries) GetArticle(ctx context.Context, id int64) (Article, error) {
 		&i.ContentType,
 		&i.Topics,
 		&i.Status,
+		&i.StoryGroupID,
+		&i.AnalysisStatus,
+		&i.Content,
+		&i.ScrapeStrategy,
+		&i.Guid,
+		&i.Author,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+		&i.PromptName,
+		&i.PromptVersion,
+		&i.AnalysisAttempts,
+		&i.AnalysisError,
+	)
+	return i, err
+}
+
+const getArticleByCanonicalUrl = `-- name: GetArticleByCanonicalUrl :one
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE canonical_url = ? LIMIT 1
+`
+
+func (q *Queries) GetArticleByCanonicalUrl(ctx context.Context, canonicalUrl sql.NullString) (Article, error) {
+	row := q.db.QueryRowContext(ctx, getArticleByCanonicalUrl, canonicalUrl)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Url,
+		&i.SourceName,
+		&i.PublishedDate,
+		&i.Summary,
+		&i.Entities,
+		&i.ContentType,
+		&i.Topics,
+		&i.Status,
+		&i.StoryGroupID,
 		&i.AnalysisStatus,
+		&i.Content,
+		&i.ScrapeStrategy,
+		&i.Guid,
+		&i.Author,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+		&i.PromptName,
+		&i.PromptVersion,
+		&i.AnalysisAttempts,
+		&i.AnalysisError,
+	)
+	return i, err
+}
+
+const getArticleBySourceGuid = `-- name: GetArticleBySourceGuid :one
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE source_name = ? AND guid = ? LIMIT 1
+`
+
+type GetArticleBySourceGuidParams struct {
+	SourceName sql.NullString
+	Guid       sql.NullString
+}
+
+func (q *Queries) GetArticleBySourceGuid(ctx context.Context, arg GetArticleBySourceGuidParams) (Article, error) {
+	row := q.db.QueryRowContext(ctx, getArticleBySourceGuid, arg.SourceName, arg.Guid)
+	var i Article
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Url,
+		&i.SourceName,
+		&i.PublishedDate,
+		&i.Summary,
+		&i.Entities,
+		&i.ContentType,
+		&i.Topics,
+		&i.Status,
 		&i.StoryGroupID,
+		&i.AnalysisStatus,
 		&i.Content,
+		&i.ScrapeStrategy,
+		&i.Guid,
+		&i.Author,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+		&i.PromptName,
+		&i.PromptVersion,
+		&i.AnalysisAttempts,
+		&i.AnalysisError,
 	)
 	return i, err
 }
 
 const getArticleByUrl = `-- name: GetArticleByUrl :one
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE url = ? LIMIT 1
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE url = ? LIMIT 1
 `
 
 func (q *Queries) GetArticleByUrl(ctx context.Context, url sql.NullString) (Article, error) {
@@ -122,19 +465,808 @@ func (q *Queries) GetArticleByUrl(ctx context.Context, url sql.NullString) (Arti
 		&i.ContentType,
 		&i.Topics,
 		&i.Status,
-		&i.AnalysisStatus,
 		&i.StoryGroupID,
+		&i.AnalysisStatus,
 		&i.Content,
+		&i.ScrapeStrategy,
+		&i.Guid,
+		&i.Author,
+		&i.ImageUrl,
+		&i.CanonicalUrl,
+		&i.PromptName,
+		&i.PromptVersion,
+		&i.AnalysisAttempts,
+		&i.AnalysisError,
+	)
+	return i, err
+}
+
+const getCanonicalLink = `-- name: GetCanonicalLink :one
+SELECT canonical_url FROM canonical_links WHERE link_key = ?
+`
+
+func (q *Queries) GetCanonicalLink(ctx context.Context, linkKey string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getCanonicalLink, linkKey)
+	var canonical_url string
+	err := row.Scan(&canonical_url)
+	return canonical_url, err
+}
+
+const getFeed = `-- name: GetFeed :one
+SELECT source_url, etag, last_modified, body_hash, checked_at, last_success_at, last_error, last_error_at, consecutive_failures, fetch_count, total_latency_ms, items_seen, quarantined_until, item_limit FROM feeds WHERE source_url = ? LIMIT 1
+`
+
+func (q *Queries) GetFeed(ctx context.Context, sourceUrl string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeed, sourceUrl)
+	var i Feed
+	err := row.Scan(
+		&i.SourceUrl,
+		&i.Etag,
+		&i.LastModified,
+		&i.BodyHash,
+		&i.CheckedAt,
+		&i.LastSuccessAt,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.ConsecutiveFailures,
+		&i.FetchCount,
+		&i.TotalLatencyMs,
+		&i.ItemsSeen,
+		&i.QuarantinedUntil,
+		&i.ItemLimit,
+	)
+	return i, err
+}
+
+const leaseJob = `-- name: LeaseJob :one
+UPDATE jobs SET
+    state = 'running',
+    attempts = attempts + 1,
+    lease_owner = ?1,
+    lease_expires_at = ?2,
+    updated_at = ?3
+WHERE id = (
+    SELECT id FROM jobs
+    WHERE (state = 'queued' AND next_run_at <= ?3)
+        OR (state = 'running' AND lease_expires_at <= ?3)
+    ORDER BY next_run_at, id
+    LIMIT 1
+)
+RETURNING id, kind, article_id, state, attempts, last_error, next_run_at, lease_owner, lease_expires_at, created_at, updated_at
+`
+
+type LeaseJobParams struct {
+	Owner          sql.NullString
+	LeaseExpiresAt sql.NullInt64
+	Now            int64
+}
+
+func (q *Queries) LeaseJob(ctx context.Context, arg LeaseJobParams) (Job, error) {
+	row := q.db.QueryRowContext(ctx, leaseJob, arg.Owner, arg.LeaseExpiresAt, arg.Now)
+	var i Job
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.ArticleID,
+		&i.State,
+		&i.Attempts,
+		&i.LastError,
+		&i.NextRunAt,
+		&i.LeaseOwner,
+		&i.LeaseExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const listAllArticles = `-- name: ListAllArticles :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles ORDER BY published_date DESC
+const linkArticleAuthor = `-- name: LinkArticleAuthor :exec
+INSERT OR IGNORE INTO article_authors (article_id, author_id) VALUES (?, ?)
+`
+
+type LinkArticleAuthorParams struct {
+	ArticleID int64
+	AuthorID  int64
+}
+
+func (q *Queries) LinkArticleAuthor(ctx context.Context, arg LinkArticleAuthorParams) error {
+	_, err := q.db.ExecContext(ctx, linkArticleAuthor, arg.ArticleID, arg.AuthorID)
+	return err
+}
+
+const linkArticleEntity = `-- name: LinkArticleEntity :exec
+INSERT OR IGNORE INTO article_entities (article_id, entity_id) VALUES (?, ?)
+`
+
+type LinkArticleEntityParams struct {
+	ArticleID int64
+	EntityID  int64
+}
+
+func (q *Queries) LinkArticleEntity(ctx context.Context, arg LinkArticleEntityParams) error {
+	_, err := q.db.ExecContext(ctx, linkArticleEntity, arg.ArticleID, arg.EntityID)
+	return err
+}
+
+const linkArticleTopic = `-- name: LinkArticleTopic :exec
+INSERT OR IGNORE INTO article_topics (article_id, topic_id) VALUES (?, ?)
+`
+
+type LinkArticleTopicParams struct {
+	ArticleID int64
+	TopicID   int64
+}
+
+func (q *Queries) LinkArticleTopic(ctx context.Context, arg LinkArticleTopicParams) error {
+	_, err := q.db.ExecContext(ctx, linkArticleTopic, arg.ArticleID, arg.TopicID)
+	return err
+}
+
+const listAIUsageSince = `-- name: ListAIUsageSince :many
+SELECT source_name, model, prompt_tokens, output_tokens, latency_ms, created_at
+FROM ai_usage
+WHERE created_at >= ?
+ORDER BY created_at, id
+`
+
+type ListAIUsageSinceRow struct {
+	SourceName   string
+	Model        string
+	PromptTokens int64
+	OutputTokens int64
+	LatencyMs    int64
+	CreatedAt    int64
+}
+
+func (q *Queries) ListAIUsageSince(ctx context.Context, createdAt int64) ([]ListAIUsageSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAIUsageSince, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAIUsageSinceRow
+	for rows.Next() {
+		var i ListAIUsageSinceRow
+		if err := rows.Scan(
+			&i.SourceName,
+			&i.Model,
+			&i.PromptTokens,
+			&i.OutputTokens,
+			&i.LatencyMs,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveJobArticleIDs = `-- name: ListActiveJobArticleIDs :many
+SELECT DISTINCT article_id FROM jobs WHERE state IN ('queued', 'running') ORDER BY article_id
+`
+
+func (q *Queries) ListActiveJobArticleIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveJobArticleIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var article_id int64
+		if err := rows.Scan(&article_id); err != nil {
+			return nil, err
+		}
+		items = append(items, article_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllArticles = `-- name: ListAllArticles :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles ORDER BY published_date DESC
+`
+
+func (q *Queries) ListAllArticles(ctx context.Context) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listAllArticles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Article
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.SourceName,
+			&i.PublishedDate,
+			&i.Summary,
+			&i.Entities,
+			&i.ContentType,
+			&i.Topics,
+			&i.Status,
+			&i.StoryGroupID,
+			&i.AnalysisStatus,
+			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllArticlesBySource = `-- name: ListAllArticlesBySource :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE source_name = ? ORDER BY published_date DESC
+`
+
+func (q *Queries) ListAllArticlesBySource(ctx context.Context, sourceName sql.NullString) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listAllArticlesBySource, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Article
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.SourceName,
+			&i.PublishedDate,
+			&i.Summary,
+			&i.Entities,
+			&i.ContentType,
+			&i.Topics,
+			&i.Status,
+			&i.StoryGroupID,
+			&i.AnalysisStatus,
+			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllArticlesBySourceAndTopic = `-- name: ListAllArticlesBySourceAndTopic :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE source_name = ? AND id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC
+`
+
+type ListAllArticlesBySourceAndTopicParams struct {
+	SourceName sql.NullString
+	Name       string
+}
+
+func (q *Queries) ListAllArticlesBySourceAndTopic(ctx context.Context, arg ListAllArticlesBySourceAndTopicParams) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listAllArticlesBySourceAndTopic, arg.SourceName, arg.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Article
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.SourceName,
+			&i.PublishedDate,
+			&i.Summary,
+			&i.Entities,
+			&i.ContentType,
+			&i.Topics,
+			&i.Status,
+			&i.StoryGroupID,
+			&i.AnalysisStatus,
+			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAllArticlesByTopic = `-- name: ListAllArticlesByTopic :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC
+`
+
+func (q *Queries) ListAllArticlesByTopic(ctx context.Context, name string) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listAllArticlesByTopic, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Article
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.SourceName,
+			&i.PublishedDate,
+			&i.Summary,
+			&i.Entities,
+			&i.ContentType,
+			&i.Topics,
+			&i.Status,
+			&i.StoryGroupID,
+			&i.AnalysisStatus,
+			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticleAuthors = `-- name: ListArticleAuthors :many
+SELECT au.name FROM authors au
+JOIN article_authors aa ON aa.author_id = au.id
+WHERE aa.article_id = ?
+ORDER BY au.name
+`
+
+func (q *Queries) ListArticleAuthors(ctx context.Context, articleID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listArticleAuthors, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticleCategories = `-- name: ListArticleCategories :many
+SELECT category FROM article_categories WHERE article_id = ? ORDER BY category
+`
+
+func (q *Queries) ListArticleCategories(ctx context.Context, articleID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listArticleCategories, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var category string
+		if err := rows.Scan(&category); err != nil {
+			return nil, err
+		}
+		items = append(items, category)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticleEmbeddings = `-- name: ListArticleEmbeddings :many
+SELECT article_id, vector FROM article_embeddings WHERE model = ? ORDER BY article_id
+`
+
+type ListArticleEmbeddingsRow struct {
+	ArticleID int64
+	Vector    []byte
+}
+
+func (q *Queries) ListArticleEmbeddings(ctx context.Context, model string) ([]ListArticleEmbeddingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listArticleEmbeddings, model)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListArticleEmbeddingsRow
+	for rows.Next() {
+		var i ListArticleEmbeddingsRow
+		if err := rows.Scan(&i.ArticleID, &i.Vector); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticleEnclosures = `-- name: ListArticleEnclosures :many
+SELECT id, article_id, url, mime_type, length FROM article_enclosures WHERE article_id = ? ORDER BY id
+`
+
+func (q *Queries) ListArticleEnclosures(ctx context.Context, articleID int64) ([]ArticleEnclosure, error) {
+	rows, err := q.db.QueryContext(ctx, listArticleEnclosures, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ArticleEnclosure
+	for rows.Next() {
+		var i ArticleEnclosure
+		if err := rows.Scan(
+			&i.ID,
+			&i.ArticleID,
+			&i.Url,
+			&i.MimeType,
+			&i.Length,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticleEntities = `-- name: ListArticleEntities :many
+SELECT e.name, e.kind FROM entities e
+JOIN article_entities ae ON ae.entity_id = e.id
+WHERE ae.article_id = ?
+ORDER BY e.kind, e.name
+`
+
+type ListArticleEntitiesRow struct {
+	Name string
+	Kind string
+}
+
+func (q *Queries) ListArticleEntities(ctx context.Context, articleID int64) ([]ListArticleEntitiesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listArticleEntities, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListArticleEntitiesRow
+	for rows.Next() {
+		var i ListArticleEntitiesRow
+		if err := rows.Scan(&i.Name, &i.Kind); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticleTopics = `-- name: ListArticleTopics :many
+SELECT t.name FROM topics t
+JOIN article_topics at ON at.topic_id = t.id
+WHERE at.article_id = ?
+ORDER BY t.name
+`
+
+func (q *Queries) ListArticleTopics(ctx context.Context, articleID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listArticleTopics, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticleURLs = `-- name: ListArticleURLs :many
+SELECT id, url, canonical_url, status, analysis_status FROM articles
+WHERE url IS NOT NULL AND url != ''
+ORDER BY id
+`
+
+type ListArticleURLsRow struct {
+	ID             int64
+	Url            sql.NullString
+	CanonicalUrl   sql.NullString
+	Status         sql.NullString
+	AnalysisStatus sql.NullString
+}
+
+func (q *Queries) ListArticleURLs(ctx context.Context) ([]ListArticleURLsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listArticleURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListArticleURLsRow
+	for rows.Next() {
+		var i ListArticleURLsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.CanonicalUrl,
+			&i.Status,
+			&i.AnalysisStatus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticles = `-- name: ListArticles :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles
+`
+
+func (q *Queries) ListArticles(ctx context.Context) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listArticles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Article
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.SourceName,
+			&i.PublishedDate,
+			&i.Summary,
+			&i.Entities,
+			&i.ContentType,
+			&i.Topics,
+			&i.Status,
+			&i.StoryGroupID,
+			&i.AnalysisStatus,
+			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesByAuthor = `-- name: ListArticlesByAuthor :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles
+WHERE (CAST(?1 AS BOOLEAN) OR status != 'read')
+    AND (?2 IS NULL OR source_name = ?2)
+    AND (?3 IS NULL OR id IN (
+        SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?3
+    ))
+    AND (?4 IS NULL OR id IN (
+        SELECT ae.article_id FROM article_entities ae JOIN entities e ON e.id = ae.entity_id WHERE e.name = ?4
+    ))
+    AND id IN (
+        SELECT aa.article_id FROM article_authors aa JOIN authors au ON au.id = aa.author_id WHERE au.name = ?5
+    )
+ORDER BY published_date DESC
+`
+
+type ListArticlesByAuthorParams struct {
+	IncludeRead bool
+	SourceName  sql.NullString
+	Topic       sql.NullString
+	Entity      sql.NullString
+	Author      string
+}
+
+func (q *Queries) ListArticlesByAuthor(ctx context.Context, arg ListArticlesByAuthorParams) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listArticlesByAuthor,
+		arg.IncludeRead,
+		arg.SourceName,
+		arg.Topic,
+		arg.Entity,
+		arg.Author,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Article
+	for rows.Next() {
+		var i Article
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.SourceName,
+			&i.PublishedDate,
+			&i.Summary,
+			&i.Entities,
+			&i.ContentType,
+			&i.Topics,
+			&i.Status,
+			&i.StoryGroupID,
+			&i.AnalysisStatus,
+			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listArticlesByEntity = `-- name: ListArticlesByEntity :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles
+WHERE (CAST(?1 AS BOOLEAN) OR status != 'read')
+    AND (?2 IS NULL OR source_name = ?2)
+    AND (?3 IS NULL OR id IN (
+        SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?3
+    ))
+    AND id IN (
+        SELECT ae.article_id FROM article_entities ae JOIN entities e ON e.id = ae.entity_id WHERE e.name = ?4
+    )
+ORDER BY published_date DESC
 `
 
-func (q *Queries) ListAllArticles(ctx context.Context) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listAllArticles)
+type ListArticlesByEntityParams struct {
+	IncludeRead bool
+	SourceName  sql.NullString
+	Topic       sql.NullString
+	Entity      string
+}
+
+func (q *Queries) ListArticlesByEntity(ctx context.Context, arg ListArticlesByEntityParams) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listArticlesByEntity,
+		arg.IncludeRead,
+		arg.SourceName,
+		arg.Topic,
+		arg.Entity,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -153,9 +1285,18 @@ func (q *Queries) ListAllArticles(ctx context.Context) ([]Article, error) {
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -170,12 +1311,12 @@ func (q *Queries) ListAllArticles(ctx context.Context) ([]Article, error) {
 	return items, nil
 }
 
-const listAllArticlesBySource = `-- name: ListAllArticlesBySource :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE source_name = ? ORDER BY published_date DESC
+const listArticlesBySource = `-- name: ListArticlesBySource :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE status != 'read' AND source_name = ? ORDER BY published_date DESC
 `
 
-func (q *Queries) ListAllArticlesBySource(ctx context.Context, sourceName sql.NullString) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listAllArticlesBySource, sourceName)
+func (q *Queries) ListArticlesBySource(ctx context.Context, sourceName sql.NullString) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listArticlesBySource, sourceName)
 	if err != nil {
 		return nil, err
 	}
@@ -194,9 +1335,18 @@ func (q *Queries) ListAllArticlesBySource(ctx context.Context, sourceName sql.Nu
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -211,17 +1361,19 @@ func (q *Queries) ListAllArticlesBySource(ctx context.Context, sourceName sql.Nu
 	return items, nil
 }
 
-const listAllArticlesBySourceAndTopic = `-- name: ListAllArticlesBySourceAndTopic :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE source_name = ? AND JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC
+const listArticlesBySourceAndTopic = `-- name: ListArticlesBySourceAndTopic :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE status != 'read' AND source_name = ? AND id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC
 `
 
-type ListAllArticlesBySourceAndTopicParams struct {
+type ListArticlesBySourceAndTopicParams struct {
 	SourceName sql.NullString
-	Column2    sql.NullString
+	Name       string
 }
 
-func (q *Queries) ListAllArticlesBySourceAndTopic(ctx context.Context, arg ListAllArticlesBySourceAndTopicParams) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listAllArticlesBySourceAndTopic, arg.SourceName, arg.Column2)
+func (q *Queries) ListArticlesBySourceAndTopic(ctx context.Context, arg ListArticlesBySourceAndTopicParams) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listArticlesBySourceAndTopic, arg.SourceName, arg.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -240,9 +1392,18 @@ func (q *Queries) ListAllArticlesBySourceAndTopic(ctx context.Context, arg ListA
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -257,12 +1418,14 @@ func (q *Queries) ListAllArticlesBySourceAndTopic(ctx context.Context, arg ListA
 	return items, nil
 }
 
-const listAllArticlesByTopic = `-- name: ListAllArticlesByTopic :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC
+const listArticlesByTopic = `-- name: ListArticlesByTopic :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE status != 'read' AND id IN (
+    SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?
+) ORDER BY published_date DESC
 `
 
-func (q *Queries) ListAllArticlesByTopic(ctx context.Context, dollar_1 sql.NullString) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listAllArticlesByTopic, dollar_1)
+func (q *Queries) ListArticlesByTopic(ctx context.Context, name string) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listArticlesByTopic, name)
 	if err != nil {
 		return nil, err
 	}
@@ -281,9 +1444,18 @@ func (q *Queries) ListAllArticlesByTopic(ctx context.Context, dollar_1 sql.NullS
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -298,12 +1470,14 @@ func (q *Queries) ListAllArticlesByTopic(ctx context.Context, dollar_1 sql.NullS
 	return items, nil
 }
 
-const listArticles = `-- name: ListArticles :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles
+const listArticlesWithoutEmbedding = `-- name: ListArticlesWithoutEmbedding :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles
+WHERE id NOT IN (SELECT article_id FROM article_embeddings WHERE model = ?)
+ORDER BY id
 `
 
-func (q *Queries) ListArticles(ctx context.Context) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listArticles)
+func (q *Queries) ListArticlesWithoutEmbedding(ctx context.Context, model string) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listArticlesWithoutEmbedding, model)
 	if err != nil {
 		return nil, err
 	}
@@ -322,9 +1496,18 @@ func (q *Queries) ListArticles(ctx context.Context) ([]Article, error) {
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -339,12 +1522,14 @@ func (q *Queries) ListArticles(ctx context.Context) ([]Article, error) {
 	return items, nil
 }
 
-const listArticlesBySource = `-- name: ListArticlesBySource :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE status != 'read' AND source_name = ? ORDER BY published_date DESC
+const listBacklogArticles = `-- name: ListBacklogArticles :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles
+WHERE analysis_status IN ('pending', 'unprocessed', 'failed') AND analysis_attempts < ?
+ORDER BY analysis_status = 'unprocessed', published_date DESC
 `
 
-func (q *Queries) ListArticlesBySource(ctx context.Context, sourceName sql.NullString) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listArticlesBySource, sourceName)
+func (q *Queries) ListBacklogArticles(ctx context.Context, analysisAttempts int64) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listBacklogArticles, analysisAttempts)
 	if err != nil {
 		return nil, err
 	}
@@ -363,9 +1548,18 @@ func (q *Queries) ListArticlesBySource(ctx context.Context, sourceName sql.NullS
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -380,17 +1574,54 @@ func (q *Queries) ListArticlesBySource(ctx context.Context, sourceName sql.NullS
 	return items, nil
 }
 
-const listArticlesBySourceAndTopic = `-- name: ListArticlesBySourceAndTopic :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE status != 'read' AND source_name = ? AND JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC
+const listFeeds = `-- name: ListFeeds :many
+SELECT source_url, etag, last_modified, body_hash, checked_at, last_success_at, last_error, last_error_at, consecutive_failures, fetch_count, total_latency_ms, items_seen, quarantined_until, item_limit FROM feeds ORDER BY source_url
 `
 
-type ListArticlesBySourceAndTopicParams struct {
-	SourceName sql.NullString
-	Column2    sql.NullString
+func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, listFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.SourceUrl,
+			&i.Etag,
+			&i.LastModified,
+			&i.BodyHash,
+			&i.CheckedAt,
+			&i.LastSuccessAt,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.ConsecutiveFailures,
+			&i.FetchCount,
+			&i.TotalLatencyMs,
+			&i.ItemsSeen,
+			&i.QuarantinedUntil,
+			&i.ItemLimit,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) ListArticlesBySourceAndTopic(ctx context.Context, arg ListArticlesBySourceAndTopicParams) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listArticlesBySourceAndTopic, arg.SourceName, arg.Column2)
+const listPendingArticles = `-- name: ListPendingArticles :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE analysis_status = 'pending' ORDER BY published_date DESC
+`
+
+func (q *Queries) ListPendingArticles(ctx context.Context) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listPendingArticles)
 	if err != nil {
 		return nil, err
 	}
@@ -409,9 +1640,18 @@ func (q *Queries) ListArticlesBySourceAndTopic(ctx context.Context, arg ListArti
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -426,33 +1666,40 @@ func (q *Queries) ListArticlesBySourceAndTopic(ctx context.Context, arg ListArti
 	return items, nil
 }
 
-const listArticlesByTopic = `-- name: ListArticlesByTopic :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE status != 'read' AND JSON_EXTRACT(topics, '$') LIKE '%' || ? || '%' ORDER BY published_date DESC
+const listStoryCandidates = `-- name: ListStoryCandidates :many
+SELECT f.article_id, f.simhash, f.minhash, a.story_group_id
+FROM article_fingerprints f JOIN articles a ON a.id = f.article_id
+WHERE f.published_at BETWEEN ?1 AND ?2 AND f.article_id != ?3
+ORDER BY f.published_at, f.article_id
 `
 
-func (q *Queries) ListArticlesByTopic(ctx context.Context, dollar_1 sql.NullString) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listArticlesByTopic, dollar_1)
+type ListStoryCandidatesParams struct {
+	PublishedFrom int64
+	PublishedTo   int64
+	ArticleID     int64
+}
+
+type ListStoryCandidatesRow struct {
+	ArticleID    int64
+	Simhash      int64
+	Minhash      []byte
+	StoryGroupID sql.NullString
+}
+
+func (q *Queries) ListStoryCandidates(ctx context.Context, arg ListStoryCandidatesParams) ([]ListStoryCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listStoryCandidates, arg.PublishedFrom, arg.PublishedTo, arg.ArticleID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Article
+	var items []ListStoryCandidatesRow
 	for rows.Next() {
-		var i Article
+		var i ListStoryCandidatesRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Url,
-			&i.SourceName,
-			&i.PublishedDate,
-			&i.Summary,
-			&i.Entities,
-			&i.ContentType,
-			&i.Topics,
-			&i.Status,
-			&i.AnalysisStatus,
+			&i.ArticleID,
+			&i.Simhash,
+			&i.Minhash,
 			&i.StoryGroupID,
-			&i.Content,
 		); err != nil {
 			return nil, err
 		}
@@ -467,12 +1714,14 @@ func (q *Queries) ListArticlesByTopic(ctx context.Context, dollar_1 sql.NullStri
 	return items, nil
 }
 
-const listPendingArticles = `-- name: ListPendingArticles :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE analysis_status = 'pending' ORDER BY published_date DESC
+const listUnclusteredArticles = `-- name: ListUnclusteredArticles :many
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles
+WHERE id NOT IN (SELECT article_id FROM article_fingerprints)
+ORDER BY published_date, id
 `
 
-func (q *Queries) ListPendingArticles(ctx context.Context) ([]Article, error) {
-	rows, err := q.db.QueryContext(ctx, listPendingArticles)
+func (q *Queries) ListUnclusteredArticles(ctx context.Context) ([]Article, error) {
+	rows, err := q.db.QueryContext(ctx, listUnclusteredArticles)
 	if err != nil {
 		return nil, err
 	}
@@ -491,9 +1740,18 @@ func (q *Queries) ListPendingArticles(ctx context.Context) ([]Article, error) {
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -509,7 +1767,7 @@ func (q *Queries) ListPendingArticles(ctx context.Context) ([]Article, error) {
 }
 
 const listUnprocessedArticles = `-- name: ListUnprocessedArticles :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE analysis_status = 'unprocessed' ORDER BY published_date DESC
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE analysis_status = 'unprocessed' ORDER BY published_date DESC
 `
 
 func (q *Queries) ListUnprocessedArticles(ctx context.Context) ([]Article, error) {
@@ -532,9 +1790,18 @@ func (q *Queries) ListUnprocessedArticles(ctx context.Context) ([]Article, error
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -550,7 +1817,7 @@ func (q *Queries) ListUnprocessedArticles(ctx context.Context) ([]Article, error
 }
 
 const listUnreadArticles = `-- name: ListUnreadArticles :many
-SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, analysis_status, story_group_id, content FROM articles WHERE status != 'read' ORDER BY source_name, published_date DESC
+SELECT id, title, url, source_name, published_date, summary, entities, content_type, topics, status, story_group_id, analysis_status, content, scrape_strategy, guid, author, image_url, canonical_url, prompt_name, prompt_version, analysis_attempts, analysis_error FROM articles WHERE status != 'read' ORDER BY source_name, published_date DESC
 `
 
 func (q *Queries) ListUnreadArticles(ctx context.Context) ([]Article, error) {
@@ -573,9 +1840,18 @@ func (q *Queries) ListUnreadArticles(ctx context.Context) ([]Article, error) {
 			&i.ContentType,
 			&i.Topics,
 			&i.Status,
-			&i.AnalysisStatus,
 			&i.StoryGroupID,
+			&i.AnalysisStatus,
 			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
 		); err != nil {
 			return nil, err
 		}
@@ -618,6 +1894,244 @@ func (q *Queries) MarkArticlesAsRead(ctx context.Context, ids []int64) error {
 	return err
 }
 
+const putAICacheEntry = `-- name: PutAICacheEntry :exec
+INSERT INTO ai_cache (content_hash, model, prompt_version, result, created_at)
+VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (content_hash, model, prompt_version)
+DO UPDATE SET result = excluded.result, created_at = excluded.created_at
+`
+
+type PutAICacheEntryParams struct {
+	ContentHash   string
+	Model         string
+	PromptVersion string
+	Result        string
+	CreatedAt     int64
+}
+
+func (q *Queries) PutAICacheEntry(ctx context.Context, arg PutAICacheEntryParams) error {
+	_, err := q.db.ExecContext(ctx, putAICacheEntry,
+		arg.ContentHash,
+		arg.Model,
+		arg.PromptVersion,
+		arg.Result,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const putCanonicalLink = `-- name: PutCanonicalLink :exec
+INSERT INTO canonical_links (link_key, canonical_url)
+VALUES (?, ?)
+ON CONFLICT (link_key) DO UPDATE SET canonical_url = excluded.canonical_url
+`
+
+type PutCanonicalLinkParams struct {
+	LinkKey      string
+	CanonicalUrl string
+}
+
+func (q *Queries) PutCanonicalLink(ctx context.Context, arg PutCanonicalLinkParams) error {
+	_, err := q.db.ExecContext(ctx, putCanonicalLink, arg.LinkKey, arg.CanonicalUrl)
+	return err
+}
+
+const quarantineFeed = `-- name: QuarantineFeed :exec
+UPDATE feeds SET quarantined_until = ? WHERE source_url = ?
+`
+
+type QuarantineFeedParams struct {
+	QuarantinedUntil sql.NullTime
+	SourceUrl        string
+}
+
+func (q *Queries) QuarantineFeed(ctx context.Context, arg QuarantineFeedParams) error {
+	_, err := q.db.ExecContext(ctx, quarantineFeed, arg.QuarantinedUntil, arg.SourceUrl)
+	return err
+}
+
+const recordArticleAnalysisFailure = `-- name: RecordArticleAnalysisFailure :exec
+UPDATE articles SET analysis_status = ?, analysis_attempts = ?, analysis_error = ?
+WHERE id = ?
+`
+
+type RecordArticleAnalysisFailureParams struct {
+	AnalysisStatus   sql.NullString
+	AnalysisAttempts int64
+	AnalysisError    sql.NullString
+	ID               int64
+}
+
+func (q *Queries) RecordArticleAnalysisFailure(ctx context.Context, arg RecordArticleAnalysisFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordArticleAnalysisFailure,
+		arg.AnalysisStatus,
+		arg.AnalysisAttempts,
+		arg.AnalysisError,
+		arg.ID,
+	)
+	return err
+}
+
+const recordFeedFailure = `-- name: RecordFeedFailure :one
+INSERT INTO feeds (source_url, last_error, last_error_at, consecutive_failures, fetch_count, total_latency_ms)
+VALUES (?, ?, ?, 1, 1, ?)
+ON CONFLICT (source_url) DO UPDATE SET
+    last_error = excluded.last_error,
+    last_error_at = excluded.last_error_at,
+    consecutive_failures = feeds.consecutive_failures + 1,
+    fetch_count = feeds.fetch_count + 1,
+    total_latency_ms = feeds.total_latency_ms + excluded.total_latency_ms
+RETURNING consecutive_failures
+`
+
+type RecordFeedFailureParams struct {
+	SourceUrl      string
+	LastError      sql.NullString
+	LastErrorAt    sql.NullTime
+	TotalLatencyMs int64
+}
+
+func (q *Queries) RecordFeedFailure(ctx context.Context, arg RecordFeedFailureParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedFailure,
+		arg.SourceUrl,
+		arg.LastError,
+		arg.LastErrorAt,
+		arg.TotalLatencyMs,
+	)
+	var consecutive_failures int64
+	err := row.Scan(&consecutive_failures)
+	return consecutive_failures, err
+}
+
+const recordFeedSuccess = `-- name: RecordFeedSuccess :exec
+INSERT INTO feeds (source_url, last_success_at, fetch_count, total_latency_ms, items_seen)
+VALUES (?, ?, 1, ?, ?)
+ON CONFLICT (source_url) DO UPDATE SET
+    last_success_at = excluded.last_success_at,
+    consecutive_failures = 0,
+    quarantined_until = NULL,
+    fetch_count = feeds.fetch_count + 1,
+    total_latency_ms = feeds.total_latency_ms + excluded.total_latency_ms,
+    items_seen = feeds.items_seen + excluded.items_seen
+`
+
+type RecordFeedSuccessParams struct {
+	SourceUrl      string
+	LastSuccessAt  sql.NullTime
+	TotalLatencyMs int64
+	ItemsSeen      int64
+}
+
+func (q *Queries) RecordFeedSuccess(ctx context.Context, arg RecordFeedSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordFeedSuccess,
+		arg.SourceUrl,
+		arg.LastSuccessAt,
+		arg.TotalLatencyMs,
+		arg.ItemsSeen,
+	)
+	return err
+}
+
+const releaseJob = `-- name: ReleaseJob :execrows
+UPDATE jobs SET
+    state = ?1,
+    attempts = ?2,
+    last_error = ?3,
+    next_run_at = ?4,
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    updated_at = ?5
+WHERE id = ?6 AND state = 'running' AND lease_owner = ?7
+`
+
+type ReleaseJobParams struct {
+	State     string
+	Attempts  int64
+	LastError sql.NullString
+	NextRunAt int64
+	Now       int64
+	ID        int64
+	Owner     sql.NullString
+}
+
+func (q *Queries) ReleaseJob(ctx context.Context, arg ReleaseJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, releaseJob,
+		arg.State,
+		arg.Attempts,
+		arg.LastError,
+		arg.NextRunAt,
+		arg.Now,
+		arg.ID,
+		arg.Owner,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renewJobLease = `-- name: RenewJobLease :execrows
+UPDATE jobs SET lease_expires_at = ?1, updated_at = ?2
+WHERE id = ?3 AND state = 'running' AND lease_owner = ?4
+`
+
+type RenewJobLeaseParams struct {
+	LeaseExpiresAt sql.NullInt64
+	Now            int64
+	ID             int64
+	Owner          sql.NullString
+}
+
+func (q *Queries) RenewJobLease(ctx context.Context, arg RenewJobLeaseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renewJobLease,
+		arg.LeaseExpiresAt,
+		arg.Now,
+		arg.ID,
+		arg.Owner,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const sumAIUsageByModelSince = `-- name: SumAIUsageByModelSince :many
+SELECT model, CAST(SUM(prompt_tokens) AS INTEGER) AS prompt_tokens, CAST(SUM(output_tokens) AS INTEGER) AS output_tokens
+FROM ai_usage
+WHERE created_at >= ?
+GROUP BY model
+ORDER BY model
+`
+
+type SumAIUsageByModelSinceRow struct {
+	Model        string
+	PromptTokens int64
+	OutputTokens int64
+}
+
+func (q *Queries) SumAIUsageByModelSince(ctx context.Context, createdAt int64) ([]SumAIUsageByModelSinceRow, error) {
+	rows, err := q.db.QueryContext(ctx, sumAIUsageByModelSince, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SumAIUsageByModelSinceRow
+	for rows.Next() {
+		var i SumAIUsageByModelSinceRow
+		if err := rows.Scan(&i.Model, &i.PromptTokens, &i.OutputTokens); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateArticleAnalysisStatus = `-- name: UpdateArticleAnalysisStatus :exec
 UPDATE articles SET analysis_status = ? WHERE id = ?
 `
@@ -632,6 +2146,21 @@ func (q *Queries) UpdateArticleAnalysisStatus(ctx context.Context, arg UpdateArt
 	return err
 }
 
+const updateArticleContent = `-- name: UpdateArticleContent :exec
+UPDATE articles SET content = ?, scrape_strategy = ? WHERE id = ?
+`
+
+type UpdateArticleContentParams struct {
+	Content        sql.NullString
+	ScrapeStrategy sql.NullString
+	ID             int64
+}
+
+func (q *Queries) UpdateArticleContent(ctx context.Context, arg UpdateArticleContentParams) error {
+	_, err := q.db.ExecContext(ctx, updateArticleContent, arg.Content, arg.ScrapeStrategy, arg.ID)
+	return err
+}
+
 const updateArticleStatus = `-- name: UpdateArticleStatus :exec
 UPDATE articles SET status = ? WHERE id = ?
 `
@@ -645,3 +2174,159 @@ func (q *Queries) UpdateArticleStatus(ctx context.Context, arg UpdateArticleStat
 	_, err := q.db.ExecContext(ctx, updateArticleStatus, arg.Status, arg.ID)
 	return err
 }
+
+const updateArticleStoryGroup = `-- name: UpdateArticleStoryGroup :exec
+UPDATE articles SET story_group_id = ? WHERE id = ?
+`
+
+type UpdateArticleStoryGroupParams struct {
+	StoryGroupID sql.NullString
+	ID           int64
+}
+
+func (q *Queries) UpdateArticleStoryGroup(ctx context.Context, arg UpdateArticleStoryGroupParams) error {
+	_, err := q.db.ExecContext(ctx, updateArticleStoryGroup, arg.StoryGroupID, arg.ID)
+	return err
+}
+
+const updateArticleURL = `-- name: UpdateArticleURL :exec
+UPDATE articles SET url = ?, canonical_url = ? WHERE id = ?
+`
+
+type UpdateArticleURLParams struct {
+	Url          sql.NullString
+	CanonicalUrl sql.NullString
+	ID           int64
+}
+
+func (q *Queries) UpdateArticleURL(ctx context.Context, arg UpdateArticleURLParams) error {
+	_, err := q.db.ExecContext(ctx, updateArticleURL, arg.Url, arg.CanonicalUrl, arg.ID)
+	return err
+}
+
+const upsertArticleEmbedding = `-- name: UpsertArticleEmbedding :exec
+INSERT INTO article_embeddings (article_id, model, dimensions, vector, created_at)
+VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (article_id) DO UPDATE SET
+    model = excluded.model,
+    dimensions = excluded.dimensions,
+    vector = excluded.vector,
+    created_at = excluded.created_at
+`
+
+type UpsertArticleEmbeddingParams struct {
+	ArticleID  int64
+	Model      string
+	Dimensions int64
+	Vector     []byte
+}
+
+func (q *Queries) UpsertArticleEmbedding(ctx context.Context, arg UpsertArticleEmbeddingParams) error {
+	_, err := q.db.ExecContext(ctx, upsertArticleEmbedding,
+		arg.ArticleID,
+		arg.Model,
+		arg.Dimensions,
+		arg.Vector,
+	)
+	return err
+}
+
+const upsertArticleFingerprint = `-- name: UpsertArticleFingerprint :exec
+INSERT INTO article_fingerprints (article_id, simhash, minhash, published_at)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (article_id) DO UPDATE SET
+    simhash = excluded.simhash,
+    minhash = excluded.minhash,
+    published_at = excluded.published_at
+`
+
+type UpsertArticleFingerprintParams struct {
+	ArticleID   int64
+	Simhash     int64
+	Minhash     []byte
+	PublishedAt int64
+}
+
+func (q *Queries) UpsertArticleFingerprint(ctx context.Context, arg UpsertArticleFingerprintParams) error {
+	_, err := q.db.ExecContext(ctx, upsertArticleFingerprint,
+		arg.ArticleID,
+		arg.Simhash,
+		arg.Minhash,
+		arg.PublishedAt,
+	)
+	return err
+}
+
+const upsertAuthor = `-- name: UpsertAuthor :one
+INSERT INTO authors (name) VALUES (?)
+ON CONFLICT (name) DO UPDATE SET name = authors.name
+RETURNING id
+`
+
+func (q *Queries) UpsertAuthor(ctx context.Context, name string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, upsertAuthor, name)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const upsertEntity = `-- name: UpsertEntity :one
+INSERT INTO entities (name, kind) VALUES (?, ?)
+ON CONFLICT (name, kind) DO UPDATE SET name = entities.name
+RETURNING id
+`
+
+type UpsertEntityParams struct {
+	Name string
+	Kind string
+}
+
+func (q *Queries) UpsertEntity(ctx context.Context, arg UpsertEntityParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, upsertEntity, arg.Name, arg.Kind)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const upsertFeed = `-- name: UpsertFeed :exec
+INSERT INTO feeds (source_url, etag, last_modified, body_hash, item_limit, checked_at)
+VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (source_url) DO UPDATE SET
+    etag = excluded.etag,
+    last_modified = excluded.last_modified,
+    body_hash = excluded.body_hash,
+    item_limit = excluded.item_limit,
+    checked_at = excluded.checked_at
+`
+
+type UpsertFeedParams struct {
+	SourceUrl    string
+	Etag         sql.NullString
+	LastModified sql.NullString
+	BodyHash     sql.NullString
+	ItemLimit    sql.NullInt64
+}
+
+func (q *Queries) UpsertFeed(ctx context.Context, arg UpsertFeedParams) error {
+	_, err := q.db.ExecContext(ctx, upsertFeed,
+		arg.SourceUrl,
+		arg.Etag,
+		arg.LastModified,
+		arg.BodyHash,
+		arg.ItemLimit,
+	)
+	return err
+}
+
+const upsertTopic = `-- name: UpsertTopic :one
+INSERT INTO topics (name) VALUES (?)
+ON CONFLICT (name) DO UPDATE SET name = topics.name
+RETURNING id
+`
+
+func (q *Queries) UpsertTopic(ctx context.Context, name string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, upsertTopic, name)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
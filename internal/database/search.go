@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// Markers wrapped around matched terms in SearchResult.TitleHighlight and
+// SearchResult.Snippet. They are control characters so they never collide
+// with article text; callers replace them with their own styling.
+const (
+	MatchStart = "\x02"
+	MatchEnd   = "\x03"
+)
+
+// SearchParams controls a full-text search over articles. Empty Source and
+// Topic disable those filters; All includes articles that were already read.
+type SearchParams struct {
+	Query  string
+	Source string
+	Topic  string
+	All    bool
+	Limit  int64
+}
+
+// SearchResult is an article matched by SearchArticles together with its
+// bm25 rank (lower is better) and highlighted excerpts.
+type SearchResult struct {
+	Article
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// searchArticles is written by hand because sqlc cannot type the fts5
+// auxiliary functions (bm25, highlight, snippet). Title matches weigh more
+// than summary matches, which weigh more than body matches.
+const searchArticles = `-- name: SearchArticles :many
+SELECT a.id, a.title, a.url, a.source_name, a.published_date, a.summary, a.entities, a.content_type, a.topics, a.status, a.story_group_id, a.analysis_status, a.content, a.scrape_strategy, a.guid, a.author, a.image_url, a.canonical_url, a.prompt_name, a.prompt_version, a.analysis_attempts, a.analysis_error,
+    bm25(articles_fts, 10.0, 5.0, 1.0) AS rank,
+    highlight(articles_fts, 0, ?1, ?2) AS title_highlight,
+    snippet(articles_fts, -1, ?1, ?2, '…', 24) AS snippet
+FROM articles_fts
+JOIN articles a ON a.id = articles_fts.rowid
+WHERE articles_fts MATCH ?3
+    AND (?4 = '' OR a.source_name = ?4)
+    AND (?5 = '' OR a.id IN (
+        SELECT at.article_id FROM article_topics at JOIN topics t ON t.id = at.topic_id WHERE t.name = ?5
+    ))
+    AND (?6 OR a.status != 'read')
+ORDER BY rank
+LIMIT ?7
+`
+
+// SearchArticles runs a ranked full-text search. The query is sanitized with
+// MatchQuery, so user input cannot produce fts5 syntax errors.
+func (q *Queries) SearchArticles(ctx context.Context, arg SearchParams) ([]SearchResult, error) {
+	match := MatchQuery(arg.Query)
+	if match == "" {
+		return nil, nil
+	}
+
+	limit := arg.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	rows, err := q.db.QueryContext(ctx, searchArticles,
+		MatchStart,
+		MatchEnd,
+		match,
+		arg.Source,
+		arg.Topic,
+		arg.All,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchResult
+	for rows.Next() {
+		var i SearchResult
+		var titleHighlight, snippet sql.NullString
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.SourceName,
+			&i.PublishedDate,
+			&i.Summary,
+			&i.Entities,
+			&i.ContentType,
+			&i.Topics,
+			&i.Status,
+			&i.StoryGroupID,
+			&i.AnalysisStatus,
+			&i.Content,
+			&i.ScrapeStrategy,
+			&i.Guid,
+			&i.Author,
+			&i.ImageUrl,
+			&i.CanonicalUrl,
+			&i.PromptName,
+			&i.PromptVersion,
+			&i.AnalysisAttempts,
+			&i.AnalysisError,
+			&i.Rank,
+			&titleHighlight,
+			&snippet,
+		); err != nil {
+			return nil, err
+		}
+		i.TitleHighlight = titleHighlight.String
+		i.Snippet = snippet.String
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// MatchQuery turns free-form user input into an fts5 MATCH expression in
+// which every word must appear. Each word is quoted so punctuation is never
+// parsed as fts5 syntax; a trailing '*' is kept as a prefix search.
+func MatchQuery(input string) string {
+	var terms []string
+	for _, word := range strings.Fields(input) {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.TrimRight(word, "*")
+		if word == "" {
+			continue
+		}
+
+		term := `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createSearchArticle(t *testing.T, queries *Queries, title, source, summary, content, topics, status string) Article {
+	article, err := queries.CreateArticle(context.Background(), CreateArticleParams{
+		Title:         sql.NullString{String: title, Valid: true},
+		Url:           sql.NullString{String: "https://example.com/" + title, Valid: true},
+		SourceName:    sql.NullString{String: source, Valid: true},
+		PublishedDate: sql.NullTime{Time: time.Now(), Valid: true},
+		Summary:       sql.NullString{String: summary, Valid: summary != ""},
+		Topics:        topics,
+		Status:        sql.NullString{String: status, Valid: true},
+		Content:       sql.NullString{String: content, Valid: content != ""},
+	})
+	require.NoError(t, err)
+
+	var tags []string
+	if topics != "" {
+		require.NoError(t, json.Unmarshal([]byte(topics), &tags))
+	}
+	require.NoError(t, queries.TagArticle(context.Background(), article.ID, tags, nil))
+	return article
+}
+
+func TestSearchArticles_RanksTitleMatchesFirst(t *testing.T) {
+	_, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	createSearchArticle(t, queries, "Weekly roundup", "Blog", "Notes on many things", "Somewhere deep in the body we mention transformers once.", `["AI"]`, "unread")
+	createSearchArticle(t, queries, "Transformers explained", "Blog", "An introduction", "Attention is all you need.", `["AI"]`, "unread")
+	createSearchArticle(t, queries, "Cooking pasta", "Food", "Boil water", "Salt generously.", `["Food"]`, "unread")
+
+	results, err := queries.SearchArticles(ctx, SearchParams{Query: "transformers"})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, "Transformers explained", results[0].Title.String)
+	assert.Equal(t, MatchStart+"Transformers"+MatchEnd+" explained", results[0].TitleHighlight)
+	assert.Less(t, results[0].Rank, results[1].Rank)
+	assert.Contains(t, results[1].Snippet, MatchStart+"transformers"+MatchEnd)
+}
+
+func TestSearchArticles_StemsAndFilters(t *testing.T) {
+	_, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	createSearchArticle(t, queries, "Running models locally", "Blog", "", "", `["Open Source"]`, "unread")
+	createSearchArticle(t, queries, "Run everything in the cloud", "News", "", "", `["Cloud"]`, "read")
+
+	results, err := queries.SearchArticles(ctx, SearchParams{Query: "run"})
+	require.NoError(t, err)
+	require.Len(t, results, 1, "read articles are excluded unless All is set")
+	assert.Equal(t, "Running models locally", results[0].Title.String)
+
+	results, err = queries.SearchArticles(ctx, SearchParams{Query: "run", All: true})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	results, err = queries.SearchArticles(ctx, SearchParams{Query: "run", All: true, Source: "News"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "News", results[0].SourceName.String)
+
+	results, err = queries.SearchArticles(ctx, SearchParams{Query: "run", All: true, Topic: "open source"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Blog", results[0].SourceName.String)
+
+	results, err = queries.SearchArticles(ctx, SearchParams{Query: "run", All: true, Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestSearchArticles_IndexFollowsUpdatesAndDeletes(t *testing.T) {
+	db, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	article := createSearchArticle(t, queries, "Placeholder", "Blog", "", "", "", "unread")
+
+	results, err := queries.SearchArticles(ctx, SearchParams{Query: "diffusion"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = db.Exec("UPDATE articles SET content = ? WHERE id = ?", "A new diffusion model was released.", article.ID)
+	require.NoError(t, err)
+
+	results, err = queries.SearchArticles(ctx, SearchParams{Query: "diffusion"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, article.ID, results[0].ID)
+
+	_, err = db.Exec("DELETE FROM articles WHERE id = ?", article.ID)
+	require.NoError(t, err)
+
+	results, err = queries.SearchArticles(ctx, SearchParams{Query: "diffusion"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSearchArticles_SyntaxCharactersAreLiteral(t *testing.T) {
+	_, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	createSearchArticle(t, queries, "GPT-4o launch", "Blog", "", "", "", "unread")
+
+	for _, query := range []string{`GPT-4o`, `"GPT`, `gpt:4o`, `(launch)`, `launch*`, `laun*`} {
+		results, err := queries.SearchArticles(ctx, SearchParams{Query: query})
+		require.NoError(t, err, query)
+		assert.NotEmpty(t, results, query)
+	}
+
+	results, err := queries.SearchArticles(ctx, SearchParams{Query: "   "})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestMatchQuery(t *testing.T) {
+	assert.Equal(t, `"open" "source"`, MatchQuery("open source"))
+	assert.Equal(t, `"say" """hi"""`, MatchQuery(`say "hi"`))
+	assert.Equal(t, `"trans"*`, MatchQuery("trans*"))
+	assert.Equal(t, "", MatchQuery(" * "))
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"strings"
+)
+
+// Entity kinds stored in the entities table.
+const (
+	EntityOrganization = "organization"
+	EntityProduct      = "product"
+	EntityPerson       = "person"
+)
+
+// EntityRef names an entity of a given kind.
+type EntityRef struct {
+	Name string
+	Kind string
+}
+
+// TagArticle links an article to topics and entities, creating them as
+// needed. Names are trimmed and blank ones skipped; since names compare
+// case-insensitively, "AI" and "ai" resolve to the same topic.
+func (q *Queries) TagArticle(ctx context.Context, articleID int64, topics []string, entities []EntityRef) error {
+	for _, name := range topics {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		topicID, err := q.UpsertTopic(ctx, name)
+		if err != nil {
+			return err
+		}
+		err = q.LinkArticleTopic(ctx, LinkArticleTopicParams{ArticleID: articleID, TopicID: topicID})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entity := range entities {
+		name := strings.TrimSpace(entity.Name)
+		if name == "" {
+			continue
+		}
+
+		entityID, err := q.UpsertEntity(ctx, UpsertEntityParams{Name: name, Kind: entity.Kind})
+		if err != nil {
+			return err
+		}
+		err = q.LinkArticleEntity(ctx, LinkArticleEntityParams{ArticleID: articleID, EntityID: entityID})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
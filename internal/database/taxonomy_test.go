@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func articleIDs(articles []Article) []int64 {
+	var ids []int64
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestTagArticle_ExactCaseInsensitiveTopics(t *testing.T) {
+	_, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	ai := createSearchArticle(t, queries, "AI news", "Blog", "", "", "", "unread")
+	openAI := createSearchArticle(t, queries, "OpenAI news", "Blog", "", "", "", "unread")
+
+	require.NoError(t, queries.TagArticle(ctx, ai.ID, []string{"AI", " ai ", ""}, nil))
+	require.NoError(t, queries.TagArticle(ctx, openAI.ID, []string{"OpenAI"}, nil))
+
+	topics, err := queries.ListArticleTopics(ctx, ai.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AI"}, topics, "duplicate and blank topics collapse into one")
+
+	articles, err := queries.ListArticlesByTopic(ctx, "ai")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{ai.ID}, articleIDs(articles), "topic match is exact, not a substring of OpenAI")
+
+	articles, err = queries.ListAllArticlesBySourceAndTopic(ctx, ListAllArticlesBySourceAndTopicParams{
+		SourceName: sql.NullString{String: "Blog", Valid: true},
+		Name:       "OPENAI",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{openAI.ID}, articleIDs(articles))
+}
+
+func TestListArticlesByEntity(t *testing.T) {
+	db, queries := setupTestDB(t)
+	ctx := context.Background()
+
+	unread := createSearchArticle(t, queries, "Gemini launch", "Google Blog", "", "", "", "unread")
+	read := createSearchArticle(t, queries, "Gemini recap", "News", "", "", "", "read")
+	other := createSearchArticle(t, queries, "Claude launch", "News", "", "", "", "unread")
+
+	require.NoError(t, queries.TagArticle(ctx, unread.ID, []string{"Models"}, []EntityRef{
+		{Name: "Google", Kind: EntityOrganization},
+		{Name: "Gemini", Kind: EntityProduct},
+	}))
+	require.NoError(t, queries.TagArticle(ctx, read.ID, nil, []EntityRef{{Name: "gemini", Kind: EntityProduct}}))
+	require.NoError(t, queries.TagArticle(ctx, other.ID, nil, []EntityRef{{Name: "Anthropic", Kind: EntityOrganization}}))
+
+	articles, err := queries.ListArticlesByEntity(ctx, ListArticlesByEntityParams{Entity: "GEMINI"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{unread.ID}, articleIDs(articles))
+
+	articles, err = queries.ListArticlesByEntity(ctx, ListArticlesByEntityParams{Entity: "gemini", IncludeRead: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{unread.ID, read.ID}, articleIDs(articles))
+
+	articles, err = queries.ListArticlesByEntity(ctx, ListArticlesByEntityParams{
+		Entity:      "Gemini",
+		IncludeRead: true,
+		SourceName:  sql.NullString{String: "News", Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{read.ID}, articleIDs(articles))
+
+	articles, err = queries.ListArticlesByEntity(ctx, ListArticlesByEntityParams{
+		Entity:      "Gemini",
+		IncludeRead: true,
+		Topic:       sql.NullString{String: "models", Valid: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{unread.ID}, articleIDs(articles))
+
+	entities, err := queries.ListArticleEntities(ctx, unread.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []ListArticleEntitiesRow{
+		{Name: "Google", Kind: EntityOrganization},
+		{Name: "Gemini", Kind: EntityProduct},
+	}, entities)
+
+	_, err = db.Exec("DELETE FROM articles WHERE id = ?", unread.ID)
+	require.NoError(t, err)
+	entities, err = queries.ListArticleEntities(ctx, unread.ID)
+	require.NoError(t, err)
+	assert.Empty(t, entities, "links are removed with the article")
+}
+
+func TestMigrateUp_BackfillsTopicsAndEntities(t *testing.T) {
+	db := openRawTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.Exec(`CREATE TABLE articles (
+		id INTEGER PRIMARY KEY,
+		title TEXT,
+		url TEXT UNIQUE,
+		source_name TEXT,
+		published_date DATETIME,
+		summary TEXT,
+		entities JSON,
+		content_type TEXT,
+		topics JSON,
+		status TEXT DEFAULT 'unread',
+		story_group_id TEXT
+	)`)
+	require.NoError(t, err)
+
+	insert := `INSERT INTO articles (url, topics, entities) VALUES (?, ?, ?)`
+	_, err = db.Exec(insert, "https://example.com/text", `["AI", "Research"]`,
+		`{"organizations":["OpenAI"],"products":["GPT-4o"],"people":["Sam Altman"]}`)
+	require.NoError(t, err)
+	// The fetcher stores JSON as []byte, which SQLite keeps as a blob.
+	_, err = db.Exec(insert, "https://example.com/blob", []byte(`["ai"]`), []byte(`{"organizations":["openai"]}`))
+	require.NoError(t, err)
+	_, err = db.Exec(insert, "https://example.com/invalid", `not json`, `{"topics": ["tech"]}`)
+	require.NoError(t, err)
+
+	_, err = MigrateUp(ctx, db)
+	require.NoError(t, err)
+
+	queries := New(db)
+
+	articles, err := queries.ListAllArticlesByTopic(ctx, "ai")
+	require.NoError(t, err)
+	assert.Len(t, articles, 2)
+
+	articles, err = queries.ListArticlesByEntity(ctx, ListArticlesByEntityParams{Entity: "OpenAI", IncludeRead: true})
+	require.NoError(t, err)
+	assert.Len(t, articles, 2)
+
+	var topicCount, entityCount int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM topics`).Scan(&topicCount))
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM entities`).Scan(&entityCount))
+	assert.Equal(t, 2, topicCount)
+	assert.Equal(t, 3, entityCount)
+}
@@ -132,9 +132,14 @@ func TestEndToEndConfigFetchStore(t *testing.T) {
 	assert.Equal(t, 1, sourceNames["Ars Technica AI"])
 	assert.Equal(t, 2, sourceNames["OpenAI Blog"])
 
+	for _, source := range cfg.Sources {
+		_, err := fetcher.FetchAndStore(ctx, queries, source, cfg, fetcher.FetchOptions{})
+		assert.ErrorIs(t, err, fetcher.ErrFeedUnchanged, "Unchanged feeds should be skipped")
+	}
+
 	secondRun := 0
 	for _, source := range cfg.Sources {
-		stored, err := fetcher.FetchAndStore(ctx, queries, source, cfg, fetcher.FetchOptions{})
+		stored, err := fetcher.FetchAndStore(ctx, queries, source, cfg, fetcher.FetchOptions{Force: true})
 		require.NoError(t, err)
 		secondRun += stored
 	}
@@ -146,6 +151,8 @@ func TestEndToEndConfigFetchStore(t *testing.T) {
 	assert.Len(t, finalArticles, 3, "Should still have only 3 articles after second run")
 }
 
+// createTestSchema creates an articles table as it existed before versioned
+// migrations and brings it to the current schema.
 func createTestSchema(db *sql.DB) error {
 	schema := `CREATE TABLE IF NOT EXISTS articles (
                 id INTEGER PRIMARY KEY,
@@ -161,6 +168,11 @@ func createTestSchema(db *sql.DB) error {
                 story_group_id TEXT
         );`
 
-	_, err := db.Exec(schema)
-	return err
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+
+	// Upgrade the legacy table through the migration runner, like an older
+	// database opened by a newer binary.
+	return database.InitSchema(db)
 }
@@ -0,0 +1,164 @@
+package fetcher
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// SourceAdapter turns a downloaded source document into articles. Adapters
+// are chosen by Source.Type; downloading, conditional requests and change
+// detection are shared by all of them.
+//
+// Parse should leave PublishedDate zero when the document has no date for an
+// item, so that undated items keep their document order.
+type SourceAdapter interface {
+	Parse(body []byte, source Source) ([]Article, error)
+}
+
+var (
+	adaptersMu sync.RWMutex
+	adapters   = map[string]SourceAdapter{
+		"":         feedAdapter{},
+		"rss":      feedAdapter{},
+		"atom":     feedAdapter{},
+		"jsonfeed": jsonFeedAdapter{},
+		"json":     jsonFeedAdapter{},
+		"sitemap":  sitemapAdapter{},
+		"html":     htmlAdapter{},
+	}
+)
+
+// RegisterAdapter makes an adapter available for sources of the given type,
+// replacing any adapter already registered for it. Types are case-insensitive.
+func RegisterAdapter(sourceType string, adapter SourceAdapter) {
+	adaptersMu.Lock()
+	defer adaptersMu.Unlock()
+	adapters[strings.ToLower(sourceType)] = adapter
+}
+
+// AdapterFor returns the adapter registered for a source type. An empty type
+// is treated as rss.
+func AdapterFor(sourceType string) (SourceAdapter, error) {
+	adaptersMu.RLock()
+	defer adaptersMu.RUnlock()
+
+	adapter, ok := adapters[strings.ToLower(strings.TrimSpace(sourceType))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported source type %q", sourceType)
+	}
+	return adapter, nil
+}
+
+// feedAdapter handles RSS and Atom feeds via gofeed.
+type feedAdapter struct{}
+
+func (feedAdapter) Parse(body []byte, source Source) ([]Article, error) {
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]Article, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		article := Article{
+			Title:       item.Title,
+			Link:        item.Link,
+			Content:     item.Content,
+			Description: item.Description,
+			GUID:        strings.TrimSpace(item.GUID),
+			Authors:     itemAuthors(item),
+			Categories:  item.Categories,
+			ImageURL:    itemImage(item),
+		}
+		if item.PublishedParsed != nil {
+			article.PublishedDate = *item.PublishedParsed
+		}
+		for _, enclosure := range item.Enclosures {
+			length, _ := strconv.ParseInt(strings.TrimSpace(enclosure.Length), 10, 64)
+			article.Enclosures = append(article.Enclosures, Enclosure{
+				URL:    enclosure.URL,
+				Type:   enclosure.Type,
+				Length: length,
+			})
+		}
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+// itemAuthors returns the names of an item's authors, falling back to their
+// email addresses when a feed gives no name.
+func itemAuthors(item *gofeed.Item) []string {
+	people := item.Authors
+	if len(people) == 0 && item.Author != nil {
+		people = []*gofeed.Person{item.Author}
+	}
+
+	var authors []string
+	for _, person := range people {
+		if name := firstNonBlank(person.Name, person.Email); name != "" {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+// itemImage returns the URL of an item's lead image. gofeed already looks at
+// iTunes images, media:content, image enclosures and the first <img> of RSS
+// items; media:thumbnail and Atom enclosures are checked here as well.
+func itemImage(item *gofeed.Item) string {
+	if item.Image != nil && item.Image.URL != "" {
+		return item.Image.URL
+	}
+	for _, thumbnail := range item.Extensions["media"]["thumbnail"] {
+		if url := thumbnail.Attrs["url"]; url != "" {
+			return url
+		}
+	}
+	for _, enclosure := range item.Enclosures {
+		if strings.HasPrefix(enclosure.Type, "image/") {
+			return enclosure.URL
+		}
+	}
+	return ""
+}
+
+// selectArticles orders articles newest first, with undated articles last in
+// feed order, applies the per-source limit in opts, and dates undated
+// articles with the current time.
+func selectArticles(articles []Article, source Source, opts FetchOptions) []Article {
+	sort.SliceStable(articles, func(i, j int) bool {
+		ti := articles[i].PublishedDate
+		tj := articles[j].PublishedDate
+		if ti.IsZero() != tj.IsZero() {
+			return tj.IsZero()
+		}
+		return ti.After(tj)
+	})
+
+	if opts.Limit != 0 && len(articles) > opts.Limit {
+		articles = articles[:opts.Limit]
+	}
+
+	now := time.Now()
+	for i := range articles {
+		if articles[i].PublishedDate.IsZero() {
+			articles[i].PublishedDate = now
+		}
+	}
+
+	if opts.Limit == 0 {
+		logging.Info("fetch_rss", fmt.Sprintf("Fetched %d articles from %s", len(articles), source.Name))
+	} else {
+		logging.Info("fetch_rss", fmt.Sprintf("Fetched %d (limit=%d) articles from %s", len(articles), opts.Limit, source.Name))
+	}
+	return articles
+}
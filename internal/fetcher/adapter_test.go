@@ -0,0 +1,242 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJSONFeed = `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "JSON Blog",
+  "items": [
+    {"id": "1", "url": "https://example.com/older", "title": "Older Post", "date_published": "2025-08-10T09:00:00Z"},
+    {"id": "https://example.com/id-link", "content_text": "Untitled note\nwith a second line", "date_modified": "2025-08-12T09:00:00+02:00"},
+    {"id": "3", "external_url": "https://other.example/linked", "title": "Linked Post", "date_published": "2025-08-11T09:00:00Z"},
+    {"id": "no-link", "title": "Dropped"}
+  ]
+}`
+
+const testSitemap = `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+  <url>
+    <loc>https://example.com/blog/2025/08/introducing-new-model.html</loc>
+    <lastmod>2025-08-12</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/about</loc>
+  </url>
+  <url>
+    <loc>https://example.com/news/123</loc>
+    <lastmod>2025-08-13T10:30:00+00:00</lastmod>
+    <news:news><news:title>Breaking News Title</news:title></news:news>
+  </url>
+  <url>
+    <loc>https://example.com/blog/old_post/</loc>
+    <lastmod>2024-01</lastmod>
+  </url>
+</urlset>`
+
+func serveBody(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetch_JSONFeed(t *testing.T) {
+	server := serveBody(t, testJSONFeed)
+	source := Source{Name: "JSON Blog", URL: server.URL, Type: "jsonfeed"}
+
+	articles, err := Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 3)
+
+	assert.Equal(t, "Untitled note", articles[0].Title)
+	assert.Equal(t, "https://example.com/id-link", articles[0].Link)
+	assert.Equal(t, "Linked Post", articles[1].Title)
+	assert.Equal(t, "https://other.example/linked", articles[1].Link)
+	assert.Equal(t, "Older Post", articles[2].Title)
+	assert.True(t, articles[2].PublishedDate.Equal(time.Date(2025, 8, 10, 9, 0, 0, 0, time.UTC)))
+}
+
+func TestFetch_JSONFeedRejectsOtherJSON(t *testing.T) {
+	server := serveBody(t, `{"items": []}`)
+	source := Source{Name: "Not a feed", URL: server.URL, Type: "jsonfeed"}
+
+	_, err := Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{})
+	assert.ErrorContains(t, err, "unrecognized version")
+}
+
+func TestFetch_Sitemap(t *testing.T) {
+	server := serveBody(t, testSitemap)
+	source := Source{Name: "Sitemap", URL: server.URL, Type: "sitemap"}
+
+	articles, err := Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 3, "entries without lastmod are skipped")
+
+	assert.Equal(t, "Breaking News Title", articles[0].Title)
+	assert.Equal(t, "https://example.com/news/123", articles[0].Link)
+	assert.Equal(t, "Introducing new model", articles[1].Title)
+	assert.True(t, articles[1].PublishedDate.Equal(time.Date(2025, 8, 12, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "Old post", articles[2].Title)
+
+	limited, err := Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+	assert.Equal(t, "https://example.com/news/123", limited[0].Link)
+}
+
+func TestFetch_SitemapIndex(t *testing.T) {
+	server := serveBody(t, `<?xml version="1.0"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-posts.xml</loc></sitemap>
+</sitemapindex>`)
+	source := Source{Name: "Index", URL: server.URL, Type: "sitemap"}
+
+	_, err := Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{})
+	assert.ErrorContains(t, err, "sitemap index")
+}
+
+type stubAdapter struct{ articles []Article }
+
+func (a stubAdapter) Parse(body []byte, source Source) ([]Article, error) {
+	return a.articles, nil
+}
+
+func TestAdapterFor(t *testing.T) {
+	for _, sourceType := range []string{"", "rss", "RSS", "atom", "jsonfeed", "sitemap"} {
+		_, err := AdapterFor(sourceType)
+		assert.NoError(t, err, sourceType)
+	}
+
+	_, err := AdapterFor("gopher")
+	assert.ErrorContains(t, err, `unsupported source type "gopher"`)
+
+	server := serveBody(t, "anything")
+	_, err = Fetch(context.Background(), Source{Name: "Gopher", URL: server.URL, Type: "gopher"}, testutil.TestConfig(), FetchOptions{})
+	assert.Error(t, err)
+
+	RegisterAdapter("Custom", stubAdapter{articles: []Article{{Title: "Stub", Link: "https://example.com/stub"}}})
+	t.Cleanup(func() {
+		adaptersMu.Lock()
+		delete(adapters, "custom")
+		adaptersMu.Unlock()
+	})
+
+	articles, err := Fetch(context.Background(), Source{Name: "Custom", URL: server.URL, Type: "custom"}, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "Stub", articles[0].Title)
+	assert.False(t, articles[0].PublishedDate.IsZero(), "undated articles get the fetch time")
+}
+
+func TestSelectArticles_UndatedLast(t *testing.T) {
+	day := time.Date(2025, 8, 10, 9, 0, 0, 0, time.UTC)
+	articles := []Article{
+		{Title: "Undated 1"},
+		{Title: "Old", PublishedDate: day},
+		{Title: "Undated 2"},
+		{Title: "Newest", PublishedDate: day.Add(48 * time.Hour)},
+		{Title: "Middle", PublishedDate: day.Add(24 * time.Hour)},
+	}
+
+	selected := selectArticles(articles, Source{Name: "Blog"}, FetchOptions{Limit: 4})
+	var titles []string
+	for _, article := range selected {
+		titles = append(titles, article.Title)
+	}
+	assert.Equal(t, []string{"Newest", "Middle", "Old", "Undated 1"}, titles)
+}
+
+func TestTitleFromURL(t *testing.T) {
+	assert.Equal(t, "My new model", titleFromURL("https://example.com/2024/05/my-new-model.html"))
+	assert.Equal(t, "Release notes", titleFromURL("https://example.com/release_notes/"))
+	assert.Equal(t, "example.com", titleFromURL("https://example.com/"))
+}
+
+func TestFetch_CarriesFeedContent(t *testing.T) {
+	server := serveBody(t, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+  <channel>
+    <title>Full Text Feed</title>
+    <item>
+      <title>Post</title>
+      <link>https://example.com/post</link>
+      <description>Short teaser</description>
+      <content:encoded><![CDATA[<p>The <em>whole</em> post.</p>]]></content:encoded>
+    </item>
+  </channel>
+</rss>`)
+
+	articles, err := Fetch(context.Background(), Source{Name: "Full", URL: server.URL}, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "<p>The <em>whole</em> post.</p>", articles[0].Content)
+	assert.Equal(t, "Short teaser", articles[0].Description)
+
+	server = serveBody(t, `{"version": "https://jsonfeed.org/version/1.1", "items": [
+  {"id": "1", "url": "https://example.com/1", "title": "Text", "summary": "a < b", "content_text": "First paragraph.\n\nSecond & last."}
+]}`)
+	articles, err = Fetch(context.Background(), Source{Name: "JSON", URL: server.URL, Type: "jsonfeed"}, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "<p>First paragraph.</p><p>Second &amp; last.</p>", articles[0].Content)
+	assert.Equal(t, "a &lt; b", articles[0].Description)
+}
+
+func TestFetch_ItemMetadata(t *testing.T) {
+	server := serveBody(t, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Podcast</title>
+    <item>
+      <title>Episode 12</title>
+      <link>https://example.com/ep12</link>
+      <guid isPermaLink="false">episode-12</guid>
+      <dc:creator>Jane Doe</dc:creator>
+      <category>Audio</category>
+      <category>Interviews</category>
+      <enclosure url="https://example.com/ep12.mp3" length="1234" type="audio/mpeg"/>
+      <media:thumbnail url="https://example.com/ep12.jpg"/>
+    </item>
+  </channel>
+</rss>`)
+
+	articles, err := Fetch(context.Background(), Source{Name: "Podcast", URL: server.URL}, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+
+	article := articles[0]
+	assert.Equal(t, "episode-12", article.GUID)
+	assert.Equal(t, []string{"Jane Doe"}, article.Authors)
+	assert.Equal(t, []string{"Audio", "Interviews"}, article.Categories)
+	assert.Equal(t, []Enclosure{{URL: "https://example.com/ep12.mp3", Type: "audio/mpeg", Length: 1234}}, article.Enclosures)
+	assert.Equal(t, "https://example.com/ep12.jpg", article.ImageURL)
+
+	server = serveBody(t, `{"version": "https://jsonfeed.org/version/1.1", "authors": [{"name": "Feed Author"}], "items": [
+  {"id": 7, "url": "https://example.com/7", "title": "Numbered", "tags": ["go"], "image": "https://example.com/7.png",
+   "attachments": [{"url": "https://example.com/7.pdf", "mime_type": "application/pdf", "size_in_bytes": 99}]},
+  {"id": "8", "url": "https://example.com/8", "title": "Signed", "author": {"name": "Old Style"}}
+]}`)
+	articles, err = Fetch(context.Background(), Source{Name: "JSON", URL: server.URL, Type: "jsonfeed"}, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 2)
+
+	assert.Equal(t, "7", articles[0].GUID)
+	assert.Equal(t, []string{"Feed Author"}, articles[0].Authors, "items without authors inherit the feed's")
+	assert.Equal(t, []string{"go"}, articles[0].Categories)
+	assert.Equal(t, "https://example.com/7.png", articles[0].ImageURL)
+	assert.Equal(t, []Enclosure{{URL: "https://example.com/7.pdf", Type: "application/pdf", Length: 99}}, articles[0].Enclosures)
+	assert.Equal(t, []string{"Old Style"}, articles[1].Authors)
+}
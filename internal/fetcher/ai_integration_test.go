@@ -3,15 +3,19 @@ package fetcher
 import (
 	"context"
 	"database/sql"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
 	"github.com/robertguss/rss-agent-cli/internal/ai/processor/mocks"
+	"github.com/robertguss/rss-agent-cli/internal/config"
 	"github.com/robertguss/rss-agent-cli/internal/database"
 	"github.com/robertguss/rss-agent-cli/internal/scraper"
 	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -71,6 +75,52 @@ func TestStoreArticlesWithAI_Success(t *testing.T) {
 	mockAI.AssertExpectations(t)
 }
 
+func TestStoreArticlesWithAI_TagsTopicsAndEntities(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	err = database.InitSchema(db)
+	require.NoError(t, err)
+
+	queries := database.New(db)
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "scraped article content", mock.Anything).Return(&processor.AnalysisResult{
+		Summary: "AI generated summary",
+		Topics:  []string{"AI", "Research"},
+		Entities: processor.Entities{
+			Organizations: []string{"OpenAI"},
+			People:        []string{"Sam Altman"},
+		},
+	}, nil)
+
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("scraped article content", nil),
+		AI:      mockAI,
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+	}
+
+	articles := []Article{{Title: "Tagged", Link: "https://example.com/tagged", PublishedDate: time.Now()}}
+
+	ctx := context.Background()
+	stored, err := StoreArticlesWithAI(ctx, deps, articles, Source{Name: "Test Source"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	byTopic, err := queries.ListArticlesByTopic(ctx, "research")
+	require.NoError(t, err)
+	require.Len(t, byTopic, 1)
+
+	entities, err := queries.ListArticleEntities(ctx, byTopic[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []database.ListArticleEntitiesRow{
+		{Name: "OpenAI", Kind: database.EntityOrganization},
+		{Name: "Sam Altman", Kind: database.EntityPerson},
+	}, entities)
+}
+
 func TestStoreArticlesWithAI_ScraperError(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
@@ -169,3 +219,215 @@ func TestStoreArticlesWithAI_AIError(t *testing.T) {
 
 	mockAI.AssertExpectations(t)
 }
+
+func TestStoreArticlesWithAI_FallsBackToFeedContent(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.InitSchema(db))
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "Feed **body** text.", mock.Anything).Return(&processor.AnalysisResult{
+		Summary: "summary from feed content",
+	}, nil)
+
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("", assert.AnError),
+		AI:      mockAI,
+		Queries: database.New(db),
+		Config:  testutil.TestConfig(),
+	}
+
+	articles := []Article{
+		{
+			Title:         "With feed content",
+			Link:          "https://example.com/with-content",
+			PublishedDate: time.Now(),
+			Content:       "<p>Feed <b>body</b> text.</p>",
+			Description:   "<p>Short description.</p>",
+		},
+		{
+			Title:         "Without feed content",
+			Link:          "https://example.com/without-content",
+			PublishedDate: time.Now(),
+		},
+	}
+
+	stored, err := StoreArticlesWithAI(context.Background(), deps, articles, Source{Name: "Test Source"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored)
+
+	var content, strategy, status sql.NullString
+	err = db.QueryRow("SELECT content, scrape_strategy, analysis_status FROM articles WHERE url = ?", "https://example.com/with-content").Scan(&content, &strategy, &status)
+	require.NoError(t, err)
+	assert.Equal(t, "Feed **body** text.", content.String)
+	assert.Equal(t, scraper.Feed, strategy.String)
+	assert.Equal(t, "completed", status.String)
+
+	var attempts int64
+	err = db.QueryRow("SELECT content, scrape_strategy, analysis_status, analysis_attempts FROM articles WHERE url = ?", "https://example.com/without-content").Scan(&content, &strategy, &status, &attempts)
+	require.NoError(t, err)
+	assert.False(t, content.Valid)
+	assert.False(t, strategy.Valid)
+	assert.Equal(t, "pending", status.String)
+	assert.Equal(t, int64(1), attempts)
+
+	mockAI.AssertExpectations(t)
+}
+
+func TestStoreArticlesWithAI_RecordsScrapeStrategy(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.InitSchema(db))
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "scraped", mock.Anything).Return(&processor.AnalysisResult{Summary: "s"}, nil)
+
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("scraped", nil),
+		AI:      mockAI,
+		Queries: database.New(db),
+		Config:  testutil.TestConfig(),
+	}
+
+	articles := []Article{{Title: "A", Link: "https://example.com/a", PublishedDate: time.Now(), Content: "<p>ignored</p>"}}
+	_, err = StoreArticlesWithAI(context.Background(), deps, articles, Source{Name: "Test Source"})
+	require.NoError(t, err)
+
+	article, err := deps.Queries.GetArticleByUrl(context.Background(), sql.NullString{String: "https://example.com/a", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, "scraped", article.Content.String)
+	assert.Equal(t, "custom", article.ScrapeStrategy.String)
+}
+
+func TestStoreArticlesWithAI_UsesSourcePrompt(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.InitSchema(db))
+
+	promptPath := filepath.Join(t.TempDir(), "papers.tmpl")
+	require.NoError(t, os.WriteFile(promptPath, []byte("Analyze the paper {{.Title}} from {{.Source}}:\n{{.Content}}"), 0644))
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.MatchedBy(func(ctx context.Context) bool {
+		return processor.PromptFrom(ctx) == "Analyze the paper Attention from arXiv:\nscraped"
+	}), "scraped", mock.Anything).Return(&processor.AnalysisResult{Summary: "s"}, nil)
+
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("scraped", nil),
+		AI:      mockAI,
+		Queries: database.New(db),
+		Config:  testutil.TestConfig(),
+		Prompts: processor.NewPrompts(),
+	}
+
+	articles := []Article{{Title: "Attention", Link: "https://example.com/paper", PublishedDate: time.Now()}}
+	_, err = StoreArticlesWithAI(context.Background(), deps, articles, Source{Name: "arXiv", Prompt: promptPath})
+	require.NoError(t, err)
+
+	article, err := deps.Queries.GetArticleByUrl(context.Background(), sql.NullString{String: "https://example.com/paper", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, "papers", article.PromptName.String)
+	expected, err := processor.LoadPrompt(promptPath)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Version, article.PromptVersion.String)
+	mockAI.AssertExpectations(t)
+}
+
+func TestStoreArticlesWithAI_LeavesArticlesPendingOverBudget(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.InitSchema(db))
+
+	ctx := context.Background()
+	queries := database.New(db)
+	require.NoError(t, queries.CreateAIUsage(ctx, database.CreateAIUsageParams{
+		RunID:        "earlier",
+		Provider:     "gemini",
+		Model:        "gemini-2.5-flash",
+		PromptTokens: 1_000_000,
+		CreatedAt:    time.Now().Unix(),
+	}))
+	usageCfg := config.UsageConfig{
+		Prices:        []config.ModelPrice{{Model: "gemini-2.5-flash", Input: 5}},
+		MonthlyBudget: 5,
+	}
+	tracker, err := usage.NewTracker(ctx, queries, usageCfg)
+	require.NoError(t, err)
+
+	mockAI := new(mocks.AIProcessor)
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("scraped", nil),
+		AI:      mockAI,
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+		Usage:   tracker,
+	}
+
+	articles := []Article{{Title: "Costly", Link: "https://example.com/costly", PublishedDate: time.Now()}}
+	stored, err := StoreArticlesWithAI(ctx, deps, articles, Source{Name: "Blog"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	article, err := queries.GetArticleByUrl(ctx, sql.NullString{String: "https://example.com/costly", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, "pending", article.AnalysisStatus.String)
+	assert.Equal(t, "scraped", article.Content.String)
+	mockAI.AssertNotCalled(t, "AnalyzeContentWithRetry", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// countingScraper records how often it is asked to scrape.
+type countingScraper struct {
+	scraper.MockScraper
+	calls int
+}
+
+func (s *countingScraper) ScrapeWithRetry(ctx context.Context, url string, cfg *config.Config) (string, error) {
+	s.calls++
+	return "scraped page", nil
+}
+
+func TestStoreArticlesWithAI_UsesLongFeedContentWithoutScraping(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.InitSchema(db))
+
+	fullText := strings.Repeat("Full article text from the feed. ", 10)
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, strings.TrimSpace(fullText), mock.Anything).Return(&processor.AnalysisResult{Summary: "from feed"}, nil)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "scraped page", mock.Anything).Return(&processor.AnalysisResult{Summary: "from page"}, nil)
+
+	cfg := testutil.TestConfig()
+	cfg.FeedContentMinLength = 200
+	counting := &countingScraper{}
+	deps := PipelineDeps{
+		Scraper: counting,
+		AI:      mockAI,
+		Queries: database.New(db),
+		Config:  cfg,
+	}
+
+	articles := []Article{
+		{Title: "Full", Link: "https://example.com/full", PublishedDate: time.Now(), Content: "<p>" + fullText + "</p>"},
+		{Title: "Teaser", Link: "https://example.com/teaser", PublishedDate: time.Now(), Description: "<p>Just a teaser.</p>"},
+	}
+
+	stored, err := StoreArticlesWithAI(context.Background(), deps, articles, Source{Name: "Test Source"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored)
+	assert.Equal(t, 1, counting.calls, "only the teaser needs a network scrape")
+
+	full, err := deps.Queries.GetArticleByUrl(context.Background(), sql.NullString{String: "https://example.com/full", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, scraper.Feed, full.ScrapeStrategy.String)
+	assert.Equal(t, "from feed", full.Summary.String)
+
+	teaser, err := deps.Queries.GetArticleByUrl(context.Background(), sql.NullString{String: "https://example.com/teaser", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, "scraped page", teaser.Content.String)
+	assert.Equal(t, "custom", teaser.ScrapeStrategy.String)
+}
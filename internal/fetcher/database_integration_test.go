@@ -102,6 +102,154 @@ func TestStoreArticles_DuplicateHandling(t *testing.T) {
 	assert.Len(t, dbArticles, 1)
 }
 
+func TestStoreArticles_MetadataAndGUIDDedup(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, createTestSchema(db))
+
+	queries := database.New(db)
+	source := Source{Name: "Test Source", URL: "https://example.com/feed"}
+	cfg := testutil.TestConfig()
+	ctx := context.Background()
+
+	article := Article{
+		Title:         "Episode 12",
+		Link:          "https://example.com/ep12",
+		PublishedDate: time.Now(),
+		GUID:          "episode-12",
+		Authors:       []string{"Jane Doe", "John Roe"},
+		Categories:    []string{"Audio"},
+		Enclosures:    []Enclosure{{URL: "https://example.com/ep12.mp3", Type: "audio/mpeg"}},
+		ImageURL:      "https://example.com/ep12.jpg",
+	}
+	stored, err := StoreArticles(ctx, queries, []Article{article}, source, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	dbArticles, err := queries.ListArticles(ctx)
+	require.NoError(t, err)
+	require.Len(t, dbArticles, 1)
+	assert.Equal(t, "episode-12", dbArticles[0].Guid.String)
+	assert.Equal(t, "Jane Doe, John Roe", dbArticles[0].Author.String)
+	assert.Equal(t, "https://example.com/ep12.jpg", dbArticles[0].ImageUrl.String)
+
+	categories, err := queries.ListArticleCategories(ctx, dbArticles[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Audio"}, categories)
+	enclosures, err := queries.ListArticleEnclosures(ctx, dbArticles[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, enclosures, 1)
+
+	// The same item with a different link is recognized by its GUID.
+	article.Link = "https://example.com/episodes/12"
+	stored, err = StoreArticles(ctx, queries, []Article{article}, source, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored)
+
+	// GUIDs are only compared within a source.
+	stored, err = StoreArticles(ctx, queries, []Article{article}, Source{Name: "Other Source"}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+}
+
+func TestStoreArticles_CanonicalURLDedup(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, createTestSchema(db))
+
+	queries := database.New(db)
+	source := Source{Name: "Test Source", URL: "https://example.com/feed"}
+	cfg := testutil.TestConfig()
+	ctx := context.Background()
+
+	articles := []Article{
+		{Title: "Post", Link: "https://Example.com/post?utm_source=rss&id=1#comments", PublishedDate: time.Now()},
+		{Title: "Post", Link: "http://www.example.com/post/?id=1", PublishedDate: time.Now()},
+		{Title: "Post", Link: "https://example.com/post/amp?id=1", PublishedDate: time.Now()},
+	}
+	stored, err := StoreArticles(ctx, queries, articles, source, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	dbArticles, err := queries.ListArticles(ctx)
+	require.NoError(t, err)
+	require.Len(t, dbArticles, 1)
+	assert.Equal(t, "https://example.com/post?id=1", dbArticles[0].Url.String)
+	assert.Equal(t, "https://example.com/post?id=1", dbArticles[0].CanonicalUrl.String)
+}
+
+func TestStoreArticles_GroupsStoriesAcrossSources(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, createTestSchema(db))
+
+	queries := database.New(db)
+	cfg := testutil.TestConfig()
+	ctx := context.Background()
+	published := time.Now()
+
+	_, err = StoreArticles(ctx, queries, []Article{
+		{Title: "OpenAI launches GPT-5 with better reasoning and coding", Link: "https://one.example.com/gpt-5", PublishedDate: published},
+		{Title: "Apple buys a robotics startup", Link: "https://one.example.com/apple", PublishedDate: published},
+	}, Source{Name: "One"}, cfg)
+	require.NoError(t, err)
+	_, err = StoreArticles(ctx, queries, []Article{
+		{Title: "OpenAI Launches GPT-5 With Better Reasoning, Coding", Link: "https://two.example.com/openai-gpt-5", PublishedDate: published.Add(time.Hour)},
+	}, Source{Name: "Two"}, cfg)
+	require.NoError(t, err)
+
+	groups := make(map[string]string)
+	dbArticles, err := queries.ListArticles(ctx)
+	require.NoError(t, err)
+	for _, article := range dbArticles {
+		require.True(t, article.StoryGroupID.Valid, article.Url.String)
+		groups[article.Url.String] = article.StoryGroupID.String
+	}
+	assert.Equal(t, groups["https://one.example.com/gpt-5"], groups["https://two.example.com/openai-gpt-5"])
+	assert.NotEqual(t, groups["https://one.example.com/gpt-5"], groups["https://one.example.com/apple"])
+}
+
+func TestStoreArticles_UsesCanonicalLink(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, createTestSchema(db))
+
+	var pageRequests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pageRequests++
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><link rel="canonical" href="/articles/post"></head></html>`))
+	}))
+	defer server.Close()
+
+	queries := database.New(db)
+	source := Source{Name: "Test Source", URL: server.URL + "/feed"}
+	cfg := testutil.TestConfig()
+	cfg.URLs.UseCanonicalLink = true
+	ctx := context.Background()
+
+	articles := []Article{{Title: "Post", Link: server.URL + "/p/123", PublishedDate: time.Now()}}
+	stored, err := StoreArticles(ctx, queries, articles, source, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	dbArticles, err := queries.ListArticles(ctx)
+	require.NoError(t, err)
+	require.Len(t, dbArticles, 1)
+	assert.Equal(t, server.URL+"/articles/post", dbArticles[0].Url.String)
+
+	// The canonical link found for the feed link is remembered, so the item
+	// resolves to the stored article without downloading the page again.
+	stored, err = StoreArticles(ctx, queries, articles, source, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored)
+	assert.Equal(t, 1, pageRequests)
+}
+
 func TestStoreArticles_EmptyList(t *testing.T) {
 	db, err := sql.Open("sqlite", ":memory:")
 	require.NoError(t, err)
@@ -177,6 +325,8 @@ func TestFetchAndStore_Integration(t *testing.T) {
 	assert.Equal(t, "Integration Test Source", dbArticles[0].SourceName.String)
 }
 
+// createTestSchema creates an articles table as it existed before versioned
+// migrations and brings it to the current schema.
 func createTestSchema(db *sql.DB) error {
 	schema := `CREATE TABLE IF NOT EXISTS articles (
                 id INTEGER PRIMARY KEY,
@@ -193,6 +343,11 @@ func createTestSchema(db *sql.DB) error {
                 story_group_id TEXT
         );`
 
-	_, err := db.Exec(schema)
-	return err
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+
+	// Upgrade the legacy table through the migration runner, like an older
+	// database opened by a newer binary.
+	return database.InitSchema(db)
 }
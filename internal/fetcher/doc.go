@@ -1,4 +1,6 @@
-// Package fetcher handles RSS feed fetching and article processing.
-// It coordinates between web scraping, AI analysis, and database storage
-// to provide a complete news aggregation pipeline.
+// Package fetcher handles source fetching and article processing. Sources
+// are parsed by a SourceAdapter chosen by their type: RSS/Atom, JSON Feed, XML
+// sitemap or an HTML page scraped with CSS selectors. It coordinates between
+// web scraping, AI analysis, and database storage to provide a complete news
+// aggregation pipeline.
 package fetcher
@@ -0,0 +1,224 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+	"github.com/robertguss/rss-agent-cli/pkg/retry"
+)
+
+// ErrFeedUnchanged is returned when a feed has not changed since it was last
+// fetched, either because the server answered 304 Not Modified or because the
+// body is byte-for-byte identical.
+var ErrFeedUnchanged = errors.New("feed unchanged since last fetch")
+
+// maxFeedSize caps how much of a feed body is read.
+const maxFeedSize = 10 << 20
+
+var feedClient = &http.Client{}
+
+// FeedState holds the HTTP validators and body hash remembered for a feed
+// between fetches. Limit is the FetchOptions.Limit its items were taken
+// with: zero means all of them, a negative value that it is not known.
+type FeedState struct {
+	ETag         string
+	LastModified string
+	BodyHash     string
+	Limit        int
+}
+
+// FetchIfChanged works like Fetch but sends the validators in state as
+// If-None-Match and If-Modified-Since headers. It returns ErrFeedUnchanged
+// when the feed has not changed, unless opts.Limit takes more items than
+// were taken when state was saved. The returned state reflects the latest
+// response and should be saved with SaveFeedState once the articles are stored.
+func FetchIfChanged(ctx context.Context, source Source, cfg *config.Config, opts FetchOptions, state FeedState) ([]Article, FeedState, error) {
+	adapter, err := AdapterFor(source.Type)
+	if err != nil {
+		return nil, state, errs.Wrap("fetch "+source.Name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.NetworkTimeout)
+	defer cancel()
+
+	if opts.Force || !coversLimit(state.Limit, opts.Limit) {
+		state = FeedState{}
+	}
+
+	var body []byte
+	var notModified bool
+	next := state
+	err = retry.DoWithCallback(ctx, cfg.RetryConfig(), func() error {
+		var e error
+		body, next, notModified, e = downloadFeed(ctx, source.URL, state)
+		return e
+	}, func(attempt int, err error) {
+		logging.Retry("fetch_rss", attempt, err)
+	})
+
+	if err != nil {
+		wrappedErr := errs.Wrap("fetch rss "+source.URL, err)
+		logging.Error("fetch_rss", wrappedErr)
+		return nil, state, wrappedErr
+	}
+
+	if notModified || (state.BodyHash != "" && next.BodyHash == state.BodyHash) {
+		logging.Info("fetch_rss", fmt.Sprintf("Feed unchanged for %s", source.Name))
+		return nil, next, ErrFeedUnchanged
+	}
+
+	articles, err := adapter.Parse(body, source)
+	if err != nil {
+		wrappedErr := errs.Wrap("fetch rss "+source.URL, err)
+		logging.Error("fetch_rss", wrappedErr)
+		return nil, state, wrappedErr
+	}
+
+	next.Limit = opts.Limit
+	return selectArticles(articles, source, opts), next, nil
+}
+
+// coversLimit reports whether the items taken from a feed with the saved
+// limit include all those limit would take.
+func coversLimit(saved, limit int) bool {
+	if saved == 0 {
+		return true
+	}
+	return saved > 0 && limit > 0 && limit <= saved
+}
+
+// downloadFeed performs a conditional GET for url. On 304 Not Modified it
+// reports notModified and keeps the previous body hash.
+func downloadFeed(ctx context.Context, url string, state FeedState) (body []byte, next FeedState, notModified bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, state, false, err
+	}
+	req.Header.Set("User-Agent", "Gofeed/1.0")
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
+	}
+
+	resp, err := feedClient.Do(req)
+	if err != nil {
+		return nil, state, false, err
+	}
+	defer resp.Body.Close()
+
+	next = state
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		next.ETag = etag
+	}
+	if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+		next.LastModified = lastModified
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, next, true, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, state, false, gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	body, err = io.ReadAll(io.LimitReader(resp.Body, maxFeedSize))
+	if err != nil {
+		return nil, state, false, err
+	}
+
+	sum := sha256.Sum256(body)
+	next.BodyHash = hex.EncodeToString(sum[:])
+	return body, next, false, nil
+}
+
+// LoadFeedState returns the state saved for a feed URL, or an empty state if
+// the feed has never been fetched.
+func LoadFeedState(ctx context.Context, queries *database.Queries, url string) (FeedState, error) {
+	feed, err := queries.GetFeed(ctx, url)
+	if err == sql.ErrNoRows {
+		return FeedState{}, nil
+	}
+	if err != nil {
+		return FeedState{}, errs.Wrap("load feed state", err)
+	}
+
+	limit := -1
+	if feed.ItemLimit.Valid {
+		limit = int(feed.ItemLimit.Int64)
+	}
+
+	return FeedState{
+		ETag:         feed.Etag.String,
+		LastModified: feed.LastModified.String,
+		BodyHash:     feed.BodyHash.String,
+		Limit:        limit,
+	}, nil
+}
+
+// SaveFeedState records the state for a feed URL and marks it as checked now.
+func SaveFeedState(ctx context.Context, queries *database.Queries, url string, state FeedState) error {
+	err := queries.UpsertFeed(ctx, database.UpsertFeedParams{
+		SourceUrl:    url,
+		Etag:         sql.NullString{String: state.ETag, Valid: state.ETag != ""},
+		LastModified: sql.NullString{String: state.LastModified, Valid: state.LastModified != ""},
+		BodyHash:     sql.NullString{String: state.BodyHash, Valid: state.BodyHash != ""},
+		ItemLimit:    sql.NullInt64{Int64: int64(state.Limit), Valid: state.Limit >= 0},
+	})
+	if err != nil {
+		return errs.Wrap("save feed state", err)
+	}
+	return nil
+}
+
+// fetchChanged loads the saved state for source, fetches it conditionally
+// and returns the articles together with the state to save after storing
+// them. When the feed is unchanged the refreshed state is saved right away
+// and ErrFeedUnchanged is returned. Every attempt is recorded in the source's
+// health, and quarantined sources are skipped with a *QuarantineError unless
+// opts.Force is set.
+func fetchChanged(ctx context.Context, queries *database.Queries, source Source, cfg *config.Config, opts FetchOptions) ([]Article, FeedState, error) {
+	if !opts.Force {
+		if err := checkQuarantine(ctx, queries, source); err != nil {
+			return nil, FeedState{}, err
+		}
+	}
+
+	state, err := LoadFeedState(ctx, queries, source.URL)
+	if err != nil {
+		return nil, state, err
+	}
+
+	start := time.Now()
+	articles, next, err := FetchIfChanged(ctx, source, cfg, opts, state)
+	latency := time.Since(start)
+
+	switch {
+	case errors.Is(err, ErrFeedUnchanged):
+		recordFetchSuccess(ctx, queries, source, latency, 0)
+		if saveErr := SaveFeedState(ctx, queries, source.URL, next); saveErr != nil {
+			logging.Warn("save_feed_state", saveErr.Error())
+		}
+	case err != nil:
+		recordFetchFailure(ctx, queries, source, cfg, latency, err)
+	default:
+		recordFetchSuccess(ctx, queries, source, latency, len(articles))
+	}
+	return articles, next, err
+}
@@ -0,0 +1,229 @@
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/robertguss/rss-agent-cli/internal/tui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+const cachedFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+    <channel>
+        <title>Cached Feed</title>
+        <link>https://example.com</link>
+        <item>
+            <title>Cached Article</title>
+            <link>https://example.com/cached</link>
+            <pubDate>Wed, 13 Aug 2025 20:28:20 +0000</pubDate>
+        </item>
+    </channel>
+</rss>`
+
+func TestFetchIfChanged_NotModified(t *testing.T) {
+	var conditional atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Wed, 13 Aug 2025 20:28:20 GMT" {
+			conditional.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 13 Aug 2025 20:28:20 GMT")
+		_, _ = w.Write([]byte(cachedFeed))
+	}))
+	defer server.Close()
+
+	source := Source{Name: "Cached", URL: server.URL, Type: "rss"}
+	cfg := testutil.TestConfig()
+	ctx := context.Background()
+
+	articles, state, err := FetchIfChanged(ctx, source, cfg, FetchOptions{}, FeedState{})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, `"v1"`, state.ETag)
+	assert.Equal(t, "Wed, 13 Aug 2025 20:28:20 GMT", state.LastModified)
+	assert.NotEmpty(t, state.BodyHash)
+
+	articles, next, err := FetchIfChanged(ctx, source, cfg, FetchOptions{}, state)
+	assert.ErrorIs(t, err, ErrFeedUnchanged)
+	assert.Nil(t, articles)
+	assert.Equal(t, state, next)
+	assert.Equal(t, int32(1), conditional.Load())
+
+	articles, _, err = FetchIfChanged(ctx, source, cfg, FetchOptions{Force: true}, state)
+	require.NoError(t, err)
+	assert.Len(t, articles, 1, "Force ignores the saved validators")
+}
+
+func TestFetchIfChanged_IdenticalBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(cachedFeed))
+	}))
+	defer server.Close()
+
+	source := Source{Name: "No validators", URL: server.URL, Type: "rss"}
+	cfg := testutil.TestConfig()
+	ctx := context.Background()
+
+	_, state, err := FetchIfChanged(ctx, source, cfg, FetchOptions{}, FeedState{})
+	require.NoError(t, err)
+	assert.Empty(t, state.ETag)
+
+	_, _, err = FetchIfChanged(ctx, source, cfg, FetchOptions{}, state)
+	assert.ErrorIs(t, err, ErrFeedUnchanged)
+
+	_, _, err = FetchIfChanged(ctx, source, cfg, FetchOptions{}, FeedState{BodyHash: "stale"})
+	assert.NoError(t, err)
+}
+
+func TestFetchAndStore_SkipsUnchangedFeeds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(cachedFeed))
+	}))
+	defer server.Close()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.InitSchema(db))
+	queries := database.New(db)
+
+	source := Source{Name: "Cached", URL: server.URL, Type: "rss"}
+	cfg := testutil.TestConfig()
+	ctx := context.Background()
+
+	stored, err := FetchAndStore(ctx, queries, source, cfg, FetchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	state, err := LoadFeedState(ctx, queries, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, `"v1"`, state.ETag)
+
+	stored, err = FetchAndStore(ctx, queries, source, cfg, FetchOptions{})
+	assert.ErrorIs(t, err, ErrFeedUnchanged)
+	assert.Equal(t, 0, stored)
+
+	feed, err := queries.GetFeed(ctx, server.URL)
+	require.NoError(t, err)
+	assert.True(t, feed.CheckedAt.Valid)
+}
+
+func TestFetchAndStore_LargerLimitRefetches(t *testing.T) {
+	const feed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+    <channel>
+        <title>Busy Feed</title>
+        <item><title>One</title><link>https://example.com/1</link><pubDate>Wed, 13 Aug 2025 20:28:20 +0000</pubDate></item>
+        <item><title>Two</title><link>https://example.com/2</link><pubDate>Tue, 12 Aug 2025 20:28:20 +0000</pubDate></item>
+        <item><title>Three</title><link>https://example.com/3</link><pubDate>Mon, 11 Aug 2025 20:28:20 +0000</pubDate></item>
+    </channel>
+</rss>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(feed))
+	}))
+	defer server.Close()
+
+	queries := setupHealthDB(t)
+	source := Source{Name: "Busy", URL: server.URL, Type: "rss"}
+	cfg := testutil.TestConfig()
+	ctx := context.Background()
+
+	stored, err := FetchAndStore(ctx, queries, source, cfg, FetchOptions{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	_, err = FetchAndStore(ctx, queries, source, cfg, FetchOptions{Limit: 1})
+	assert.ErrorIs(t, err, ErrFeedUnchanged)
+
+	stored, err = FetchAndStore(ctx, queries, source, cfg, FetchOptions{Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored, "a larger limit takes the items the smaller one left out")
+
+	_, err = FetchAndStore(ctx, queries, source, cfg, FetchOptions{Limit: 2})
+	assert.ErrorIs(t, err, ErrFeedUnchanged)
+
+	state, err := LoadFeedState(ctx, queries, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 3, state.Limit)
+
+	state.Limit = -1
+	_, _, err = FetchIfChanged(ctx, source, cfg, FetchOptions{Limit: 1}, state)
+	assert.NoError(t, err, "a feed saved without its limit is fetched again")
+}
+
+func TestFetchAndStoreWithAIProgress_KeepsFeedStateWhenStoreFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(cachedFeed))
+	}))
+	defer server.Close()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.InitSchema(db))
+	_, err = db.Exec(`CREATE TRIGGER reject_articles BEFORE INSERT ON articles BEGIN SELECT RAISE(ABORT, 'disk full'); END`)
+	require.NoError(t, err)
+
+	deps := PipelineDeps{Queries: database.New(db), Config: testutil.TestConfig()}
+	source := Source{Name: "Cached", URL: server.URL, Type: "rss"}
+	ctx := context.Background()
+	progress := make(chan tui.DetailedProgressMsg, 100)
+
+	stored, err := FetchAndStoreWithAIProgress(ctx, deps, source, FetchOptions{}, progress)
+	assert.ErrorContains(t, err, "disk full")
+	assert.Equal(t, 0, stored)
+
+	state, err := LoadFeedState(ctx, deps.Queries, server.URL)
+	require.NoError(t, err)
+	assert.Empty(t, state.ETag, "the feed state is not saved while articles are missing")
+
+	_, err = db.Exec(`DROP TRIGGER reject_articles`)
+	require.NoError(t, err)
+	stored, err = FetchAndStoreWithAIProgress(ctx, deps, source, FetchOptions{}, progress)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+}
+
+func TestProcessSourcesConcurrently_ReportsUnchanged(t *testing.T) {
+	sources := []Source{{Name: "A"}, {Name: "B"}}
+	process := func(ctx context.Context, source Source, opts FetchOptions, progress chan<- tui.DetailedProgressMsg) (int, error) {
+		if source.Name == "A" {
+			return 0, ErrFeedUnchanged
+		}
+		return 2, nil
+	}
+
+	results := ProcessSourcesConcurrently(context.Background(), sources, 2, process, FetchOptions{}, nil)
+
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Unchanged)
+	assert.NoError(t, results[0].Error)
+	assert.False(t, results[1].Unchanged)
+	assert.Equal(t, 2, results[1].Added)
+}
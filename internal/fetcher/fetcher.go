@@ -3,17 +3,22 @@ package fetcher
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
-	"github.com/mmcdole/gofeed"
 	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
 	"github.com/robertguss/rss-agent-cli/internal/config"
 	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/jobs"
 	"github.com/robertguss/rss-agent-cli/internal/scraper"
+	"github.com/robertguss/rss-agent-cli/internal/story"
 	"github.com/robertguss/rss-agent-cli/internal/tui"
+	"github.com/robertguss/rss-agent-cli/internal/urlnorm"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
 	"github.com/robertguss/rss-agent-cli/pkg/errs"
 	"github.com/robertguss/rss-agent-cli/pkg/logging"
 	"github.com/robertguss/rss-agent-cli/pkg/retry"
@@ -23,96 +28,61 @@ type Source = config.Source
 
 // FetchOptions holds configuration options for fetching articles.
 type FetchOptions struct {
-	Limit int // Maximum articles to fetch per source (0 = unlimited)
+	Limit int  // Maximum articles to fetch per source (0 = unlimited)
+	Force bool // Ignore cached feed validators and process unchanged feeds
 }
 
+// Enclosure is a media file attached to a feed item, such as a podcast
+// episode or an image.
+type Enclosure = database.EnclosureRef
+
 // Article represents a news article with basic metadata from RSS feeds.
+// Content and Description hold the HTML the feed embedded for the item, if
+// any, and serve as a fallback when the article page cannot be scraped. GUID
+// is the item's identifier within its source, used with the link to spot
+// items already stored; ImageURL is the item's lead image.
 type Article struct {
 	Title         string
 	Link          string
 	PublishedDate time.Time
+	Content       string
+	Description   string
+	GUID          string
+	Authors       []string
+	Categories    []string
+	Enclosures    []Enclosure
+	ImageURL      string
 }
 
 // PipelineDeps holds dependencies for the AI-enhanced article processing pipeline.
+// Prompts caches the prompt templates named in the config and Usage records
+// the AI calls made and enforces the monthly budget; either may be nil. With
+// Jobs set, new articles are stored pending and their scraping and analysis
+// queued there, to be run by a JobRunner, instead of done inline.
 type PipelineDeps struct {
 	Scraper scraper.Scraper
 	AI      processor.AIProcessor
 	Queries *database.Queries
 	Config  *config.Config
+	Prompts *processor.Prompts
+	Usage   *usage.Tracker
+	Jobs    *jobs.Queue
 }
 
-// Fetch retrieves articles from an RSS feed source with timeout and retry logic.
-// It parses the RSS feed and returns a list of articles with metadata.
+// Fetch retrieves articles from a source with timeout and retry logic. The
+// source is parsed by the SourceAdapter registered for its type.
 func Fetch(ctx context.Context, source Source, cfg *config.Config, opts FetchOptions) ([]Article, error) {
-	ctx, cancel := context.WithTimeout(ctx, cfg.NetworkTimeout)
-	defer cancel()
-
-	var feed *gofeed.Feed
-	err := retry.DoWithCallback(ctx, cfg.RetryConfig(), func() error {
-		parser := gofeed.NewParser()
-		var e error
-		feed, e = parser.ParseURLWithContext(source.URL, ctx)
-		return e
-	}, func(attempt int, err error) {
-		logging.Retry("fetch_rss", attempt, err)
-	})
-
-	if err != nil {
-		wrappedErr := errs.Wrap("fetch rss "+source.URL, err)
-		logging.Error("fetch_rss", wrappedErr)
-		return nil, wrappedErr
-	}
-
-	sort.SliceStable(feed.Items, func(i, j int) bool {
-		ti := feed.Items[i].PublishedParsed
-		tj := feed.Items[j].PublishedParsed
-		if ti == nil || tj == nil {
-			return false
-		}
-		return ti.After(*tj)
-	})
-
-	limit := opts.Limit
-	if limit == 0 || limit > len(feed.Items) {
-		limit = len(feed.Items)
-	}
-
-	articles := make([]Article, 0, limit)
-	for i, item := range feed.Items {
-		if opts.Limit != 0 && i >= opts.Limit {
-			break
-		}
-
-		publishedDate := time.Now()
-		if item.PublishedParsed != nil {
-			publishedDate = *item.PublishedParsed
-		}
-
-		article := Article{
-			Title:         item.Title,
-			Link:          item.Link,
-			PublishedDate: publishedDate,
-		}
-		articles = append(articles, article)
-	}
-
-	if opts.Limit == 0 {
-		logging.Info("fetch_rss", fmt.Sprintf("Fetched %d articles from %s", len(articles), source.Name))
-	} else {
-		logging.Info("fetch_rss", fmt.Sprintf("Fetched %d (limit=%d) articles from %s", len(articles), opts.Limit, source.Name))
-	}
-	return articles, nil
+	articles, _, err := FetchIfChanged(ctx, source, cfg, opts, FeedState{})
+	return articles, err
 }
 
 func StoreArticles(ctx context.Context, queries *database.Queries, articles []Article, source Source, cfg *config.Config) (int, error) {
 	stored := 0
 
 	for _, article := range articles {
+		article = canonicalizeArticle(ctx, queries, cfg, source, article)
 		err := retry.Do(ctx, cfg.RetryConfig(), func() error {
-			_, err := queries.GetArticleByUrl(ctx, sql.NullString{
-				String: article.Link,
-				Valid:  true,
-			})
+			_, err := findArticle(ctx, queries, source, article)
 
 			if err == sql.ErrNoRows {
 				params := database.CreateArticleParams{
@@ -154,12 +124,18 @@ func StoreArticles(ctx context.Context, queries *database.Queries, articles []Ar
 						String: "",
 						Valid:  false,
 					},
+					Guid:         nullString(article.GUID),
+					Author:       nullString(article.Byline()),
+					ImageUrl:     nullString(article.ImageURL),
+					CanonicalUrl: nullString(urlnorm.Key(article.Link)),
 				}
 
-				_, err = queries.CreateArticle(ctx, params)
+				created, err := queries.CreateArticle(ctx, params)
 				if err != nil {
 					return errs.Wrap("create article", err)
 				}
+				storeMetadata(ctx, queries, created.ID, article)
+				assignStory(ctx, queries, cfg, created)
 				stored++
 				return nil
 			} else if err != nil {
@@ -177,60 +153,191 @@ func StoreArticles(ctx context.Context, queries *database.Queries, articles []Ar
 	return stored, nil
 }
 
+// Byline returns the article's authors as a single display string.
+func (a Article) Byline() string {
+	return strings.Join(a.Authors, ", ")
+}
+
+// findArticle returns the stored copy of an article, matched by the key of
+// its canonical link, by its exact link for articles stored before links
+// were canonicalized, or, for items whose link changed, by GUID within the
+// same source. It returns sql.ErrNoRows if the article is new.
+func findArticle(ctx context.Context, queries *database.Queries, source Source, article Article) (database.Article, error) {
+	existing, err := queries.GetArticleByCanonicalUrl(ctx, nullString(urlnorm.Key(article.Link)))
+	if err != sql.ErrNoRows {
+		return existing, err
+	}
+	existing, err = queries.GetArticleByUrl(ctx, nullString(article.Link))
+	if err != sql.ErrNoRows || article.GUID == "" {
+		return existing, err
+	}
+	return queries.GetArticleBySourceGuid(ctx, database.GetArticleBySourceGuidParams{
+		SourceName: nullString(source.Name),
+		Guid:       nullString(article.GUID),
+	})
+}
+
+// canonicalizeArticle rewrites an article's link to its canonical form, so
+// that tracking parameters and AMP variants neither get stored nor defeat
+// duplicate detection. With URLs.UseCanonicalLink set, the page of an
+// article not stored yet is also checked for a <link rel="canonical">. The
+// link found is remembered for the feed link, so the page is downloaded only
+// once however often the item stays in the feed.
+func canonicalizeArticle(ctx context.Context, queries *database.Queries, cfg *config.Config, source Source, article Article) Article {
+	norm := urlnorm.New(cfg.URLs.StripParams...)
+	article.Link = norm.Canonical(article.Link)
+	if !cfg.URLs.UseCanonicalLink {
+		return article
+	}
+	if _, err := findArticle(ctx, queries, source, article); err != sql.ErrNoRows {
+		return article
+	}
+
+	key := urlnorm.Key(article.Link)
+	if link, err := queries.GetCanonicalLink(ctx, key); err == nil {
+		article.Link = link
+		return article
+	} else if err != sql.ErrNoRows {
+		logging.Warn("canonical_link", fmt.Sprintf("Failed to look up canonical link of %s: %v", article.Link, err))
+	}
+
+	link, err := scraper.CanonicalLink(ctx, article.Link, cfg)
+	if err != nil {
+		logging.Warn("canonical_link", fmt.Sprintf("Failed to read canonical link of %s: %v", article.Link, err))
+		return article
+	}
+	if link == "" {
+		return article
+	}
+	article.Link = norm.Canonical(link)
+	err = queries.PutCanonicalLink(ctx, database.PutCanonicalLinkParams{LinkKey: key, CanonicalUrl: article.Link})
+	if err != nil {
+		logging.Warn("canonical_link", fmt.Sprintf("Failed to remember canonical link of %s: %v", article.Link, err))
+	}
+	return article
+}
+
+// storeMetadata records the authors, categories and enclosures of a newly
+// stored article. The article is usable without them, so failures are
+// logged, not returned.
+func storeMetadata(ctx context.Context, queries *database.Queries, articleID int64, article Article) {
+	err := queries.SetArticleMetadata(ctx, articleID, database.ArticleMetadata{
+		Authors:    article.Authors,
+		Categories: article.Categories,
+		Enclosures: article.Enclosures,
+	})
+	if err != nil {
+		logging.Warn("store_metadata", fmt.Sprintf("Failed to store metadata for %s: %v", article.Link, err))
+	}
+}
+
+// assignStory groups a stored article with near-duplicates from other
+// sources, when it is stored and again once its content is scraped. Like
+// metadata, story groups are not essential to the article, so
+// failures are logged, not returned.
+func assignStory(ctx context.Context, queries *database.Queries, cfg *config.Config, created database.Article) {
+	if _, err := story.Assign(ctx, queries, cfg.Stories, created); err != nil {
+		logging.Warn("assign_story", fmt.Sprintf("Failed to group %s into a story: %v", created.Url.String, err))
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
 // FetchAndStore fetches articles from a source and stores them in the database.
 // It combines the fetch and store operations, returning the number of articles stored.
+// Feeds that have not changed since the last fetch are skipped with
+// ErrFeedUnchanged.
 func FetchAndStore(ctx context.Context, queries *database.Queries, source Source, cfg *config.Config, opts FetchOptions) (int, error) {
-	articles, err := Fetch(ctx, source, cfg, opts)
+	articles, state, err := fetchChanged(ctx, queries, source, cfg, opts)
 	if err != nil {
 		return 0, err
 	}
 
-	return StoreArticles(ctx, queries, articles, source, cfg)
+	stored, err := StoreArticles(ctx, queries, articles, source, cfg)
+	if err != nil {
+		return stored, err
+	}
+
+	saveFeedState(ctx, queries, source, state)
+	return stored, nil
 }
 
+// FetchAndStoreWithAI fetches a source, then scrapes, analyzes and stores its
+// new articles, or stores them and queues that work if deps.Jobs is set.
+// Like FetchAndStore it returns ErrFeedUnchanged for feeds that have not
+// changed since the last fetch.
 func FetchAndStoreWithAI(ctx context.Context, deps PipelineDeps, source Source, opts FetchOptions) (int, error) {
-	articles, err := Fetch(ctx, source, deps.Config, opts)
+	articles, state, err := fetchChanged(ctx, deps.Queries, source, deps.Config, opts)
 	if err != nil {
 		return 0, err
 	}
 
-	return StoreArticlesWithAI(ctx, deps, articles, source)
+	stored, err := StoreArticlesWithAI(ctx, deps, articles, source)
+	if err != nil {
+		return stored, err
+	}
+
+	saveFeedState(ctx, deps.Queries, source, state)
+	return stored, nil
+}
+
+// saveFeedState records the feed state after its articles were stored. A
+// failure only costs a full refetch next time, so it is logged, not returned.
+func saveFeedState(ctx context.Context, queries *database.Queries, source Source, state FeedState) {
+	if err := SaveFeedState(ctx, queries, source.URL, state); err != nil {
+		logging.Warn("save_feed_state", fmt.Sprintf("Failed to save feed state for %s: %v", source.Name, err))
+	}
 }
 
 func StoreArticlesWithAI(ctx context.Context, deps PipelineDeps, articles []Article, source Source) (int, error) {
 	stored := 0
 
 	for _, article := range articles {
+		article = canonicalizeArticle(ctx, deps.Queries, deps.Config, source, article)
 		err := retry.Do(ctx, deps.Config.RetryConfig(), func() error {
-			_, err := deps.Queries.GetArticleByUrl(ctx, sql.NullString{
-				String: article.Link,
-				Valid:  true,
-			})
+			_, err := findArticle(ctx, deps.Queries, source, article)
 
 			if err == sql.ErrNoRows {
 				var summary sql.NullString
 				var entities []byte
 				var topics []byte
 				var contentType sql.NullString
-				var storyGroupID sql.NullString
 				var analysisStatus = "unprocessed"
 				var articleContent sql.NullString
-
-				if deps.Scraper != nil && deps.AI != nil {
-					content, scrapeErr := deps.Scraper.ScrapeWithRetry(ctx, article.Link, deps.Config)
+				var scrapeStrategy sql.NullString
+				var analysis *processor.AnalysisResult
+				var promptName, promptVersion sql.NullString
+				calls := &processor.UsageRecorder{}
+				var analysisErr error
+				var jobKind string
+
+				if deps.Jobs != nil {
+					analysisStatus = "pending"
+					articleContent, scrapeStrategy, jobKind = queuedContent(deps, article)
+				} else if deps.Scraper != nil && deps.AI != nil {
+					content, strategy, scrapeErr := scrapeArticle(ctx, deps, article)
 					if scrapeErr != nil {
 						logging.Warn("scrape_article", fmt.Sprintf("Failed to scrape %s: %v", article.Link, scrapeErr))
+						analysisStatus = "pending"
+						analysisErr = scrapeErr
 					} else {
 						// Store the scraped content
 						articleContent = sql.NullString{
 							String: content,
 							Valid:  true,
 						}
+						scrapeStrategy = sql.NullString{
+							String: strategy,
+							Valid:  true,
+						}
 
-						result, aiErr := deps.AI.AnalyzeContentWithRetry(ctx, content, deps.Config)
+						result, prompt, aiErr := analyzeArticle(ctx, deps, source, article, content, calls)
 						if aiErr != nil {
 							logging.Warn("ai_analysis", fmt.Sprintf("Failed to analyze %s: %v", article.Link, aiErr))
 							analysisStatus = "pending"
+							analysisErr = aiErr
 						} else if result != nil {
 							summary = sql.NullString{
 								String: result.Summary,
@@ -242,15 +349,15 @@ func StoreArticlesWithAI(ctx context.Context, deps PipelineDeps, articles []Arti
 								String: result.ContentType,
 								Valid:  true,
 							}
-							storyGroupID = sql.NullString{
-								String: result.StoryGroupID,
-								Valid:  true,
-							}
 							analysisStatus = "completed"
+							analysis = result
+							promptName = nullString(prompt.Name)
+							promptVersion = nullString(prompt.Version)
 						}
 					}
 				}
 
+				attempts, lastError := analysisFailure(analysisErr)
 				params := database.CreateArticleParams{
 					Title: sql.NullString{
 						String: article.Title,
@@ -280,14 +387,32 @@ func StoreArticlesWithAI(ctx context.Context, deps PipelineDeps, articles []Arti
 						String: analysisStatus,
 						Valid:  true,
 					},
-					StoryGroupID: storyGroupID,
-					Content:      articleContent,
+					Content:          articleContent,
+					ScrapeStrategy:   scrapeStrategy,
+					Guid:             nullString(article.GUID),
+					Author:           nullString(article.Byline()),
+					ImageUrl:         nullString(article.ImageURL),
+					CanonicalUrl:     nullString(urlnorm.Key(article.Link)),
+					PromptName:       promptName,
+					PromptVersion:    promptVersion,
+					AnalysisAttempts: attempts,
+					AnalysisError:    lastError,
 				}
 
-				_, err = deps.Queries.CreateArticle(ctx, params)
+				created, err := deps.Queries.CreateArticle(ctx, params)
 				if err != nil {
+					recordUsage(ctx, deps, 0, source, calls)
 					return errs.Wrap("create article with AI", err)
 				}
+				recordUsage(ctx, deps, created.ID, source, calls)
+				storeMetadata(ctx, deps.Queries, created.ID, article)
+				assignStory(ctx, deps.Queries, deps.Config, created)
+				if err := tagArticle(ctx, deps.Queries, created.ID, analysis); err != nil {
+					logging.Warn("tag_article", fmt.Sprintf("Failed to tag %s: %v", article.Link, err))
+				}
+				if jobKind != "" {
+					enqueueJob(ctx, deps, jobKind, created.ID)
+				}
 				stored++
 				return nil
 			} else if err != nil {
@@ -305,10 +430,119 @@ func StoreArticlesWithAI(ctx context.Context, deps PipelineDeps, articles []Arti
 	return stored, nil
 }
 
+// analyzeArticle has deps.AI analyze the content of an article with the
+// prompt configured for its source, in chunks if it is too long for one
+// prompt, and returns the prompt along with the result. The calls made are
+// reported to calls. Once the monthly budget is spent it returns
+// usage.ErrBudgetExceeded without calling the AI, so the article is left
+// pending.
+func analyzeArticle(ctx context.Context, deps PipelineDeps, source Source, article Article, content string, calls *processor.UsageRecorder) (*processor.AnalysisResult, *processor.Prompt, error) {
+	if deps.Usage.OverBudget(ctx) {
+		return nil, nil, usage.ErrBudgetExceeded
+	}
+
+	prompt, err := deps.Prompts.For(deps.Config, source)
+	if err != nil {
+		return nil, nil, errs.Wrap("load prompt", err)
+	}
+
+	result, err := processor.AnalyzeChunked(processor.WithUsageRecorder(ctx, calls), deps.AI, prompt, processor.PromptData{
+		Title:   article.Title,
+		URL:     article.Link,
+		Source:  source.Name,
+		Content: content,
+	}, deps.Config)
+	return result, prompt, err
+}
+
+// analysisFailure returns the attempt count and error to store with an
+// article whose analysis failed with err, if it did. Being over the monthly
+// budget does not count as an attempt.
+func analysisFailure(err error) (int64, sql.NullString) {
+	if err == nil {
+		return 0, sql.NullString{}
+	}
+	attempts := int64(1)
+	if errors.Is(err, usage.ErrBudgetExceeded) {
+		attempts = 0
+	}
+	return attempts, nullString(err.Error())
+}
+
+// recordUsage stores the AI calls made for an article; an articleID of zero
+// means the article could not be stored.
+func recordUsage(ctx context.Context, deps PipelineDeps, articleID int64, source Source, calls *processor.UsageRecorder) {
+	if err := deps.Usage.Record(ctx, articleID, source.Name, calls.Calls()); err != nil {
+		logging.Warn("record_usage", fmt.Sprintf("Failed to record AI usage: %v", err))
+	}
+}
+
+// scrapeArticle returns the Markdown content of an article and the strategy
+// that produced it. When the feed embeds at least FeedContentMinLength
+// characters of content for the item, that content is used without touching
+// the network. Otherwise the configured scraper (usually a chain of Jina
+// Reader and direct extraction) is tried, falling back to whatever content
+// the feed embedded. An error means every strategy failed.
+func scrapeArticle(ctx context.Context, deps PipelineDeps, article Article) (string, string, error) {
+	feedContent, feedErr := feedMarkdown(article)
+	minLength := deps.Config.FeedContentMinLength
+	if feedErr == nil && minLength > 0 && utf8.RuneCountInString(feedContent) >= minLength {
+		logging.Info("scrape_article", fmt.Sprintf("Using feed content for %s", article.Link))
+		return feedContent, scraper.Feed, nil
+	}
+
+	content, strategy, err := scraper.ScrapeWithStrategy(ctx, deps.Scraper, article.Link, deps.Config)
+	if err == nil {
+		return content, strategy, nil
+	}
+
+	if feedErr != nil {
+		return "", "", errors.Join(err, fmt.Errorf("%s: %w", scraper.Feed, feedErr))
+	}
+	logging.Info("scrape_article", fmt.Sprintf("Using feed content for %s after scraping failed: %v", article.Link, err))
+	return feedContent, scraper.Feed, nil
+}
+
+// feedMarkdown converts the content the feed embedded for an article, or
+// its description if there is no content, to Markdown.
+func feedMarkdown(article Article) (string, error) {
+	feedHTML := article.Content
+	if strings.TrimSpace(feedHTML) == "" {
+		feedHTML = article.Description
+	}
+	return scraper.FromFeed(feedHTML, article.Link)
+}
+
+// tagArticle links a stored article to the topics and entities found by AI
+// analysis, so view and search can filter on them exactly. A nil result is a
+// no-op.
+func tagArticle(ctx context.Context, queries *database.Queries, articleID int64, result *processor.AnalysisResult) error {
+	if result == nil {
+		return nil
+	}
+
+	var entities []database.EntityRef
+	for _, name := range result.Entities.Organizations {
+		entities = append(entities, database.EntityRef{Name: name, Kind: database.EntityOrganization})
+	}
+	for _, name := range result.Entities.Products {
+		entities = append(entities, database.EntityRef{Name: name, Kind: database.EntityProduct})
+	}
+	for _, name := range result.Entities.People {
+		entities = append(entities, database.EntityRef{Name: name, Kind: database.EntityPerson})
+	}
+
+	if err := queries.TagArticle(ctx, articleID, result.Topics, entities); err != nil {
+		return errs.Wrap("tag article", err)
+	}
+	return nil
+}
+
 type SourceResult struct {
-	Source Source
-	Added  int
-	Error  error
+	Source    Source
+	Added     int
+	Unchanged bool // The feed had not changed since the last fetch
+	Error     error
 }
 
 func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source Source, opts FetchOptions, progress chan<- tui.DetailedProgressMsg) (int, error) {
@@ -317,7 +551,14 @@ func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source
 		Phase:  tui.PhaseRSSFetch,
 	}
 
-	articles, err := Fetch(ctx, source, deps.Config, opts)
+	articles, state, err := fetchChanged(ctx, deps.Queries, source, deps.Config, opts)
+	if errors.Is(err, ErrFeedUnchanged) {
+		progress <- tui.DetailedProgressMsg{
+			Source: source.Name,
+			Phase:  tui.PhaseUnchanged,
+		}
+		return 0, err
+	}
 	if err != nil {
 		progress <- tui.DetailedProgressMsg{
 			Source: source.Name,
@@ -329,6 +570,7 @@ func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source
 
 	total := len(articles)
 	stored := 0
+	var storeErrs []error
 
 	for i, article := range articles {
 		progress <- tui.DetailedProgressMsg{
@@ -339,30 +581,42 @@ func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source
 			ArticleTitle: article.Title,
 		}
 
-		_, err := deps.Queries.GetArticleByUrl(ctx, sql.NullString{
-			String: article.Link,
-			Valid:  true,
-		})
+		article = canonicalizeArticle(ctx, deps.Queries, deps.Config, source, article)
+		_, err := findArticle(ctx, deps.Queries, source, article)
 
 		if err == sql.ErrNoRows {
 			var summary sql.NullString
 			var entities []byte
 			var topics []byte
 			var contentType sql.NullString
-			var storyGroupID sql.NullString
 			var analysisStatus = "unprocessed"
 			var articleContent sql.NullString
-
-			if deps.Scraper != nil && deps.AI != nil {
-				content, scrapeErr := deps.Scraper.Scrape(article.Link)
+			var scrapeStrategy sql.NullString
+			var analysis *processor.AnalysisResult
+			var promptName, promptVersion sql.NullString
+			calls := &processor.UsageRecorder{}
+			var analysisErr error
+			var jobKind string
+
+			if deps.Jobs != nil {
+				analysisStatus = "pending"
+				articleContent, scrapeStrategy, jobKind = queuedContent(deps, article)
+			} else if deps.Scraper != nil && deps.AI != nil {
+				content, strategy, scrapeErr := scrapeArticle(ctx, deps, article)
 				if scrapeErr != nil {
+					logging.Warn("scrape_article", fmt.Sprintf("Failed to scrape %s: %v", article.Link, scrapeErr))
 					analysisStatus = "pending"
+					analysisErr = scrapeErr
 				} else {
 					// Store the scraped content
 					articleContent = sql.NullString{
 						String: content,
 						Valid:  true,
 					}
+					scrapeStrategy = sql.NullString{
+						String: strategy,
+						Valid:  true,
+					}
 
 					progress <- tui.DetailedProgressMsg{
 						Source:       source.Name,
@@ -372,9 +626,10 @@ func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source
 						ArticleTitle: article.Title,
 					}
 
-					result, aiErr := deps.AI.AnalyzeContent(content)
+					result, prompt, aiErr := analyzeArticle(ctx, deps, source, article, content, calls)
 					if aiErr != nil {
 						analysisStatus = "pending"
+						analysisErr = aiErr
 					} else if result != nil {
 						summary = sql.NullString{
 							String: result.Summary,
@@ -386,15 +641,15 @@ func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source
 							String: result.ContentType,
 							Valid:  true,
 						}
-						storyGroupID = sql.NullString{
-							String: result.StoryGroupID,
-							Valid:  true,
-						}
 						analysisStatus = "completed"
+						analysis = result
+						promptName = nullString(prompt.Name)
+						promptVersion = nullString(prompt.Version)
 					}
 				}
 			}
 
+			attempts, lastError := analysisFailure(analysisErr)
 			params := database.CreateArticleParams{
 				Title: sql.NullString{
 					String: article.Title,
@@ -424,12 +679,34 @@ func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source
 					String: analysisStatus,
 					Valid:  true,
 				},
-				StoryGroupID: storyGroupID,
-				Content:      articleContent,
+				Content:          articleContent,
+				ScrapeStrategy:   scrapeStrategy,
+				Guid:             nullString(article.GUID),
+				Author:           nullString(article.Byline()),
+				ImageUrl:         nullString(article.ImageURL),
+				CanonicalUrl:     nullString(urlnorm.Key(article.Link)),
+				PromptName:       promptName,
+				PromptVersion:    promptVersion,
+				AnalysisAttempts: attempts,
+				AnalysisError:    lastError,
 			}
 
-			_, err = deps.Queries.CreateArticle(ctx, params)
-			if err == nil {
+			created, err := deps.Queries.CreateArticle(ctx, params)
+			if err != nil {
+				recordUsage(ctx, deps, 0, source, calls)
+				err = errs.Wrap("create article with AI", err)
+				logging.Error("store_article_with_ai", err)
+				storeErrs = append(storeErrs, err)
+			} else {
+				recordUsage(ctx, deps, created.ID, source, calls)
+				storeMetadata(ctx, deps.Queries, created.ID, article)
+				assignStory(ctx, deps.Queries, deps.Config, created)
+				if err := tagArticle(ctx, deps.Queries, created.ID, analysis); err != nil {
+					logging.Warn("tag_article", fmt.Sprintf("Failed to tag %s: %v", article.Link, err))
+				}
+				if jobKind != "" {
+					enqueueJob(ctx, deps, jobKind, created.ID)
+				}
 				stored++
 				progress <- tui.DetailedProgressMsg{
 					Source:       source.Name,
@@ -444,12 +721,20 @@ func FetchAndStoreWithAIProgress(ctx context.Context, deps PipelineDeps, source
 		}
 	}
 
+	// Saving the feed state would have the next fetch skip the feed as
+	// unchanged, losing the articles that could not be stored.
+	storeErr := errors.Join(storeErrs...)
+	if storeErr == nil {
+		saveFeedState(ctx, deps.Queries, source, state)
+	}
+
 	progress <- tui.DetailedProgressMsg{
 		Source: source.Name,
 		Phase:  tui.PhaseDone,
+		Error:  storeErr,
 	}
 
-	return stored, nil
+	return stored, storeErr
 }
 
 func ProcessSourcesConcurrently(ctx context.Context, sources []Source, workerCount int, processFunc func(context.Context, Source, FetchOptions, chan<- tui.DetailedProgressMsg) (int, error), opts FetchOptions, progress chan<- tui.DetailedProgressMsg) []SourceResult {
@@ -464,10 +749,15 @@ func ProcessSourcesConcurrently(ctx context.Context, sources []Source, workerCou
 			for idx := range sourceCh {
 				source := sources[idx]
 				added, err := processFunc(ctx, source, opts, progress)
+				unchanged := errors.Is(err, ErrFeedUnchanged)
+				if unchanged {
+					err = nil
+				}
 				results[idx] = SourceResult{
-					Source: source,
-					Added:  added,
-					Error:  err,
+					Source:    source,
+					Added:     added,
+					Unchanged: unchanged,
+					Error:     err,
 				}
 			}
 		}()
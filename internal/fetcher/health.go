@@ -0,0 +1,147 @@
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// QuarantineError is returned instead of fetching a source that failed too
+// many times in a row, until its cool-down has passed.
+type QuarantineError struct {
+	Failures int
+	Until    time.Time
+}
+
+func (e *QuarantineError) Error() string {
+	return fmt.Sprintf("quarantined after %d consecutive failures until %s",
+		e.Failures, e.Until.Local().Format("2006-01-02 15:04"))
+}
+
+// SourceHealth summarizes the recorded fetch history of a source. Times are
+// zero when the event never happened.
+type SourceHealth struct {
+	Source              Source
+	LastSuccess         time.Time
+	LastError           string
+	LastErrorAt         time.Time
+	ConsecutiveFailures int
+	Fetches             int
+	AverageLatency      time.Duration
+	ItemsSeen           int
+	QuarantinedUntil    time.Time
+}
+
+// Quarantined reports whether the source is being skipped at the given time.
+func (h SourceHealth) Quarantined(now time.Time) bool {
+	return now.Before(h.QuarantinedUntil)
+}
+
+func healthFromFeed(source Source, feed database.Feed) SourceHealth {
+	h := SourceHealth{
+		Source:              source,
+		LastSuccess:         feed.LastSuccessAt.Time,
+		LastError:           feed.LastError.String,
+		LastErrorAt:         feed.LastErrorAt.Time,
+		ConsecutiveFailures: int(feed.ConsecutiveFailures),
+		Fetches:             int(feed.FetchCount),
+		ItemsSeen:           int(feed.ItemsSeen),
+		QuarantinedUntil:    feed.QuarantinedUntil.Time,
+	}
+	if feed.FetchCount > 0 {
+		h.AverageLatency = time.Duration(feed.TotalLatencyMs/feed.FetchCount) * time.Millisecond
+	}
+	return h
+}
+
+// ListSourceHealth returns the health of each source, in the given order.
+// Sources that were never fetched have an empty history.
+func ListSourceHealth(ctx context.Context, queries *database.Queries, sources []Source) ([]SourceHealth, error) {
+	feeds, err := queries.ListFeeds(ctx)
+	if err != nil {
+		return nil, errs.Wrap("list source health", err)
+	}
+
+	byURL := make(map[string]database.Feed, len(feeds))
+	for _, feed := range feeds {
+		byURL[feed.SourceUrl] = feed
+	}
+
+	health := make([]SourceHealth, 0, len(sources))
+	for _, source := range sources {
+		health = append(health, healthFromFeed(source, byURL[source.URL]))
+	}
+	return health, nil
+}
+
+// checkQuarantine returns a *QuarantineError if source is still cooling down
+// after repeated failures.
+func checkQuarantine(ctx context.Context, queries *database.Queries, source Source) error {
+	feed, err := queries.GetFeed(ctx, source.URL)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return errs.Wrap("check source health", err)
+	}
+
+	h := healthFromFeed(source, feed)
+	if h.Quarantined(time.Now()) {
+		return &QuarantineError{Failures: h.ConsecutiveFailures, Until: h.QuarantinedUntil}
+	}
+	return nil
+}
+
+// recordFetchSuccess resets the failure streak of a source and adds to its
+// latency and item totals. Health bookkeeping never fails a fetch, so errors
+// are only logged.
+func recordFetchSuccess(ctx context.Context, queries *database.Queries, source Source, latency time.Duration, items int) {
+	err := queries.RecordFeedSuccess(ctx, database.RecordFeedSuccessParams{
+		SourceUrl:      source.URL,
+		LastSuccessAt:  sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		TotalLatencyMs: latency.Milliseconds(),
+		ItemsSeen:      int64(items),
+	})
+	if err != nil {
+		logging.Warn("record_source_health", fmt.Sprintf("Failed to record success for %s: %v", source.Name, err))
+	}
+}
+
+// recordFetchFailure extends the failure streak of a source and quarantines
+// it for the configured cool-down once the streak reaches the threshold.
+func recordFetchFailure(ctx context.Context, queries *database.Queries, source Source, cfg *config.Config, latency time.Duration, fetchErr error) {
+	now := time.Now().UTC()
+	failures, err := queries.RecordFeedFailure(ctx, database.RecordFeedFailureParams{
+		SourceUrl:      source.URL,
+		LastError:      sql.NullString{String: fetchErr.Error(), Valid: true},
+		LastErrorAt:    sql.NullTime{Time: now, Valid: true},
+		TotalLatencyMs: latency.Milliseconds(),
+	})
+	if err != nil {
+		logging.Warn("record_source_health", fmt.Sprintf("Failed to record failure for %s: %v", source.Name, err))
+		return
+	}
+
+	threshold := cfg.Health.FailureThreshold
+	if threshold <= 0 || failures < int64(threshold) {
+		return
+	}
+
+	until := now.Add(cfg.Health.Cooldown)
+	err = queries.QuarantineFeed(ctx, database.QuarantineFeedParams{
+		QuarantinedUntil: sql.NullTime{Time: until, Valid: true},
+		SourceUrl:        source.URL,
+	})
+	if err != nil {
+		logging.Warn("record_source_health", fmt.Sprintf("Failed to quarantine %s: %v", source.Name, err))
+		return
+	}
+	logging.Warn("source_quarantined", fmt.Sprintf("%s failed %d times in a row; skipping until %s",
+		source.Name, failures, until.Local().Format(time.RFC3339)))
+}
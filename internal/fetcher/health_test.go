@@ -0,0 +1,107 @@
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func setupHealthDB(t *testing.T) *database.Queries {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, database.InitSchema(db))
+	return database.New(db)
+}
+
+func TestFetchAndStore_QuarantinesFailingSource(t *testing.T) {
+	var requests atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(cachedFeed))
+	}))
+	defer server.Close()
+
+	queries := setupHealthDB(t)
+	cfg := testutil.TestConfig()
+	cfg.Health.FailureThreshold = 2
+	cfg.Health.Cooldown = time.Hour
+	source := Source{Name: "Flaky", URL: server.URL, Type: "rss"}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := FetchAndStore(ctx, queries, source, cfg, FetchOptions{})
+		require.Error(t, err)
+	}
+	assert.Equal(t, int32(2), requests.Load())
+
+	_, err := FetchAndStore(ctx, queries, source, cfg, FetchOptions{})
+	var quarantined *QuarantineError
+	require.True(t, errors.As(err, &quarantined), "got %v", err)
+	assert.Equal(t, 2, quarantined.Failures)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), quarantined.Until, time.Minute)
+	assert.Equal(t, int32(2), requests.Load(), "quarantined sources are not requested")
+
+	healthy.Store(true)
+	stored, err := FetchAndStore(ctx, queries, source, cfg, FetchOptions{Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+
+	health, err := ListSourceHealth(ctx, queries, []Source{source})
+	require.NoError(t, err)
+	require.Len(t, health, 1)
+	h := health[0]
+	assert.Equal(t, 0, h.ConsecutiveFailures)
+	assert.False(t, h.Quarantined(time.Now()), "a success lifts the quarantine")
+	assert.Equal(t, 3, h.Fetches)
+	assert.Equal(t, 1, h.ItemsSeen)
+	assert.False(t, h.LastSuccess.IsZero())
+	assert.Contains(t, h.LastError, "404")
+	assert.False(t, h.LastErrorAt.IsZero())
+}
+
+func TestListSourceHealth_NeverFetched(t *testing.T) {
+	queries := setupHealthDB(t)
+
+	health, err := ListSourceHealth(context.Background(), queries, []Source{{Name: "New", URL: "https://example.com/new.xml"}})
+	require.NoError(t, err)
+	require.Len(t, health, 1)
+	assert.Equal(t, "New", health[0].Source.Name)
+	assert.Zero(t, health[0].Fetches)
+	assert.True(t, health[0].LastSuccess.IsZero())
+	assert.Zero(t, health[0].AverageLatency)
+}
+
+func TestRecordFetchFailure_ThresholdDisabled(t *testing.T) {
+	queries := setupHealthDB(t)
+	cfg := testutil.TestConfig()
+	source := Source{Name: "Down", URL: "https://example.com/down.xml"}
+	ctx := context.Background()
+
+	for i := 0; i < 10; i++ {
+		recordFetchFailure(ctx, queries, source, cfg, 10*time.Millisecond, errors.New("boom"))
+	}
+
+	assert.NoError(t, checkQuarantine(ctx, queries, source))
+
+	health, err := ListSourceHealth(ctx, queries, []Source{source})
+	require.NoError(t, err)
+	assert.Equal(t, 10, health[0].ConsecutiveFailures)
+	assert.Equal(t, 10*time.Millisecond, health[0].AverageLatency)
+}
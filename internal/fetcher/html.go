@@ -0,0 +1,131 @@
+package fetcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// htmlAdapter scrapes articles from an HTML listing page using the CSS
+// selectors in Source.HTML. Relative links are resolved against the page's
+// <base href> or, failing that, the source URL.
+type htmlAdapter struct{}
+
+// defaultHTMLDateLayouts are tried when a source configures no date layouts.
+var defaultHTMLDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"01/02/2006",
+}
+
+func (htmlAdapter) Parse(body []byte, source Source) ([]Article, error) {
+	selectors := source.HTML
+	if strings.TrimSpace(selectors.Item) == "" {
+		return nil, errors.New("html source has no item selector (html.item)")
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parse html: %w", err)
+	}
+
+	base, err := url.Parse(source.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse source url: %w", err)
+	}
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if ref, err := base.Parse(strings.TrimSpace(href)); err == nil {
+			base = ref
+		}
+	}
+
+	layouts := selectors.DateLayouts
+	if len(layouts) == 0 {
+		layouts = defaultHTMLDateLayouts
+	}
+
+	var articles []Article
+	seen := make(map[string]bool)
+	doc.Find(selectors.Item).Each(func(_ int, item *goquery.Selection) {
+		link := htmlLink(item, selectors.Link)
+		href, ok := link.Attr("href")
+		href = strings.TrimSpace(href)
+		if !ok || href == "" || strings.HasPrefix(href, "#") {
+			return
+		}
+		ref, err := base.Parse(href)
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+			return
+		}
+		if seen[ref.String()] {
+			return
+		}
+		seen[ref.String()] = true
+
+		title := link.Text()
+		if selectors.Title != "" {
+			title = item.Find(selectors.Title).First().Text()
+		}
+		title = strings.Join(strings.Fields(title), " ")
+		if title == "" {
+			title = ref.String()
+		}
+
+		article := Article{Title: title, Link: ref.String()}
+		if selectors.Date != "" {
+			article.PublishedDate = htmlDate(item.Find(selectors.Date).First(), layouts)
+		}
+		articles = append(articles, article)
+	})
+
+	if len(articles) == 0 {
+		return nil, fmt.Errorf("no articles matched item selector %q", selectors.Item)
+	}
+	return articles, nil
+}
+
+// htmlLink returns the element holding an item's link.
+func htmlLink(item *goquery.Selection, selector string) *goquery.Selection {
+	if selector != "" {
+		return item.Find(selector).First()
+	}
+	if goquery.NodeName(item) == "a" {
+		return item
+	}
+	return item.Find("a[href]").First()
+}
+
+// htmlDate parses an element's datetime attribute, or its text, with the
+// first matching layout. It returns the zero time if nothing parses.
+func htmlDate(sel *goquery.Selection, layouts []string) time.Time {
+	if sel.Length() == 0 {
+		return time.Time{}
+	}
+
+	candidates := []string{strings.Join(strings.Fields(sel.Text()), " ")}
+	if attr, ok := sel.Attr("datetime"); ok {
+		candidates = append([]string{strings.TrimSpace(attr)}, candidates...)
+	}
+
+	for _, value := range candidates {
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
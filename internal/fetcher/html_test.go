@@ -0,0 +1,134 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const blogListingPage = `<!DOCTYPE html>
+<html>
+<head><title>Lab Blog</title></head>
+<body>
+  <nav><a href="/about">About</a></nav>
+  <main>
+    <article class="post">
+      <h2 class="post-title"><a href="/posts/first-post">First   Post</a></h2>
+      <time datetime="2025-08-10T09:00:00Z">August 10</time>
+    </article>
+    <article class="post">
+      <h2 class="post-title"><a href="second-post">Second Post</a></h2>
+      <span class="date">12/08/2025</span>
+    </article>
+    <article class="post">
+      <h2 class="post-title"><a href="https://other.example/third">Third Post</a></h2>
+      <span class="date">not a date</span>
+    </article>
+    <article class="post">
+      <h2 class="post-title"><a href="#comments">Anchor only</a></h2>
+    </article>
+    <article class="post">
+      <h2 class="post-title"><a href="/posts/first-post">First Post (duplicate)</a></h2>
+    </article>
+  </main>
+</body>
+</html>`
+
+const newsListingPage = `<html>
+<head><base href="https://cdn.example/news/"></head>
+<body>
+  <ul>
+    <li><a class="headline" href="story-1.html">Story One</a> <em>Jan 2, 2025</em></li>
+    <li><a class="headline" href="story-2.html">Story Two</a> <em>Jan 3, 2025</em></li>
+  </ul>
+</body>
+</html>`
+
+func serveHTML(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(page))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetch_HTMLSource(t *testing.T) {
+	server := serveHTML(t, map[string]string{"/blog/": blogListingPage})
+	source := Source{
+		Name: "Lab Blog",
+		URL:  server.URL + "/blog/",
+		Type: "html",
+		HTML: config.HTMLSelectors{
+			Item:        "article.post",
+			Title:       ".post-title",
+			Link:        ".post-title a",
+			Date:        "time, .date",
+			DateLayouts: []string{time.RFC3339, "02/01/2006"},
+		},
+	}
+
+	articles, err := Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 3)
+
+	assert.Equal(t, "Second Post", articles[0].Title)
+	assert.Equal(t, server.URL+"/blog/second-post", articles[0].Link)
+	assert.True(t, articles[0].PublishedDate.Equal(time.Date(2025, 8, 12, 0, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, "First Post", articles[1].Title)
+	assert.Equal(t, server.URL+"/posts/first-post", articles[1].Link)
+	assert.True(t, articles[1].PublishedDate.Equal(time.Date(2025, 8, 10, 9, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, "Third Post", articles[2].Title)
+	assert.Equal(t, "https://other.example/third", articles[2].Link)
+	assert.False(t, articles[2].PublishedDate.IsZero(), "unparseable dates fall back to the fetch time")
+}
+
+func TestFetch_HTMLSourceDefaultsAndBaseHref(t *testing.T) {
+	server := serveHTML(t, map[string]string{"/news": newsListingPage})
+	source := Source{
+		Name: "News",
+		URL:  server.URL + "/news",
+		Type: "html",
+		HTML: config.HTMLSelectors{Item: "a.headline"},
+	}
+
+	articles, err := Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 2)
+	assert.Equal(t, "Story One", articles[0].Title)
+	assert.Equal(t, "https://cdn.example/news/story-1.html", articles[0].Link)
+
+	source.HTML = config.HTMLSelectors{Item: "li", Date: "em"}
+	articles, err = Fetch(context.Background(), source, testutil.TestConfig(), FetchOptions{})
+	require.NoError(t, err)
+	require.Len(t, articles, 2)
+	assert.Equal(t, "Story Two", articles[0].Title)
+	assert.True(t, articles[0].PublishedDate.Equal(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestFetch_HTMLSourceErrors(t *testing.T) {
+	server := serveHTML(t, map[string]string{"/news": newsListingPage})
+	cfg := testutil.TestConfig()
+
+	_, err := Fetch(context.Background(), Source{Name: "No selectors", URL: server.URL + "/news", Type: "html"}, cfg, FetchOptions{})
+	assert.ErrorContains(t, err, "html.item")
+
+	source := Source{Name: "Wrong selector", URL: server.URL + "/news", Type: "html", HTML: config.HTMLSelectors{Item: "article"}}
+	_, err = Fetch(context.Background(), source, cfg, FetchOptions{})
+	assert.ErrorContains(t, err, "no articles matched")
+}
@@ -0,0 +1,194 @@
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/jobs"
+	"github.com/robertguss/rss-agent-cli/internal/scraper"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// Kinds of the jobs queued for new articles when PipelineDeps.Jobs is set.
+// A scrape job fetches the article page and queues an analyze job, which
+// has the article analyzed.
+const (
+	JobScrape  = "scrape"
+	JobAnalyze = "analyze"
+)
+
+// queuedContent returns the content to store with a new article whose work
+// is queued, with the strategy that produced it, and the kind of job to
+// queue. Feed content long enough to use without scraping, as configured by
+// FeedContentMinLength, goes straight to analysis; shorter feed content is
+// stored as a fallback for when scraping fails.
+func queuedContent(deps PipelineDeps, article Article) (sql.NullString, sql.NullString, string) {
+	content, err := feedMarkdown(article)
+	if err != nil || strings.TrimSpace(content) == "" {
+		return sql.NullString{}, sql.NullString{}, JobScrape
+	}
+	kind := JobScrape
+	if minLength := deps.Config.FeedContentMinLength; minLength > 0 && utf8.RuneCountInString(content) >= minLength {
+		kind = JobAnalyze
+	}
+	return nullString(content), nullString(scraper.Feed), kind
+}
+
+// enqueueJob queues a job for a stored article. An article whose job could
+// not be queued is left pending for the process command, so the failure is
+// logged, not returned.
+func enqueueJob(ctx context.Context, deps PipelineDeps, kind string, articleID int64) {
+	if _, err := deps.Jobs.Enqueue(ctx, kind, articleID); err != nil {
+		logging.Warn("enqueue_job", fmt.Sprintf("Failed to queue %s job for article %d: %v", kind, articleID, err))
+	}
+}
+
+// JobRunner runs the scrape and analyze jobs queued by fetch. Each job runs
+// with Deps and the scraper configured for the source of its article, unless
+// Deps.Scraper is set to one for all sources. Deps.Jobs receives the analyze
+// jobs that scrape jobs queue.
+type JobRunner struct {
+	Deps        PipelineDeps
+	MaxAttempts int // failures after which an article is marked failed; zero or less means no limit
+
+	// Scrapers holds the scrapers already created, by config name. Those
+	// missing are created and added when a job first needs them.
+	Scrapers map[string]scraper.Scraper
+
+	// OnStart, if not nil, is called with the kind of job and its article
+	// before the work starts, from the goroutine that does it.
+	OnStart func(kind string, article database.Article)
+
+	mu sync.Mutex
+}
+
+// Handlers returns the handlers of the job kinds the runner runs, for a
+// jobs.Worker.
+func (r *JobRunner) Handlers() map[string]jobs.Handler {
+	return map[string]jobs.Handler{
+		JobScrape:  r.Scrape,
+		JobAnalyze: r.Analyze,
+	}
+}
+
+// Scrape stores the scraped page of the job's article and queues its
+// analysis. If scraping fails, the content the feed embedded is analyzed
+// instead; without any, the failure is recorded with the article as by
+// ProcessArticle.
+func (r *JobRunner) Scrape(ctx context.Context, job jobs.Job) error {
+	stored, deps, source, ok, err := r.prepare(ctx, job)
+	if !ok {
+		return err
+	}
+
+	content, strategy, err := scraper.ScrapeWithStrategy(ctx, deps.Scraper, stored.Url.String, deps.Config)
+	if err != nil && strings.TrimSpace(stored.Content.String) == "" {
+		return jobError(recordFailure(ctx, deps, ProcessResult{Article: stored}, err, r.MaxAttempts))
+	}
+	if err != nil {
+		logging.Info("scrape_article", fmt.Sprintf("Using feed content for %s after scraping failed: %v", stored.Url.String, err))
+	} else if err := storeContent(ctx, deps, stored, content, strategy); err != nil {
+		return err
+	}
+
+	if _, err := deps.Jobs.Enqueue(ctx, JobAnalyze, stored.ID); err != nil {
+		return err
+	}
+	logging.Info("job_scrape", fmt.Sprintf("Scraped %s for %s", stored.Url.String, source.Name))
+	return nil
+}
+
+// Analyze has the job's article analyzed with ProcessArticle. Running over
+// the monthly budget postpones the job; see jobError.
+func (r *JobRunner) Analyze(ctx context.Context, job jobs.Job) error {
+	stored, deps, source, ok, err := r.prepare(ctx, job)
+	if !ok {
+		return err
+	}
+	return jobError(ProcessArticle(ctx, deps, source, stored, r.MaxAttempts))
+}
+
+// prepare loads the article of a job, with the dependencies and source to
+// work on it with. It reports false if there is nothing to do, because the
+// article was deleted or analyzed in the meantime, or loading failed.
+func (r *JobRunner) prepare(ctx context.Context, job jobs.Job) (database.Article, PipelineDeps, Source, bool, error) {
+	deps := r.Deps
+	stored, err := deps.Queries.GetArticle(ctx, job.ArticleID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return stored, deps, Source{}, false, nil
+	} else if err != nil {
+		return stored, deps, Source{}, false, errs.Wrap("get article", err)
+	}
+	if stored.AnalysisStatus.String == "completed" {
+		return stored, deps, Source{}, false, nil
+	}
+
+	source := deps.Config.SourceNamed(stored.SourceName.String)
+	deps.Scraper, err = r.scraperFor(source)
+	if err != nil {
+		return stored, deps, source, false, jobs.Permanent(err)
+	}
+	if r.OnStart != nil {
+		r.OnStart(job.Kind, stored)
+	}
+	return stored, deps, source, true, nil
+}
+
+// scraperFor returns the scraper configured for source, creating it the
+// first time it is needed.
+func (r *JobRunner) scraperFor(source Source) (scraper.Scraper, error) {
+	if r.Deps.Scraper != nil {
+		return r.Deps.Scraper, nil
+	}
+	name := r.Deps.Config.ScraperFor(source)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.Scrapers[name]; ok {
+		return s, nil
+	}
+	s, err := scraper.New(name)
+	if err != nil {
+		return nil, fmt.Errorf("source %s: %w", source.Name, err)
+	}
+	if r.Scrapers == nil {
+		r.Scrapers = make(map[string]scraper.Scraper)
+	}
+	r.Scrapers[name] = s
+	return s, nil
+}
+
+// budgetRecheck is how long a job postponed by the monthly budget waits at
+// most before the budget is read again, in case it was raised or other
+// processes' spending was overcounted.
+const budgetRecheck = time.Hour
+
+// jobError turns the outcome of processing an article into the error that
+// decides what becomes of its job. A job over budget is postponed to the
+// start of next month or until budgetRecheck has passed, whichever comes
+// first; the budget is read afresh when it runs again.
+func jobError(result ProcessResult) error {
+	switch {
+	case result.Error == nil:
+		return nil
+	case errors.Is(result.Error, usage.ErrBudgetExceeded):
+		now := time.Now()
+		until := usage.MonthStart(now).AddDate(0, 1, 0)
+		if recheck := now.Add(budgetRecheck); recheck.Before(until) {
+			until = recheck
+		}
+		return jobs.PostponeUntil(result.Error, until)
+	case result.GaveUp:
+		return jobs.Permanent(result.Error)
+	}
+	return result.Error
+}
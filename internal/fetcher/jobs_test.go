@@ -0,0 +1,187 @@
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor/mocks"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/jobs"
+	"github.com/robertguss/rss-agent-cli/internal/scraper"
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJobsDB(t *testing.T) *database.Queries {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, database.InitSchema(db))
+	return database.New(db)
+}
+
+func TestStoreArticlesWithAI_QueuesJobs(t *testing.T) {
+	queries := setupJobsDB(t)
+	ctx := context.Background()
+
+	mockAI := new(mocks.AIProcessor)
+	cfg := testutil.TestConfig()
+	cfg.FeedContentMinLength = 40
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("scraped", nil),
+		AI:      mockAI,
+		Queries: queries,
+		Config:  cfg,
+		Jobs:    jobs.NewQueue(queries),
+	}
+
+	articles := []Article{
+		{Title: "Bare", Link: "https://example.com/bare", PublishedDate: time.Now()},
+		{Title: "Full", Link: "https://example.com/full", PublishedDate: time.Now(),
+			Content: "<p>The whole article was embedded in the feed item itself.</p>"},
+	}
+	stored, err := StoreArticlesWithAI(ctx, deps, articles, Source{Name: "Blog"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored)
+
+	bare, err := queries.GetArticleByUrl(ctx, nullString("https://example.com/bare"))
+	require.NoError(t, err)
+	assert.Equal(t, "pending", bare.AnalysisStatus.String)
+	assert.False(t, bare.Content.Valid)
+
+	full, err := queries.GetArticleByUrl(ctx, nullString("https://example.com/full"))
+	require.NoError(t, err)
+	assert.Equal(t, scraper.Feed, full.ScrapeStrategy.String)
+
+	counts, err := deps.Jobs.Counts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []database.CountJobsRow{
+		{Kind: JobAnalyze, State: jobs.StateQueued, Count: 1},
+		{Kind: JobScrape, State: jobs.StateQueued, Count: 1},
+	}, counts)
+	mockAI.AssertNotCalled(t, "AnalyzeContentWithRetry", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJobRunner_ScrapesThenAnalyzes(t *testing.T) {
+	queries := setupJobsDB(t)
+	ctx := context.Background()
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "scraped page", mock.Anything).Return(&processor.AnalysisResult{Summary: "Queued summary"}, nil)
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("scraped page", nil),
+		AI:      mockAI,
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+		Jobs:    jobs.NewQueue(queries),
+	}
+
+	articles := []Article{{Title: "Queued", Link: "https://example.com/queued", PublishedDate: time.Now()}}
+	_, err := StoreArticlesWithAI(ctx, deps, articles, Source{Name: "Blog"})
+	require.NoError(t, err)
+
+	runner := &JobRunner{Deps: deps, MaxAttempts: 3}
+	worker := &jobs.Worker{Queue: deps.Jobs, Owner: "test", Handlers: runner.Handlers(), Lease: time.Minute, MaxAttempts: 3}
+	var results []jobs.Result
+	require.NoError(t, worker.Run(ctx, 1, 0, func(result jobs.Result) { results = append(results, result) }))
+
+	require.Len(t, results, 2)
+	assert.Equal(t, JobScrape, results[0].Job.Kind)
+	assert.Equal(t, JobAnalyze, results[1].Job.Kind)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+	}
+
+	article, err := queries.GetArticleByUrl(ctx, nullString("https://example.com/queued"))
+	require.NoError(t, err)
+	assert.Equal(t, "completed", article.AnalysisStatus.String)
+	assert.Equal(t, "Queued summary", article.Summary.String)
+	assert.Equal(t, "scraped page", article.Content.String)
+
+	counts, err := deps.Jobs.Counts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestJobRunner_ScrapeFailure(t *testing.T) {
+	queries := setupJobsDB(t)
+	ctx := context.Background()
+
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("", assert.AnError),
+		AI:      new(mocks.AIProcessor),
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+		Jobs:    jobs.NewQueue(queries),
+	}
+
+	articles := []Article{
+		{Title: "Bare", Link: "https://example.com/bare", PublishedDate: time.Now()},
+		{Title: "Teaser", Link: "https://example.com/teaser", PublishedDate: time.Now(), Description: "<p>A short teaser.</p>"},
+	}
+	_, err := StoreArticlesWithAI(ctx, deps, articles, Source{Name: "Blog"})
+	require.NoError(t, err)
+
+	runner := &JobRunner{Deps: deps, MaxAttempts: 1}
+	for _, url := range []string{"https://example.com/bare", "https://example.com/teaser"} {
+		article, err := queries.GetArticleByUrl(ctx, nullString(url))
+		require.NoError(t, err)
+		err = runner.Scrape(ctx, jobs.Job{Kind: JobScrape, ArticleID: article.ID})
+
+		if url == "https://example.com/bare" {
+			assert.ErrorIs(t, err, assert.AnError)
+			article, err = queries.GetArticle(ctx, article.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "failed", article.AnalysisStatus.String)
+			assert.Equal(t, int64(1), article.AnalysisAttempts)
+		} else {
+			assert.NoError(t, err, "the teaser from the feed is analyzed instead")
+		}
+	}
+
+	counts, err := deps.Jobs.Counts(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, counts, database.CountJobsRow{Kind: JobAnalyze, State: jobs.StateQueued, Count: 1})
+}
+
+func TestJobRunner_ScrapeRegroupsStory(t *testing.T) {
+	queries := setupJobsDB(t)
+	ctx := context.Background()
+
+	page := strings.Repeat("The city council approved the new riverside park plan on Tuesday after months of debate over funding, "+
+		"traffic and the fate of the old warehouse district that the park will replace. ", 3)
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper(page, nil),
+		AI:      new(mocks.AIProcessor),
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+		Jobs:    jobs.NewQueue(queries),
+	}
+
+	runner := &JobRunner{Deps: deps, MaxAttempts: 3}
+	var groups []string
+	for _, article := range []Article{
+		{Title: "Council backs riverside park", Link: "https://a.example.com/park", PublishedDate: time.Now()},
+		{Title: "Warehouse district to make way for greenery", Link: "https://b.example.com/park", PublishedDate: time.Now()},
+	} {
+		_, err := StoreArticlesWithAI(ctx, deps, []Article{article}, Source{Name: article.Link})
+		require.NoError(t, err)
+		stored, err := queries.GetArticleByUrl(ctx, nullString(article.Link))
+		require.NoError(t, err)
+		require.NoError(t, runner.Scrape(ctx, jobs.Job{Kind: JobScrape, ArticleID: stored.ID}))
+
+		stored, err = queries.GetArticle(ctx, stored.ID)
+		require.NoError(t, err)
+		groups = append(groups, stored.StoryGroupID.String)
+	}
+
+	assert.NotEmpty(t, groups[0])
+	assert.Equal(t, groups[0], groups[1], "near-duplicates are grouped by their scraped content, not just their titles")
+}
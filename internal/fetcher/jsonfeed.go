@@ -0,0 +1,164 @@
+package fetcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// jsonFeedAdapter handles JSON Feed 1.0 and 1.1 documents
+// (https://www.jsonfeed.org/version/1.1/).
+type jsonFeedAdapter struct{}
+
+type jsonFeed struct {
+	Version string           `json:"version"`
+	Title   string           `json:"title"`
+	Authors []jsonFeedAuthor `json:"authors"`
+	Author  *jsonFeedAuthor  `json:"author"`
+	Items   []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            json.RawMessage      `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	Summary       string               `json:"summary"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Image         string               `json:"image"`
+	BannerImage   string               `json:"banner_image"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []jsonFeedAuthor     `json:"authors"`
+	Author        *jsonFeedAuthor      `json:"author"` // JSON Feed 1.0
+	Tags          []string             `json:"tags"`
+	Attachments   []jsonFeedAttachment `json:"attachments"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+func (jsonFeedAdapter) Parse(body []byte, source Source) ([]Article, error) {
+	var feed jsonFeed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("parse json feed: %w", err)
+	}
+	if !strings.HasPrefix(feed.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("parse json feed: unrecognized version %q", feed.Version)
+	}
+
+	articles := make([]Article, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		id := jsonFeedID(item.ID)
+		link := firstNonBlank(item.URL, item.ExternalURL)
+		if link == "" && isHTTPURL(id) {
+			link = id
+		}
+		if link == "" {
+			continue
+		}
+
+		article := Article{
+			Title:       firstNonBlank(item.Title, firstLine(item.Summary), firstLine(item.ContentText), link),
+			Link:        link,
+			Content:     firstNonBlank(item.ContentHTML, textToHTML(item.ContentText)),
+			Description: html.EscapeString(item.Summary),
+			GUID:        id,
+			Authors:     jsonFeedAuthors(item.Authors, item.Author),
+			Categories:  item.Tags,
+			ImageURL:    firstNonBlank(item.Image, item.BannerImage),
+		}
+		if len(article.Authors) == 0 {
+			// Items without authors inherit the feed's.
+			article.Authors = jsonFeedAuthors(feed.Authors, feed.Author)
+		}
+		for _, attachment := range item.Attachments {
+			article.Enclosures = append(article.Enclosures, Enclosure{
+				URL:    attachment.URL,
+				Type:   attachment.MimeType,
+				Length: attachment.SizeInBytes,
+			})
+		}
+		for _, date := range []string{item.DatePublished, item.DateModified} {
+			if t, err := time.Parse(time.RFC3339, date); err == nil {
+				article.PublishedDate = t
+				break
+			}
+		}
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+// jsonFeedID decodes an item id. The spec requires a string, but some feeds
+// publish numbers, which are kept in their JSON form.
+func jsonFeedID(raw json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return strings.TrimSpace(id)
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// jsonFeedAuthors returns author names from a 1.1 authors list, or from a
+// 1.0 author object if the list is empty.
+func jsonFeedAuthors(authors []jsonFeedAuthor, author *jsonFeedAuthor) []string {
+	if len(authors) == 0 && author != nil {
+		authors = []jsonFeedAuthor{*author}
+	}
+
+	var names []string
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func firstNonBlank(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// firstLine returns the first line of s, cut to a headline-sized length.
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if runes := []rune(s); len(runes) > 120 {
+		s = string(runes[:119]) + "…"
+	}
+	return s
+}
+
+// textToHTML wraps each blank-line separated paragraph of plain text in <p>.
+func textToHTML(text string) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			b.WriteString("<p>" + html.EscapeString(paragraph) + "</p>")
+		}
+	}
+	return b.String()
+}
+
+func isHTTPURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
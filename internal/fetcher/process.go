@@ -0,0 +1,221 @@
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/usage"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+)
+
+// ErrNoScraper is returned for an article without content when there is no
+// scraper to fetch it with.
+var ErrNoScraper = errors.New("article has no content and no scraper is configured")
+
+// BacklogOptions selects the articles Backlog returns.
+type BacklogOptions struct {
+	ArticleID   int64  // only this article, whatever its analysis status
+	Source      string // only articles of this source
+	MaxAttempts int    // skip articles that failed this many times; 0 or less means no limit
+}
+
+// Backlog returns the articles awaiting analysis: those left pending or
+// failed by a scrape or AI call and still under the attempt limit, then
+// those stored without analysis. Articles with a job queued or running are
+// left to the workers, so they are not analyzed and paid for twice.
+func Backlog(ctx context.Context, queries *database.Queries, opts BacklogOptions) ([]database.Article, error) {
+	activeIDs, err := queries.ListActiveJobArticleIDs(ctx)
+	if err != nil {
+		return nil, errs.Wrap("list active jobs", err)
+	}
+	queued := make(map[int64]bool, len(activeIDs))
+	for _, id := range activeIDs {
+		queued[id] = true
+	}
+
+	if opts.ArticleID != 0 {
+		article, err := queries.GetArticle(ctx, opts.ArticleID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("article %d not found", opts.ArticleID)
+		} else if err != nil {
+			return nil, errs.Wrap("get article", err)
+		}
+		if queued[article.ID] {
+			return nil, fmt.Errorf("article %d is queued for the worker command", opts.ArticleID)
+		}
+		return []database.Article{article}, nil
+	}
+
+	maxAttempts := int64(math.MaxInt64)
+	if opts.MaxAttempts > 0 {
+		maxAttempts = int64(opts.MaxAttempts)
+	}
+	articles, err := queries.ListBacklogArticles(ctx, maxAttempts)
+	if err != nil {
+		return nil, errs.Wrap("list backlog articles", err)
+	}
+
+	var backlog []database.Article
+	for _, article := range articles {
+		if opts.Source != "" && article.SourceName.String != opts.Source {
+			continue
+		}
+		if queued[article.ID] {
+			continue
+		}
+		backlog = append(backlog, article)
+	}
+	return backlog, nil
+}
+
+// ProcessResult is the outcome of processing one stored article.
+type ProcessResult struct {
+	Article  database.Article
+	Analyzed bool
+	GaveUp   bool // the attempt limit was reached and the article marked failed
+	Error    error
+}
+
+// ProcessArticle analyzes a stored article from the backlog, scraping its
+// page first if it has no content. On success the analysis is stored and
+// the article completed. On failure the attempt count and error are stored
+// and the article stays pending, or is marked failed once it has failed
+// maxAttempts times (no limit if maxAttempts is zero or less). Running over
+// the monthly budget leaves the article as it was.
+func ProcessArticle(ctx context.Context, deps PipelineDeps, source Source, stored database.Article, maxAttempts int) ProcessResult {
+	result := ProcessResult{Article: stored}
+	if deps.Usage.OverBudget(ctx) {
+		result.Error = usage.ErrBudgetExceeded
+		return result
+	}
+	article := Article{Title: stored.Title.String, Link: stored.Url.String}
+
+	content := stored.Content.String
+	if strings.TrimSpace(content) == "" {
+		if deps.Scraper == nil {
+			return recordFailure(ctx, deps, result, ErrNoScraper, maxAttempts)
+		}
+		scraped, strategy, err := scrapeArticle(ctx, deps, article)
+		if err != nil {
+			return recordFailure(ctx, deps, result, err, maxAttempts)
+		}
+		if err := storeContent(ctx, deps, stored, scraped, strategy); err != nil {
+			result.Error = err
+			return result
+		}
+		content = scraped
+	}
+
+	calls := &processor.UsageRecorder{}
+	analysis, prompt, err := analyzeArticle(ctx, deps, source, article, content, calls)
+	recordUsage(ctx, deps, stored.ID, source, calls)
+	if errors.Is(err, usage.ErrBudgetExceeded) {
+		result.Error = err
+		return result
+	}
+	if err != nil {
+		return recordFailure(ctx, deps, result, err, maxAttempts)
+	}
+
+	err = deps.Queries.CompleteArticleAnalysis(ctx, database.CompleteArticleAnalysisParams{
+		Summary:       nullString(analysis.Summary),
+		Entities:      analysis.EntitiesJSON(),
+		Topics:        analysis.TopicsJSON(),
+		ContentType:   nullString(analysis.ContentType),
+		PromptName:    nullString(prompt.Name),
+		PromptVersion: nullString(prompt.Version),
+		ID:            stored.ID,
+	})
+	if err != nil {
+		result.Error = errs.Wrap("store analysis", err)
+		return result
+	}
+	if err := tagArticle(ctx, deps.Queries, stored.ID, analysis); err != nil {
+		result.Error = errs.Wrap("tag article", err)
+		return result
+	}
+	result.Analyzed = true
+	return result
+}
+
+// recordFailure stores another failed attempt to analyze an article.
+func recordFailure(ctx context.Context, deps PipelineDeps, result ProcessResult, cause error, maxAttempts int) ProcessResult {
+	attempts := result.Article.AnalysisAttempts + 1
+	status := "pending"
+	if maxAttempts > 0 && attempts >= int64(maxAttempts) {
+		status = "failed"
+		result.GaveUp = true
+	}
+
+	result.Error = cause
+	err := deps.Queries.RecordArticleAnalysisFailure(ctx, database.RecordArticleAnalysisFailureParams{
+		AnalysisStatus:   nullString(status),
+		AnalysisAttempts: attempts,
+		AnalysisError:    nullString(cause.Error()),
+		ID:               result.Article.ID,
+	})
+	if err != nil {
+		result.Error = errors.Join(cause, errs.Wrap("record analysis failure", err))
+	}
+	return result
+}
+
+// ProcessConcurrently runs processFunc on articles with workerCount workers
+// and returns the results in the order of articles. done, if not nil, is
+// called with each result as soon as it is available, from the worker that
+// produced it.
+func ProcessConcurrently(ctx context.Context, articles []database.Article, workerCount int, processFunc func(context.Context, database.Article) ProcessResult, done func(ProcessResult)) []ProcessResult {
+	results := make([]ProcessResult, len(articles))
+	articleCh := make(chan int, len(articles))
+	var wg sync.WaitGroup
+
+	for i := 0; i < max(workerCount, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range articleCh {
+				if err := ctx.Err(); err != nil {
+					results[idx] = ProcessResult{Article: articles[idx], Error: err}
+				} else {
+					results[idx] = processFunc(ctx, articles[idx])
+				}
+				if done != nil {
+					done(results[idx])
+				}
+			}
+		}()
+	}
+
+	for i := range articles {
+		articleCh <- i
+	}
+	close(articleCh)
+
+	wg.Wait()
+	return results
+}
+
+// storeContent stores the scraped content of an article and regroups it into
+// a story, since the fingerprint it got when it was stored was taken without
+// the content.
+func storeContent(ctx context.Context, deps PipelineDeps, stored database.Article, content, strategy string) error {
+	err := deps.Queries.UpdateArticleContent(ctx, database.UpdateArticleContentParams{
+		Content:        nullString(content),
+		ScrapeStrategy: nullString(strategy),
+		ID:             stored.ID,
+	})
+	if err != nil {
+		return errs.Wrap("update article content", err)
+	}
+	stored.Content = nullString(content)
+	stored.ScrapeStrategy = nullString(strategy)
+	assignStory(ctx, deps.Queries, deps.Config, stored)
+	return nil
+}
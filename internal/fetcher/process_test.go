@@ -0,0 +1,230 @@
+package fetcher
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor/mocks"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/internal/jobs"
+	"github.com/robertguss/rss-agent-cli/internal/scraper"
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func createBacklogArticle(t *testing.T, queries *database.Queries, url, source, status, content string) database.Article {
+	article, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+		Title:          nullString("Article " + url),
+		Url:            nullString(url),
+		SourceName:     nullString(source),
+		PublishedDate:  sql.NullTime{Time: time.Now(), Valid: true},
+		Status:         nullString("unread"),
+		AnalysisStatus: nullString(status),
+		Content:        nullString(content),
+	})
+	require.NoError(t, err)
+	return article
+}
+
+func TestBacklog(t *testing.T) {
+	queries := setupHealthDB(t)
+	ctx := context.Background()
+
+	pending := createBacklogArticle(t, queries, "https://a.example/1", "A", "pending", "text")
+	unprocessed := createBacklogArticle(t, queries, "https://b.example/1", "B", "unprocessed", "text")
+	exhausted := createBacklogArticle(t, queries, "https://a.example/2", "A", "pending", "text")
+	done := createBacklogArticle(t, queries, "https://a.example/3", "A", "completed", "text")
+	retryable := createBacklogArticle(t, queries, "https://a.example/4", "A", "failed", "text")
+	gaveUp := createBacklogArticle(t, queries, "https://a.example/5", "A", "failed", "text")
+	for _, article := range []database.Article{exhausted, gaveUp} {
+		require.NoError(t, queries.RecordArticleAnalysisFailure(ctx, database.RecordArticleAnalysisFailureParams{
+			AnalysisStatus:   article.AnalysisStatus,
+			AnalysisAttempts: 3,
+			AnalysisError:    nullString("boom"),
+			ID:               article.ID,
+		}))
+	}
+
+	backlog, err := Backlog(ctx, queries, BacklogOptions{MaxAttempts: 3})
+	require.NoError(t, err)
+	require.Len(t, backlog, 3)
+	assert.ElementsMatch(t, []int64{pending.ID, retryable.ID}, []int64{backlog[0].ID, backlog[1].ID})
+	assert.Equal(t, unprocessed.ID, backlog[2].ID)
+
+	backlog, err = Backlog(ctx, queries, BacklogOptions{Source: "A"})
+	require.NoError(t, err)
+	assert.Len(t, backlog, 4)
+
+	backlog, err = Backlog(ctx, queries, BacklogOptions{ArticleID: done.ID, MaxAttempts: 3})
+	require.NoError(t, err)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, done.ID, backlog[0].ID)
+
+	_, err = Backlog(ctx, queries, BacklogOptions{ArticleID: 999})
+	assert.ErrorContains(t, err, "article 999 not found")
+}
+
+func TestBacklog_SkipsQueuedArticles(t *testing.T) {
+	queries := setupHealthDB(t)
+	ctx := context.Background()
+
+	queued := createBacklogArticle(t, queries, "https://a.example/1", "A", "pending", "")
+	free := createBacklogArticle(t, queries, "https://a.example/2", "A", "pending", "")
+	_, err := jobs.NewQueue(queries).Enqueue(ctx, JobScrape, queued.ID)
+	require.NoError(t, err)
+
+	backlog, err := Backlog(ctx, queries, BacklogOptions{})
+	require.NoError(t, err)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, free.ID, backlog[0].ID)
+
+	_, err = Backlog(ctx, queries, BacklogOptions{ArticleID: queued.ID})
+	assert.ErrorContains(t, err, "queued for the worker command")
+}
+
+func TestProcessArticle_StoresAnalysis(t *testing.T) {
+	queries := setupHealthDB(t)
+	ctx := context.Background()
+	stored := createBacklogArticle(t, queries, "https://example.com/a", "Blog", "unprocessed", "")
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "scraped content", mock.Anything).Return(&processor.AnalysisResult{
+		Summary: "A summary",
+		Topics:  []string{"Go"},
+	}, nil)
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("scraped content", nil),
+		AI:      mockAI,
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+	}
+
+	result := ProcessArticle(ctx, deps, Source{Name: "Blog"}, stored, 3)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Analyzed)
+
+	article, err := queries.GetArticle(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", article.AnalysisStatus.String)
+	assert.Equal(t, "A summary", article.Summary.String)
+	assert.Equal(t, "scraped content", article.Content.String)
+	assert.Equal(t, "analysis", article.PromptName.String)
+	assert.False(t, article.AnalysisError.Valid)
+	mockAI.AssertExpectations(t)
+}
+
+func TestProcessArticle_RecordsFailuresAndGivesUp(t *testing.T) {
+	queries := setupHealthDB(t)
+	ctx := context.Background()
+	stored := createBacklogArticle(t, queries, "https://example.com/a", "Blog", "pending", "content")
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "content", mock.Anything).Return(nil, assert.AnError)
+	deps := PipelineDeps{
+		AI:      mockAI,
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+	}
+
+	result := ProcessArticle(ctx, deps, Source{Name: "Blog"}, stored, 2)
+	require.Error(t, result.Error)
+	assert.False(t, result.GaveUp)
+
+	article, err := queries.GetArticle(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", article.AnalysisStatus.String)
+	assert.Equal(t, int64(1), article.AnalysisAttempts)
+	assert.Contains(t, article.AnalysisError.String, assert.AnError.Error())
+
+	result = ProcessArticle(ctx, deps, Source{Name: "Blog"}, article, 2)
+	require.Error(t, result.Error)
+	assert.True(t, result.GaveUp)
+
+	article, err = queries.GetArticle(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "failed", article.AnalysisStatus.String)
+	assert.Equal(t, int64(2), article.AnalysisAttempts)
+}
+
+func TestProcessArticle_RetriesFailedScrape(t *testing.T) {
+	queries := setupHealthDB(t)
+	ctx := context.Background()
+	source := Source{Name: "Blog"}
+
+	mockAI := new(mocks.AIProcessor)
+	mockAI.On("AnalyzeContentWithRetry", mock.Anything, "scraped content", mock.Anything).Return(&processor.AnalysisResult{Summary: "A summary"}, nil)
+	deps := PipelineDeps{
+		Scraper: scraper.NewMockScraper("", assert.AnError),
+		AI:      mockAI,
+		Queries: queries,
+		Config:  testutil.TestConfig(),
+	}
+
+	stored, err := StoreArticlesWithAI(ctx, deps, []Article{{
+		Title:         "Unreachable",
+		Link:          "https://example.com/a",
+		PublishedDate: time.Now(),
+	}}, source)
+	require.NoError(t, err)
+	require.Equal(t, 1, stored)
+
+	backlog, err := Backlog(ctx, queries, BacklogOptions{MaxAttempts: 3})
+	require.NoError(t, err)
+	require.Len(t, backlog, 1)
+	assert.Equal(t, "pending", backlog[0].AnalysisStatus.String)
+	assert.Equal(t, int64(1), backlog[0].AnalysisAttempts)
+
+	deps.Scraper = scraper.NewMockScraper("scraped content", nil)
+	result := ProcessArticle(ctx, deps, source, backlog[0], 3)
+	require.NoError(t, result.Error)
+	assert.True(t, result.Analyzed)
+
+	article, err := queries.GetArticle(ctx, backlog[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", article.AnalysisStatus.String)
+	assert.Equal(t, "A summary", article.Summary.String)
+
+	backlog, err = Backlog(ctx, queries, BacklogOptions{MaxAttempts: 3})
+	require.NoError(t, err)
+	assert.Empty(t, backlog)
+	mockAI.AssertExpectations(t)
+}
+
+func TestProcessArticle_NoContentWithoutScraper(t *testing.T) {
+	queries := setupHealthDB(t)
+	ctx := context.Background()
+	stored := createBacklogArticle(t, queries, "https://example.com/a", "Blog", "pending", "")
+
+	mockAI := new(mocks.AIProcessor)
+	deps := PipelineDeps{AI: mockAI, Queries: queries, Config: testutil.TestConfig()}
+
+	result := ProcessArticle(ctx, deps, Source{Name: "Blog"}, stored, 0)
+	assert.ErrorIs(t, result.Error, ErrNoScraper)
+	assert.False(t, result.GaveUp)
+	mockAI.AssertNotCalled(t, "AnalyzeContentWithRetry", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessConcurrently(t *testing.T) {
+	articles := make([]database.Article, 10)
+	for i := range articles {
+		articles[i].ID = int64(i + 1)
+	}
+
+	var done atomic.Int32
+	results := ProcessConcurrently(context.Background(), articles, 3, func(ctx context.Context, article database.Article) ProcessResult {
+		return ProcessResult{Article: article, Analyzed: article.ID%2 == 0}
+	}, func(ProcessResult) { done.Add(1) })
+
+	require.Len(t, results, 10)
+	for i, result := range results {
+		assert.Equal(t, int64(i+1), result.Article.ID)
+		assert.Equal(t, (i+1)%2 == 0, result.Analyzed)
+	}
+	assert.Equal(t, int32(10), done.Load())
+}
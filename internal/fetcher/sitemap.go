@@ -0,0 +1,104 @@
+package fetcher
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// sitemapAdapter handles XML sitemaps (https://www.sitemaps.org/protocol.html).
+// Every <url> with a <lastmod> becomes an article; entries without one are
+// skipped because there is no way to tell when they were published. Titles
+// come from the Google News <news:title> extension when present and are
+// otherwise derived from the URL path. Already stored URLs are skipped by the
+// usual de-duplication, so each fetch only adds new entries.
+type sitemapAdapter struct{}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc       string `xml:"loc"`
+	LastMod   string `xml:"lastmod"`
+	NewsTitle string `xml:"news>title"`
+}
+
+// errSitemapIndex is returned for sitemap index files, which list other
+// sitemaps rather than pages.
+var errSitemapIndex = errors.New("sitemap index files are not supported; add the child sitemaps as sources instead")
+
+// sitemapDateLayouts are the W3C Datetime forms allowed for <lastmod>.
+var sitemapDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+func (sitemapAdapter) Parse(body []byte, source Source) ([]Article, error) {
+	var set sitemapURLSet
+	if err := xml.Unmarshal(body, &set); err != nil {
+		var root struct{ XMLName xml.Name }
+		if xml.Unmarshal(body, &root) == nil && root.XMLName.Local == "sitemapindex" {
+			return nil, errSitemapIndex
+		}
+		return nil, fmt.Errorf("parse sitemap: %w", err)
+	}
+
+	var articles []Article
+	for _, u := range set.URLs {
+		loc := strings.TrimSpace(u.Loc)
+		lastMod, ok := parseSitemapDate(u.LastMod)
+		if loc == "" || !ok {
+			continue
+		}
+
+		articles = append(articles, Article{
+			Title:         firstNonBlank(u.NewsTitle, titleFromURL(loc)),
+			Link:          loc,
+			PublishedDate: lastMod,
+		})
+	}
+	return articles, nil
+}
+
+func parseSitemapDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range sitemapDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// titleFromURL turns the last path segment of a page URL into a readable
+// title, e.g. ".../2024/05/my-new-model.html" becomes "My new model".
+func titleFromURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	slug := path.Base(strings.TrimSuffix(u.Path, "/"))
+	slug = strings.TrimSuffix(slug, path.Ext(slug))
+	if slug == "" || slug == "." || slug == "/" {
+		return u.Host
+	}
+
+	words := strings.FieldsFunc(slug, func(r rune) bool { return r == '-' || r == '_' || r == '+' })
+	if len(words) == 0 {
+		return u.Host
+	}
+	title := []rune(strings.Join(words, " "))
+	title[0] = unicode.ToUpper(title[0])
+	return string(title)
+}
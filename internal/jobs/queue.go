@@ -0,0 +1,157 @@
+// Package jobs is a durable queue of work on stored articles. Jobs live in
+// the jobs table, so work queued by one run survives a crash or Ctrl-C and is
+// picked up by the next, and several processes sharing a database can work
+// through the queue together: a job is leased to one worker at a time, and
+// a lease that runs out without the job being finished, because its worker
+// died, lets another worker take the job over.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+)
+
+// Job states. Finished jobs are deleted rather than given a state.
+const (
+	StateQueued  = "queued"
+	StateRunning = "running"
+	StateFailed  = "failed"
+)
+
+// ErrLeaseLost is returned when finishing a job whose lease has expired and
+// that another worker may have taken over.
+var ErrLeaseLost = errors.New("job lease lost")
+
+// Job is a row of the jobs table.
+type Job = database.Job
+
+// Queue reads and writes the jobs table.
+type Queue struct {
+	queries *database.Queries
+	now     func() time.Time
+}
+
+// NewQueue returns the queue kept in the database of queries.
+func NewQueue(queries *database.Queries) *Queue {
+	return &Queue{queries: queries, now: time.Now}
+}
+
+// Enqueue queues a job of kind on an article to run as soon as possible. It
+// reports false, and queues nothing, if the article already has a queued or
+// running job of that kind.
+func (q *Queue) Enqueue(ctx context.Context, kind string, articleID int64) (bool, error) {
+	n, err := q.queries.EnqueueJob(ctx, database.EnqueueJobParams{
+		Kind:      kind,
+		ArticleID: articleID,
+		Now:       q.now().Unix(),
+	})
+	if err != nil {
+		return false, errs.Wrap("enqueue job", err)
+	}
+	return n > 0, nil
+}
+
+// Lease hands the next due job to owner for the duration of lease, counting
+// an attempt, and reports false if no job is due. Queued jobs are due at
+// their next_run_at; running jobs are due again once their lease expires.
+func (q *Queue) Lease(ctx context.Context, owner string, lease time.Duration) (Job, bool, error) {
+	now := q.now()
+	job, err := q.queries.LeaseJob(ctx, database.LeaseJobParams{
+		Owner:          sql.NullString{String: owner, Valid: true},
+		LeaseExpiresAt: sql.NullInt64{Int64: now.Add(lease).Unix(), Valid: true},
+		Now:            now.Unix(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return Job{}, false, nil
+	} else if err != nil {
+		return Job{}, false, errs.Wrap("lease job", err)
+	}
+	return job, true, nil
+}
+
+// Renew extends the lease on a running job to lease from now.
+func (q *Queue) Renew(ctx context.Context, job Job, lease time.Duration) error {
+	now := q.now()
+	n, err := q.queries.RenewJobLease(ctx, database.RenewJobLeaseParams{
+		LeaseExpiresAt: sql.NullInt64{Int64: now.Add(lease).Unix(), Valid: true},
+		Now:            now.Unix(),
+		ID:             job.ID,
+		Owner:          job.LeaseOwner,
+	})
+	if err != nil {
+		return errs.Wrap("renew job lease", err)
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Complete removes a job its worker has finished.
+func (q *Queue) Complete(ctx context.Context, job Job) error {
+	n, err := q.queries.CompleteJob(ctx, database.CompleteJobParams{
+		ID:         job.ID,
+		LeaseOwner: job.LeaseOwner,
+	})
+	if err != nil {
+		return errs.Wrap("complete job", err)
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Retry queues a job that failed with cause to run again after delay.
+func (q *Queue) Retry(ctx context.Context, job Job, cause error, delay time.Duration) error {
+	return q.release(ctx, job, StateQueued, job.Attempts, cause, q.now().Add(delay))
+}
+
+// Fail gives up on a job that failed with cause. It stays in the table, in
+// the failed state, for inspection.
+func (q *Queue) Fail(ctx context.Context, job Job, cause error) error {
+	return q.release(ctx, job, StateFailed, job.Attempts, cause, time.Unix(job.NextRunAt, 0))
+}
+
+// Postpone queues a job that could not run yet to run at until, without
+// counting the attempt.
+func (q *Queue) Postpone(ctx context.Context, job Job, cause error, until time.Time) error {
+	return q.release(ctx, job, StateQueued, max(job.Attempts-1, 0), cause, until)
+}
+
+func (q *Queue) release(ctx context.Context, job Job, state string, attempts int64, cause error, next time.Time) error {
+	var lastError sql.NullString
+	if cause != nil {
+		lastError = sql.NullString{String: cause.Error(), Valid: true}
+	}
+	n, err := q.queries.ReleaseJob(ctx, database.ReleaseJobParams{
+		State:     state,
+		Attempts:  attempts,
+		LastError: lastError,
+		NextRunAt: next.Unix(),
+		Now:       q.now().Unix(),
+		ID:        job.ID,
+		Owner:     job.LeaseOwner,
+	})
+	if err != nil {
+		return errs.Wrap("release job", err)
+	}
+	if n == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// Counts returns how many jobs there are of each kind in each state.
+func (q *Queue) Counts(ctx context.Context) ([]database.CountJobsRow, error) {
+	counts, err := q.queries.CountJobs(ctx)
+	if err != nil {
+		return nil, errs.Wrap("count jobs", err)
+	}
+	return counts, nil
+}
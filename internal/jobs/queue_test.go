@@ -0,0 +1,156 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// setupQueue returns a queue in a fresh database holding articleCount
+// articles, whose clock reads the time clock points to.
+func setupQueue(t *testing.T, articleCount int) (*Queue, *time.Time) {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1) // each connection would open its own in-memory database
+	require.NoError(t, database.InitSchema(db))
+	queries := database.New(db)
+
+	for i := 0; i < articleCount; i++ {
+		_, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+			Url: sql.NullString{String: "https://example.com/" + string(rune('a'+i)), Valid: true},
+		})
+		require.NoError(t, err)
+	}
+
+	clock := time.Unix(1_700_000_000, 0)
+	queue := NewQueue(queries)
+	queue.now = func() time.Time { return clock }
+	return queue, &clock
+}
+
+func TestQueue_EnqueueSkipsActiveDuplicates(t *testing.T) {
+	queue, _ := setupQueue(t, 1)
+	ctx := context.Background()
+
+	added, err := queue.Enqueue(ctx, "scrape", 1)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	added, err = queue.Enqueue(ctx, "scrape", 1)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	added, err = queue.Enqueue(ctx, "analyze", 1)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	job, ok, err := queue.Lease(ctx, "w1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, queue.Fail(ctx, job, assert.AnError))
+
+	added, err = queue.Enqueue(ctx, job.Kind, 1)
+	require.NoError(t, err)
+	assert.True(t, added, "a failed job does not block queueing the work again")
+}
+
+func TestQueue_LeaseTakesDueJobsInOrder(t *testing.T) {
+	queue, clock := setupQueue(t, 2)
+	ctx := context.Background()
+
+	_, err := queue.Enqueue(ctx, "scrape", 1)
+	require.NoError(t, err)
+	*clock = clock.Add(time.Second)
+	_, err = queue.Enqueue(ctx, "scrape", 2)
+	require.NoError(t, err)
+
+	first, ok, err := queue.Lease(ctx, "w1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), first.ArticleID)
+	assert.Equal(t, StateRunning, first.State)
+	assert.Equal(t, int64(1), first.Attempts)
+	assert.Equal(t, "w1", first.LeaseOwner.String)
+
+	second, ok, err := queue.Lease(ctx, "w2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, int64(2), second.ArticleID)
+
+	_, ok, err = queue.Lease(ctx, "w3", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, queue.Complete(ctx, first))
+	require.NoError(t, queue.Retry(ctx, second, assert.AnError, time.Minute))
+
+	_, ok, err = queue.Lease(ctx, "w3", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "a retried job waits out its delay")
+
+	*clock = clock.Add(time.Minute)
+	retried, ok, err := queue.Lease(ctx, "w3", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, second.ID, retried.ID)
+	assert.Equal(t, int64(2), retried.Attempts)
+	assert.Equal(t, assert.AnError.Error(), retried.LastError.String)
+}
+
+func TestQueue_ExpiredLeaseIsTakenOver(t *testing.T) {
+	queue, clock := setupQueue(t, 1)
+	ctx := context.Background()
+
+	_, err := queue.Enqueue(ctx, "analyze", 1)
+	require.NoError(t, err)
+	abandoned, ok, err := queue.Lease(ctx, "crashed", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	*clock = clock.Add(30 * time.Second)
+	_, ok, err = queue.Lease(ctx, "w2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	*clock = clock.Add(time.Minute)
+	taken, ok, err := queue.Lease(ctx, "w2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, abandoned.ID, taken.ID)
+	assert.Equal(t, "w2", taken.LeaseOwner.String)
+
+	assert.ErrorIs(t, queue.Complete(ctx, abandoned), ErrLeaseLost)
+	require.NoError(t, queue.Complete(ctx, taken))
+
+	counts, err := queue.Counts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestQueue_PostponeDoesNotCountAttempt(t *testing.T) {
+	queue, clock := setupQueue(t, 1)
+	ctx := context.Background()
+
+	_, err := queue.Enqueue(ctx, "analyze", 1)
+	require.NoError(t, err)
+	job, _, err := queue.Lease(ctx, "w1", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, queue.Postpone(ctx, job, assert.AnError, clock.Add(time.Hour)))
+
+	counts, err := queue.Counts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []database.CountJobsRow{{Kind: "analyze", State: StateQueued, Count: 1}}, counts)
+
+	*clock = clock.Add(time.Hour)
+	job, ok, err := queue.Lease(ctx, "w1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), job.Attempts)
+}
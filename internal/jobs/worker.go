@@ -0,0 +1,248 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// Handler does the work of a job. Its error decides what becomes of the
+// job: nil completes it, an error made by Permanent fails it at once, one
+// made by PostponeUntil queues it again without counting the attempt, and
+// any other error retries it after a backoff until the worker's MaxAttempts
+// is used up.
+type Handler func(ctx context.Context, job Job) error
+
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one that retrying the job will not fix.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type postponeError struct {
+	err   error
+	until time.Time
+}
+
+func (e *postponeError) Error() string { return e.err.Error() }
+func (e *postponeError) Unwrap() error { return e.err }
+
+// PostponeUntil marks err as a reason the job cannot run before until, such
+// as a spent budget, rather than a failure of the job.
+func PostponeUntil(err error, until time.Time) error {
+	if err == nil {
+		return nil
+	}
+	return &postponeError{err: err, until: until}
+}
+
+// Result is the outcome of running a job: the job as leased and the error
+// its handler returned.
+type Result struct {
+	Job Job
+	Err error
+}
+
+// Worker leases jobs from a queue and runs the handler for their kind. The
+// lease on a job is renewed while it runs, so a job is only taken over by
+// another worker once its worker has stopped, by crashing or losing the
+// database, for longer than Lease.
+type Worker struct {
+	Queue       *Queue
+	Owner       string                             // names the worker in leases; see NewOwner
+	Handlers    map[string]Handler                 // by job kind
+	Lease       time.Duration                      // zero or less means DefaultLease
+	MaxAttempts int                                // attempts before a job fails; zero or less retries without limit
+	Backoff     func(attempts int64) time.Duration // delay before a retry; nil means Backoff
+}
+
+// DefaultLease is how long a job is leased to a worker whose Lease is unset.
+const DefaultLease = 10 * time.Minute
+
+// Backoff waits 30 seconds before the first retry of a job and twice as
+// long before each retry after that, up to an hour.
+func Backoff(attempts int64) time.Duration {
+	delay := 30 * time.Second
+	for i := int64(1); i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
+
+// NewOwner returns a name for a worker that is unique among the processes
+// sharing a database: the host name, the process ID and a random suffix.
+func NewOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d:%04x", host, os.Getpid(), rand.IntN(0x10000))
+}
+
+// RunOnce leases the next due job and runs it, reporting false if no job was
+// due. The error is that of the queue; the handler's is in the result.
+func (w *Worker) RunOnce(ctx context.Context) (Result, bool, error) {
+	lease := w.Lease
+	if lease <= 0 {
+		lease = DefaultLease
+	}
+	job, ok, err := w.Queue.Lease(ctx, w.Owner, lease)
+	if err != nil || !ok {
+		return Result{}, false, err
+	}
+
+	handler, ok := w.Handlers[job.Kind]
+	var jobErr error
+	if ok {
+		jobCtx, stop := context.WithCancel(ctx)
+		heartbeat := make(chan struct{})
+		go func() {
+			defer close(heartbeat)
+			w.heartbeat(jobCtx, stop, job, lease)
+		}()
+		jobErr = handler(jobCtx, job)
+		stop()
+		<-heartbeat
+	} else {
+		jobErr = Permanent(fmt.Errorf("no handler for job kind %q", job.Kind))
+	}
+	return Result{Job: job, Err: jobErr}, true, w.finish(ctx, job, jobErr)
+}
+
+// heartbeat renews the lease on a running job every third of lease until
+// ctx is done, so that a job running longer than its lease is not taken
+// over. If another worker has taken the job over anyway, the job is
+// stopped with cancel.
+func (w *Worker) heartbeat(ctx context.Context, cancel context.CancelFunc, job Job, lease time.Duration) {
+	ticker := time.NewTicker(lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := w.Queue.Renew(ctx, job, lease)
+		switch {
+		case errors.Is(err, ErrLeaseLost):
+			logging.Warn("jobs", fmt.Sprintf("Lease on job %d was taken over, stopping it", job.ID))
+			cancel()
+			return
+		case err != nil && ctx.Err() == nil:
+			logging.Warn("jobs", fmt.Sprintf("Failed to renew the lease on job %d: %v", job.ID, err))
+		}
+	}
+}
+
+// finish records the outcome of a job in the queue. A job interrupted by
+// the cancellation of ctx is queued again as it was.
+func (w *Worker) finish(ctx context.Context, job Job, jobErr error) error {
+	var permanent *permanentError
+	var postpone *postponeError
+	var err error
+	switch {
+	case jobErr == nil:
+		err = w.Queue.Complete(ctx, job)
+	case ctx.Err() != nil:
+		err = w.Queue.Postpone(context.WithoutCancel(ctx), job, jobErr, w.Queue.now())
+	case errors.As(jobErr, &postpone):
+		err = w.Queue.Postpone(ctx, job, jobErr, postpone.until)
+	case errors.As(jobErr, &permanent), w.MaxAttempts > 0 && job.Attempts >= int64(w.MaxAttempts):
+		err = w.Queue.Fail(ctx, job, jobErr)
+	default:
+		backoff := w.Backoff
+		if backoff == nil {
+			backoff = Backoff
+		}
+		err = w.Queue.Retry(ctx, job, jobErr, backoff(job.Attempts))
+	}
+
+	if errors.Is(err, ErrLeaseLost) {
+		logging.Warn("jobs", fmt.Sprintf("Lease on job %d expired before it finished", job.ID))
+		return nil
+	}
+	return err
+}
+
+// Run runs jobs with concurrency goroutines and calls done, if not nil,
+// with the result of each. Once no job is due, Run returns if poll is zero
+// or less, and otherwise checks the queue again every poll until ctx is
+// done. A database locked by another worker is waited out; Run returns the
+// first other queue error, if any, after the other goroutines have finished
+// their jobs. The cancellation of ctx is not an error.
+func (w *Worker) Run(ctx context.Context, concurrency int, poll time.Duration, done func(Result)) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	stop, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for i := 0; i < max(concurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for stop.Err() == nil {
+				result, ran, err := w.RunOnce(ctx)
+				if ran && done != nil && err != nil {
+					done(result)
+				}
+				if database.IsBusy(err) {
+					// Another process kept the database locked past the
+					// busy timeout; its transaction will end soon. A job
+					// whose outcome could not be recorded stays leased
+					// until its lease runs out and is then run again.
+					logging.Warn("jobs", fmt.Sprintf("Database busy, retrying: %v", err))
+					select {
+					case <-stop.Done():
+					case <-time.After(busyDelay()):
+					}
+					continue
+				}
+				if err != nil {
+					if ctx.Err() == nil {
+						once.Do(func() { firstErr = err })
+					}
+					cancel()
+					return
+				}
+				if ran {
+					if done != nil {
+						done(result)
+					}
+					continue
+				}
+				if poll <= 0 {
+					return
+				}
+				select {
+				case <-stop.Done():
+				case <-time.After(poll):
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// busyDelay returns how long to wait before using a busy database again,
+// randomized so that workers in different processes do not retry in step.
+func busyDelay() time.Duration {
+	return 100*time.Millisecond + rand.N(400*time.Millisecond)
+}
@@ -0,0 +1,237 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorker_RunDrainsQueue(t *testing.T) {
+	queue, _ := setupQueue(t, 3)
+	ctx := context.Background()
+
+	for id := int64(1); id <= 3; id++ {
+		_, err := queue.Enqueue(ctx, "scrape", id)
+		require.NoError(t, err)
+	}
+
+	var scraped, analyzed atomic.Int32
+	worker := &Worker{
+		Queue: queue,
+		Owner: NewOwner(),
+		Lease: time.Minute,
+		Handlers: map[string]Handler{
+			"scrape": func(ctx context.Context, job Job) error {
+				scraped.Add(1)
+				_, err := queue.Enqueue(ctx, "analyze", job.ArticleID)
+				return err
+			},
+			"analyze": func(ctx context.Context, job Job) error {
+				analyzed.Add(1)
+				return nil
+			},
+		},
+	}
+
+	var results atomic.Int32
+	err := worker.Run(ctx, 2, 0, func(Result) { results.Add(1) })
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), scraped.Load())
+	assert.Equal(t, int32(3), analyzed.Load())
+	assert.Equal(t, int32(6), results.Load())
+
+	counts, err := queue.Counts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestWorker_RetriesThenFails(t *testing.T) {
+	queue, clock := setupQueue(t, 1)
+	ctx := context.Background()
+	_, err := queue.Enqueue(ctx, "analyze", 1)
+	require.NoError(t, err)
+
+	worker := &Worker{
+		Queue:       queue,
+		Owner:       "w1",
+		Lease:       time.Minute,
+		MaxAttempts: 2,
+		Handlers: map[string]Handler{
+			"analyze": func(context.Context, Job) error { return assert.AnError },
+		},
+	}
+
+	result, ran, err := worker.RunOnce(ctx)
+	require.NoError(t, err)
+	require.True(t, ran)
+	assert.ErrorIs(t, result.Err, assert.AnError)
+
+	_, ran, err = worker.RunOnce(ctx)
+	require.NoError(t, err)
+	assert.False(t, ran, "the retry waits for its backoff")
+
+	*clock = clock.Add(Backoff(1))
+	_, ran, err = worker.RunOnce(ctx)
+	require.NoError(t, err)
+	require.True(t, ran)
+
+	counts, err := queue.Counts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []database.CountJobsRow{{Kind: "analyze", State: StateFailed, Count: 1}}, counts)
+}
+
+func TestWorker_PermanentAndPostponedErrors(t *testing.T) {
+	queue, clock := setupQueue(t, 2)
+	ctx := context.Background()
+	_, err := queue.Enqueue(ctx, "broken", 1)
+	require.NoError(t, err)
+	_, err = queue.Enqueue(ctx, "unknown", 2)
+	require.NoError(t, err)
+
+	tomorrow := clock.Add(24 * time.Hour)
+	worker := &Worker{
+		Queue: queue,
+		Owner: "w1",
+		Lease: time.Minute,
+		Handlers: map[string]Handler{
+			"broken": func(context.Context, Job) error {
+				return PostponeUntil(errors.New("budget spent"), tomorrow)
+			},
+		},
+	}
+
+	require.NoError(t, worker.Run(ctx, 1, 0, nil))
+
+	counts, err := queue.Counts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []database.CountJobsRow{
+		{Kind: "broken", State: StateQueued, Count: 1},
+		{Kind: "unknown", State: StateFailed, Count: 1},
+	}, counts)
+
+	*clock = tomorrow
+	job, ok, err := queue.Lease(ctx, "w1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), job.Attempts)
+	assert.Equal(t, "budget spent", job.LastError.String)
+}
+
+func TestWorker_SharedDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	var queues []*Queue
+	for range 2 {
+		db, queries, err := database.Open(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		require.NoError(t, database.InitSchema(db))
+		queues = append(queues, NewQueue(queries))
+	}
+
+	ctx := context.Background()
+	const jobCount = 40
+	for i := range jobCount {
+		article, err := queues[0].queries.CreateArticle(ctx, database.CreateArticleParams{
+			Url: sql.NullString{String: "https://example.com/" + string(rune('a'+i)), Valid: true},
+		})
+		require.NoError(t, err)
+		_, err = queues[0].Enqueue(ctx, "analyze", article.ID)
+		require.NoError(t, err)
+	}
+
+	var mu sync.Mutex
+	runs := make(map[int64]int)
+	handlers := map[string]Handler{
+		"analyze": func(ctx context.Context, job Job) error {
+			mu.Lock()
+			runs[job.ArticleID]++
+			mu.Unlock()
+			return nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(queues))
+	for i, queue := range queues {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := &Worker{Queue: queue, Owner: NewOwner(), Handlers: handlers, Lease: time.Minute}
+			errs[i] = worker.Run(ctx, 4, 0, nil)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Len(t, runs, jobCount)
+	for id, n := range runs {
+		assert.Equal(t, 1, n, "article %d", id)
+	}
+	counts, err := queues[1].Counts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestWorker_RenewsLeaseWhileJobRuns(t *testing.T) {
+	queue, _ := setupQueue(t, 1)
+	ctx := context.Background()
+	_, err := queue.Enqueue(ctx, "analyze", 1)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	clock := time.Unix(1_700_000_000, 0)
+	queue.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return clock
+	}
+	advance := func(d time.Duration) {
+		mu.Lock()
+		clock = clock.Add(d)
+		mu.Unlock()
+	}
+
+	var takenOver bool
+	worker := &Worker{
+		Queue: queue,
+		Owner: "slow",
+		Lease: 3 * time.Second,
+		Handlers: map[string]Handler{
+			"analyze": func(ctx context.Context, job Job) error {
+				advance(2 * time.Second)
+				time.Sleep(1500 * time.Millisecond) // one heartbeat
+				advance(2 * time.Second)            // past the original lease
+				_, takenOver, _ = queue.Lease(ctx, "other", time.Minute)
+				return nil
+			},
+		},
+	}
+
+	result, ran, err := worker.RunOnce(ctx)
+	require.NoError(t, err)
+	require.True(t, ran)
+	assert.NoError(t, result.Err)
+	assert.False(t, takenOver, "the renewed lease has not run out")
+
+	counts, err := queue.Counts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 4*time.Minute, Backoff(4))
+	assert.Equal(t, time.Hour, Backoff(20))
+}
@@ -0,0 +1,213 @@
+// Package opml converts between OPML subscription lists and configured
+// news sources.
+//
+// Exported documents are OPML 2.0. Besides the standard text, type and
+// xmlUrl attributes, each feed outline carries name, priority and enabled
+// attributes, the scraper, prompt and HTML selectors of the source as
+// attributes in the Namespace namespace, and sources with a group are nested
+// under a folder outline, so that exporting and re-importing a config loses
+// nothing.
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+)
+
+// DefaultPriority is given to imported feeds that carry no priority attribute.
+const DefaultPriority = 3
+
+// Namespace is the XML namespace of the outline attributes for source
+// settings that OPML has no place for. The Outline struct tags spell it out,
+// as tags cannot refer to constants.
+const Namespace = "https://github.com/robertguss/rss-agent-cli"
+
+// dateLayoutSeparator joins the date layouts of an HTML source in one
+// attribute; layouts contain spaces and commas but no vertical bars.
+const dateLayoutSeparator = "|"
+
+// Document is an OPML document.
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head holds the OPML document metadata.
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+// Body holds the top-level outlines.
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is either a feed (XMLURL is set) or a folder of nested outlines.
+// Name, Priority and Enabled are this tool's custom attributes; the fields
+// after them are in the Namespace namespace.
+type Outline struct {
+	Text            string    `xml:"text,attr"`
+	Title           string    `xml:"title,attr,omitempty"`
+	Type            string    `xml:"type,attr,omitempty"`
+	XMLURL          string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL         string    `xml:"htmlUrl,attr,omitempty"`
+	Category        string    `xml:"category,attr,omitempty"`
+	Name            string    `xml:"name,attr,omitempty"`
+	Priority        string    `xml:"priority,attr,omitempty"`
+	Enabled         string    `xml:"enabled,attr,omitempty"`
+	Scraper         string    `xml:"https://github.com/robertguss/rss-agent-cli scraper,attr,omitempty"`
+	Prompt          string    `xml:"https://github.com/robertguss/rss-agent-cli prompt,attr,omitempty"`
+	HTMLItem        string    `xml:"https://github.com/robertguss/rss-agent-cli htmlItem,attr,omitempty"`
+	HTMLTitle       string    `xml:"https://github.com/robertguss/rss-agent-cli htmlTitle,attr,omitempty"`
+	HTMLLink        string    `xml:"https://github.com/robertguss/rss-agent-cli htmlLink,attr,omitempty"`
+	HTMLDate        string    `xml:"https://github.com/robertguss/rss-agent-cli htmlDate,attr,omitempty"`
+	HTMLDateLayouts string    `xml:"https://github.com/robertguss/rss-agent-cli htmlDateLayouts,attr,omitempty"`
+	Outlines        []Outline `xml:"outline"`
+}
+
+// Parse reads an OPML document.
+func Parse(r io.Reader) (*Document, error) {
+	var doc Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse opml: %w", err)
+	}
+	return &doc, nil
+}
+
+// Sources flattens the feed outlines of doc into sources. A feed nested in a
+// folder outline gets the folder's text as its group; a top-level feed falls
+// back to its first category, e.g. "/Tech/AI" becomes "Tech/AI".
+func (d *Document) Sources() []config.Source {
+	var sources []config.Source
+	var walk func(outlines []Outline, group string)
+	walk = func(outlines []Outline, group string) {
+		for _, o := range outlines {
+			if o.XMLURL == "" {
+				folder := strings.TrimSpace(firstNonEmpty(o.Text, o.Title))
+				if group != "" && folder != "" {
+					folder = group + "/" + folder
+				}
+				walk(o.Outlines, firstNonEmpty(folder, group))
+				continue
+			}
+			sources = append(sources, o.source(group))
+		}
+	}
+	walk(d.Body.Outlines, "")
+	return sources
+}
+
+func (o Outline) source(group string) config.Source {
+	source := config.Source{
+		Name:     strings.TrimSpace(firstNonEmpty(o.Name, o.Title, o.Text, o.XMLURL)),
+		URL:      strings.TrimSpace(o.XMLURL),
+		Type:     strings.ToLower(strings.TrimSpace(o.Type)),
+		Priority: DefaultPriority,
+		Group:    group,
+		HTML: config.HTMLSelectors{
+			Item:  o.HTMLItem,
+			Title: o.HTMLTitle,
+			Link:  o.HTMLLink,
+			Date:  o.HTMLDate,
+		},
+		Scraper: strings.TrimSpace(o.Scraper),
+		Prompt:  strings.TrimSpace(o.Prompt),
+	}
+	if o.HTMLDateLayouts != "" {
+		source.HTML.DateLayouts = strings.Split(o.HTMLDateLayouts, dateLayoutSeparator)
+	}
+	if source.Type == "" {
+		source.Type = "rss"
+	}
+	if p, err := strconv.Atoi(strings.TrimSpace(o.Priority)); err == nil && p > 0 {
+		source.Priority = p
+	}
+	if enabled, err := strconv.ParseBool(strings.TrimSpace(o.Enabled)); err == nil {
+		source.Enabled = &enabled
+	}
+	if source.Group == "" && o.Category != "" {
+		category := strings.TrimSpace(strings.Split(o.Category, ",")[0])
+		source.Group = strings.Trim(category, "/")
+	}
+	return source
+}
+
+// FromSources builds an OPML 2.0 document listing sources in order. Sources
+// sharing a group are nested under one folder outline, placed where the
+// group first appears.
+func FromSources(title string, sources []config.Source, created time.Time) *Document {
+	doc := &Document{
+		Version: "2.0",
+		Head:    Head{Title: title, DateCreated: created.UTC().Format(time.RFC1123Z)},
+	}
+
+	folders := make(map[string]int)
+	for _, source := range sources {
+		o := Outline{
+			Text:            source.Name,
+			Title:           source.Name,
+			Type:            source.Type,
+			XMLURL:          source.URL,
+			Name:            source.Name,
+			Priority:        strconv.Itoa(source.Priority),
+			Scraper:         source.Scraper,
+			Prompt:          source.Prompt,
+			HTMLItem:        source.HTML.Item,
+			HTMLTitle:       source.HTML.Title,
+			HTMLLink:        source.HTML.Link,
+			HTMLDate:        source.HTML.Date,
+			HTMLDateLayouts: strings.Join(source.HTML.DateLayouts, dateLayoutSeparator),
+		}
+		if source.Priority == 0 {
+			o.Priority = ""
+		}
+		if source.Enabled != nil {
+			o.Enabled = strconv.FormatBool(*source.Enabled)
+		}
+
+		if source.Group == "" {
+			doc.Body.Outlines = append(doc.Body.Outlines, o)
+			continue
+		}
+		i, ok := folders[source.Group]
+		if !ok {
+			i = len(doc.Body.Outlines)
+			folders[source.Group] = i
+			doc.Body.Outlines = append(doc.Body.Outlines, Outline{Text: source.Group, Title: source.Group})
+		}
+		doc.Body.Outlines[i].Outlines = append(doc.Body.Outlines[i].Outlines, o)
+	}
+	return doc
+}
+
+// Write encodes doc as indented XML with a declaration.
+func (d *Document) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("write opml: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package opml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const readerExport = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="1.0">
+  <head><title>Reader subscriptions</title></head>
+  <body>
+    <outline text="AI" title="AI">
+      <outline text="OpenAI Blog" title="OpenAI Blog" type="rss" xmlUrl="https://openai.com/blog/rss.xml" htmlUrl="https://openai.com/blog"/>
+      <outline text="Research">
+        <outline text="arXiv cs.LG" type="rss" xmlUrl="https://export.arxiv.org/rss/cs.LG"/>
+      </outline>
+    </outline>
+    <outline text="Hacker News" type="rss" xmlUrl="https://hnrss.org/frontpage" category="/Tech/News,/Daily"/>
+    <outline text="Loose" xmlUrl="https://example.com/loose.xml"/>
+  </body>
+</opml>`
+
+func TestParse_MapsFoldersAndCategoriesToGroups(t *testing.T) {
+	doc, err := Parse(strings.NewReader(readerExport))
+	require.NoError(t, err)
+
+	sources := doc.Sources()
+	require.Len(t, sources, 4)
+
+	assert.Equal(t, "OpenAI Blog", sources[0].Name)
+	assert.Equal(t, "https://openai.com/blog/rss.xml", sources[0].URL)
+	assert.Equal(t, "AI", sources[0].Group)
+	assert.Equal(t, "rss", sources[0].Type)
+	assert.Equal(t, DefaultPriority, sources[0].Priority)
+	assert.Nil(t, sources[0].Enabled)
+
+	assert.Equal(t, "arXiv cs.LG", sources[1].Name)
+	assert.Equal(t, "AI/Research", sources[1].Group)
+
+	assert.Equal(t, "Hacker News", sources[2].Name)
+	assert.Equal(t, "Tech/News", sources[2].Group)
+
+	assert.Equal(t, "Loose", sources[3].Name)
+	assert.Equal(t, "rss", sources[3].Type)
+	assert.Empty(t, sources[3].Group)
+}
+
+func TestParse_InvalidXML(t *testing.T) {
+	_, err := Parse(strings.NewReader("<opml><body>"))
+	assert.Error(t, err)
+}
+
+func TestFromSources_RoundTripIsLossless(t *testing.T) {
+	disabled := false
+	enabled := true
+	sources := []config.Source{
+		{Name: "OpenAI Blog", URL: "https://openai.com/blog/rss.xml", Type: "rss", Priority: 1, Group: "AI"},
+		{Name: "Ars Technica", URL: "http://feeds.arstechnica.com/arstechnica/technology-lab", Type: "rss", Priority: 2, Enabled: &disabled},
+		{Name: "Sitemap & Co", URL: "https://example.com/sitemap.xml?a=1&b=2", Type: "sitemap", Priority: 3, Group: "AI", Enabled: &enabled},
+		{Name: "Research Feed", URL: "https://example.com/research.xml", Type: "atom", Priority: 2, Group: "AI/Research"},
+	}
+
+	var buf bytes.Buffer
+	doc := FromSources("Test", sources, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, doc.Write(&buf))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "<?xml"))
+	assert.Contains(t, out, `<opml version="2.0">`)
+	assert.Contains(t, out, `<dateCreated>Fri, 02 Jan 2026 03:04:05 +0000</dateCreated>`)
+
+	parsed, err := Parse(&buf)
+	require.NoError(t, err)
+
+	got := parsed.Sources()
+	require.Len(t, got, 4)
+	// Sources in the same group are exported together, after the group's
+	// first member.
+	assert.Equal(t, []config.Source{sources[0], sources[2], sources[1], sources[3]}, got)
+}
+
+func TestFromSources_RoundTripKeepsSourceSettings(t *testing.T) {
+	sources := []config.Source{{
+		Name:     "Lab News",
+		URL:      "https://example.com/news",
+		Type:     "html",
+		Priority: 2,
+		HTML: config.HTMLSelectors{
+			Item:        "article.post",
+			Title:       "h2 a",
+			Link:        "h2 a",
+			Date:        "time",
+			DateLayouts: []string{"Jan 2, 2006", "2006-01-02"},
+		},
+		Scraper: "readability",
+		Prompt:  "prompts/research.tmpl",
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, FromSources("Test", sources, time.Now()).Write(&buf))
+	assert.Contains(t, buf.String(), `xmlns:rss-agent-cli="`+Namespace+`"`)
+
+	parsed, err := Parse(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, sources, parsed.Sources())
+}
@@ -0,0 +1,37 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+)
+
+// CanonicalLink downloads a page and returns the absolute URL named by its
+// <link rel="canonical">, or an empty string if it declares none.
+func CanonicalLink(ctx context.Context, rawURL string, cfg *config.Config) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.NetworkTimeout)
+	defer cancel()
+
+	body, pageURL, err := fetchPage(ctx, rawURL)
+	if err != nil {
+		return "", errs.Wrap("fetch canonical link for "+rawURL, err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(body)
+	if err != nil {
+		return "", errs.Wrap("parse "+rawURL, err)
+	}
+
+	href, ok := doc.Find(`link[rel~="canonical"][href]`).First().Attr("href")
+	if !ok || strings.TrimSpace(href) == "" {
+		return "", nil
+	}
+	canonical, err := pageURL.Parse(strings.TrimSpace(href))
+	if err != nil || (canonical.Scheme != "http" && canonical.Scheme != "https") {
+		return "", nil
+	}
+	return canonical.String(), nil
+}
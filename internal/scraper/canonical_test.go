@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalLink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch r.URL.Path {
+		case "/post/amp":
+			_, _ = w.Write([]byte(`<html><head><link rel="amphtml" href="/post/amp"><link rel="canonical" href="/post"></head><body></body></html>`))
+		case "/plain":
+			_, _ = w.Write([]byte(`<html><head><title>No canonical</title></head></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := testutil.TestConfig()
+
+	link, err := CanonicalLink(context.Background(), server.URL+"/post/amp", cfg)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/post", link, "relative hrefs resolve against the page")
+
+	link, err = CanonicalLink(context.Background(), server.URL+"/plain", cfg)
+	require.NoError(t, err)
+	assert.Empty(t, link)
+
+	_, err = CanonicalLink(context.Background(), server.URL+"/missing", cfg)
+	assert.Error(t, err)
+}
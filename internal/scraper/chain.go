@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// Feed names the strategy that falls back to the content embedded in the
+// feed item itself; see FromFeed.
+const Feed = "feed"
+
+// StrategyScraper is implemented by scrapers that can report which
+// extraction strategy produced the content they return.
+type StrategyScraper interface {
+	ScrapeWithStrategy(ctx context.Context, url string, cfg *config.Config) (content, strategy string, err error)
+}
+
+// Chain tries several scrapers in order and returns the first content any of
+// them produces.
+type Chain struct {
+	names    []string
+	scrapers []Scraper
+}
+
+// NewChain returns a chain of the named scrapers, tried in the given order.
+func NewChain(names ...string) (*Chain, error) {
+	c := &Chain{}
+	for _, name := range names {
+		s, err := newSingle(name)
+		if err != nil {
+			return nil, err
+		}
+		c.names = append(c.names, strings.ToLower(name))
+		c.scrapers = append(c.scrapers, s)
+	}
+	if len(c.scrapers) == 0 {
+		return nil, errors.New("scraper chain is empty")
+	}
+	return c, nil
+}
+
+func (c *Chain) Scrape(rawURL string) (string, error) {
+	var errs []error
+	for i, s := range c.scrapers {
+		content, err := s.Scrape(rawURL)
+		if err == nil {
+			return content, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", c.names[i], err))
+	}
+	return "", errors.Join(errs...)
+}
+
+func (c *Chain) ScrapeWithRetry(ctx context.Context, rawURL string, cfg *config.Config) (string, error) {
+	content, _, err := c.ScrapeWithStrategy(ctx, rawURL, cfg)
+	return content, err
+}
+
+// ScrapeWithStrategy returns the content from the first scraper that
+// succeeds along with that scraper's name. If all of them fail, the error
+// joins every scraper's error.
+func (c *Chain) ScrapeWithStrategy(ctx context.Context, rawURL string, cfg *config.Config) (string, string, error) {
+	var errs []error
+	for i, s := range c.scrapers {
+		content, err := s.ScrapeWithRetry(ctx, rawURL, cfg)
+		if err == nil {
+			return content, c.names[i], nil
+		}
+		if i < len(c.scrapers)-1 {
+			logging.Warn("scrape_fallback", fmt.Sprintf("%s failed for %s, trying %s: %v", c.names[i], rawURL, c.names[i+1], err))
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", c.names[i], err))
+	}
+	return "", "", errors.Join(errs...)
+}
+
+// ScrapeWithStrategy scrapes rawURL with s and names the strategy that
+// produced the content: the one reported by a StrategyScraper, else the
+// name of the scraper itself.
+func ScrapeWithStrategy(ctx context.Context, s Scraper, rawURL string, cfg *config.Config) (string, string, error) {
+	if ss, ok := s.(StrategyScraper); ok {
+		return ss.ScrapeWithStrategy(ctx, rawURL, cfg)
+	}
+
+	content, err := s.ScrapeWithRetry(ctx, rawURL, cfg)
+	if err != nil {
+		return "", "", err
+	}
+	return content, strategyName(s), nil
+}
+
+func strategyName(s Scraper) string {
+	switch s.(type) {
+	case *JinaScraper:
+		return Jina
+	case *ReadabilityScraper:
+		return Readability
+	default:
+		return "custom"
+	}
+}
+
+// FromFeed converts the HTML content a feed embedded for an item to
+// Markdown, resolving relative URLs against the article link. It returns
+// ErrNoContent if the feed carried no text.
+func FromFeed(feedHTML, articleURL string) (string, error) {
+	base, _ := url.Parse(articleURL)
+	markdown, err := HTMLToMarkdown(feedHTML, base)
+	if err != nil {
+		return "", err
+	}
+	if markdown == "" {
+		return "", ErrNoContent
+	}
+	return markdown, nil
+}
@@ -0,0 +1,69 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain_FallsBackInOrder(t *testing.T) {
+	failing := NewMockScraper("", errors.New("jina down"))
+	working := NewMockScraper("# Extracted", nil)
+	chain := &Chain{names: []string{Jina, Readability}, scrapers: []Scraper{failing, working}}
+
+	content, strategy, err := chain.ScrapeWithStrategy(context.Background(), "https://example.com/a", testutil.TestConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "# Extracted", content)
+	assert.Equal(t, Readability, strategy)
+
+	content, err = chain.Scrape("https://example.com/a")
+	require.NoError(t, err)
+	assert.Equal(t, "# Extracted", content)
+}
+
+func TestChain_AllFail(t *testing.T) {
+	chain := &Chain{
+		names:    []string{Jina, Readability},
+		scrapers: []Scraper{NewMockScraper("", errors.New("jina down")), NewMockScraper("", ErrNoContent)},
+	}
+
+	_, _, err := chain.ScrapeWithStrategy(context.Background(), "https://example.com/a", testutil.TestConfig())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNoContent)
+	assert.Contains(t, err.Error(), "jina: jina down")
+	assert.Contains(t, err.Error(), "readability: ")
+}
+
+func TestNewChain(t *testing.T) {
+	chain, err := NewChain(Jina, Readability)
+	require.NoError(t, err)
+	assert.Equal(t, []string{Jina, Readability}, chain.names)
+
+	_, err = NewChain(Jina, "magic")
+	assert.Error(t, err)
+
+	_, err = NewChain()
+	assert.Error(t, err)
+}
+
+func TestScrapeWithStrategy_NamesSingleScrapers(t *testing.T) {
+	_, strategy, err := ScrapeWithStrategy(context.Background(), NewMockScraper("content", nil), "https://example.com/a", testutil.TestConfig())
+	require.NoError(t, err)
+	assert.Equal(t, "custom", strategy)
+
+	assert.Equal(t, Jina, strategyName(NewJinaScraper()))
+	assert.Equal(t, Readability, strategyName(NewReadabilityScraper()))
+}
+
+func TestFromFeed(t *testing.T) {
+	markdown, err := FromFeed(`<p>Feed <b>content</b> with <a href="/more">a link</a>.</p>`, "https://example.com/posts/1")
+	require.NoError(t, err)
+	assert.Equal(t, "Feed **content** with [a link](https://example.com/more).", markdown)
+
+	_, err = FromFeed("<p>  </p>", "https://example.com/posts/1")
+	assert.ErrorIs(t, err, ErrNoContent)
+}
@@ -1,4 +1,5 @@
 // Package scraper provides HTML and RSS scraping capabilities.
-// It normalizes article metadata, extracts clean content using Jina Reader,
-// and handles various content sources for the news aggregation pipeline.
+// It extracts clean Markdown content either through the Jina Reader service
+// or in-process with a readability-style extractor, and handles various
+// content sources for the news aggregation pipeline.
 package scraper
@@ -2,6 +2,8 @@ package scraper
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/robertguss/rss-agent-cli/internal/config"
 )
@@ -25,3 +27,32 @@ func (j *JinaScraper) ScrapeWithRetry(ctx context.Context, url string, cfg *conf
 func NewJinaScraper() *JinaScraper {
 	return &JinaScraper{}
 }
+
+// Scraper names accepted by New and the scraper config settings.
+const (
+	Auto        = "auto"
+	Jina        = "jina"
+	Readability = "readability"
+)
+
+// New returns the scraper with the given name. Auto, also selected by an
+// empty name, chains Jina Reader with in-process readability extraction.
+func New(name string) (Scraper, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", Auto:
+		return NewChain(Jina, Readability)
+	default:
+		return newSingle(name)
+	}
+}
+
+func newSingle(name string) (Scraper, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case Jina:
+		return NewJinaScraper(), nil
+	case Readability:
+		return NewReadabilityScraper(), nil
+	default:
+		return nil, fmt.Errorf("unknown scraper %q (want %q, %q or %q)", name, Auto, Jina, Readability)
+	}
+}
@@ -0,0 +1,260 @@
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	whitespaceRun = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+)
+
+// HTMLToMarkdown converts an HTML fragment or document to Markdown. Relative
+// link and image URLs are resolved against base, which may be nil. Elements
+// that carry no readable content, such as scripts and forms, are dropped.
+func HTMLToMarkdown(fragment string, base *url.URL) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	w := markdownWriter{base: base}
+	for _, n := range nodes {
+		b.WriteString(w.node(n))
+	}
+	return cleanMarkdown(b.String()), nil
+}
+
+// nodeToMarkdown converts a parsed node and its children to Markdown.
+func nodeToMarkdown(n *html.Node, base *url.URL) string {
+	w := markdownWriter{base: base}
+	return cleanMarkdown(w.node(n))
+}
+
+type markdownWriter struct {
+	base *url.URL
+}
+
+func (w markdownWriter) children(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(w.node(c))
+	}
+	return b.String()
+}
+
+func (w markdownWriter) node(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeMarkdown(whitespaceRun.ReplaceAllString(n.Data, " "))
+	case html.DocumentNode:
+		return w.children(n)
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Template, atom.Iframe,
+		atom.Form, atom.Button, atom.Input, atom.Select, atom.Textarea, atom.Svg, atom.Head:
+		return ""
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := inlineText(w.children(n))
+		if text == "" {
+			return ""
+		}
+		level := int(n.Data[1] - '0')
+		return block(strings.Repeat("#", level) + " " + text)
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Main, atom.Header,
+		atom.Footer, atom.Figure, atom.Figcaption, atom.Dl, atom.Dt, atom.Dd,
+		atom.Table, atom.Tbody, atom.Thead, atom.Details, atom.Summary:
+		return block(w.children(n))
+	case atom.Tr:
+		var cells []string
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.DataAtom == atom.Td || c.DataAtom == atom.Th) {
+				cells = append(cells, inlineText(w.children(c)))
+			}
+		}
+		return "\n" + strings.Join(cells, " | ") + "\n"
+	case atom.Br:
+		return "\n"
+	case atom.Hr:
+		return block("---")
+	case atom.Pre:
+		text := strings.Trim(textContent(n), "\n")
+		if text == "" {
+			return ""
+		}
+		return block("```\n" + text + "\n```")
+	case atom.Code, atom.Kbd, atom.Samp:
+		text := whitespaceRun.ReplaceAllString(textContent(n), " ")
+		if strings.TrimSpace(text) == "" {
+			return ""
+		}
+		return "`" + text + "`"
+	case atom.Strong, atom.B:
+		return wrapInline(w.children(n), "**")
+	case atom.Em, atom.I:
+		return wrapInline(w.children(n), "_")
+	case atom.Del, atom.S, atom.Strike:
+		return wrapInline(w.children(n), "~~")
+	case atom.A:
+		text := inlineText(w.children(n))
+		href := w.resolve(attr(n, "href"))
+		if href == "" || strings.HasPrefix(href, "javascript:") {
+			return text
+		}
+		if text == "" {
+			return ""
+		}
+		return fmt.Sprintf("[%s](%s)", text, href)
+	case atom.Img:
+		src := w.resolve(attr(n, "src"))
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return ""
+		}
+		return fmt.Sprintf("![%s](%s)", strings.TrimSpace(attr(n, "alt")), src)
+	case atom.Ul, atom.Ol:
+		return block(w.list(n))
+	case atom.Blockquote:
+		text := strings.TrimSpace(cleanMarkdown(w.children(n)))
+		if text == "" {
+			return ""
+		}
+		lines := strings.Split(text, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return block(strings.Join(lines, "\n"))
+	}
+	return w.children(n)
+}
+
+func (w markdownWriter) list(n *html.Node) string {
+	var b strings.Builder
+	i := 0
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+		i++
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", i)
+		}
+
+		item := strings.TrimSpace(cleanMarkdown(w.children(c)))
+		var lines []string
+		for j, line := range strings.Split(item, "\n") {
+			switch {
+			case j == 0:
+				lines = append(lines, marker+line)
+			case strings.TrimSpace(line) != "":
+				lines = append(lines, strings.Repeat(" ", len(marker))+line)
+			}
+		}
+		b.WriteString(strings.Join(lines, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func (w markdownWriter) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || w.base == nil {
+		return ref
+	}
+	u, err := w.base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+func block(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return ""
+	}
+	return "\n\n" + s + "\n\n"
+}
+
+func wrapInline(s, marker string) string {
+	text := strings.TrimSpace(s)
+	if text == "" {
+		return s
+	}
+	// Keep the surrounding spaces outside the markers so that
+	// "a <b>bold </b>word" stays valid Markdown.
+	lead := s[:len(s)-len(strings.TrimLeft(s, " "))]
+	trail := s[len(strings.TrimRight(s, " ")):]
+	return lead + marker + text + marker + trail
+}
+
+func inlineText(s string) string {
+	return strings.TrimSpace(whitespaceRun.ReplaceAllString(s, " "))
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && c.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// escapeMarkdown escapes characters that would otherwise start Markdown
+// emphasis or links inside plain text.
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer(`*`, `\*`, `_`, `\_`, "`", "\\`", `[`, `\[`, `]`, `\]`).Replace(s)
+}
+
+// cleanMarkdown trims stray whitespace left by inline text at the start and
+// end of lines, outside code fences, and collapses runs of blank lines.
+func cleanMarkdown(s string) string {
+	lines := strings.Split(s, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = strings.TrimRight(line, " \t")
+		if strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "  ") {
+			line = line[1:]
+		}
+		lines[i] = line
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,58 @@
+package scraper
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	base, _ := url.Parse("https://example.com/posts/")
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "headings and paragraphs",
+			html: "<h1>Title</h1><p>First   paragraph\nwraps.</p><h3>Sub</h3><p>Second.</p>",
+			want: "# Title\n\nFirst paragraph wraps.\n\n### Sub\n\nSecond.",
+		},
+		{
+			name: "inline formatting and links",
+			html: `<p>Some <strong>bold </strong>and <em>italic</em> text with <code>x := 1</code> and a <a href="../about">link</a>.</p>`,
+			want: "Some **bold** and _italic_ text with `x := 1` and a [link](https://example.com/about).",
+		},
+		{
+			name: "nested lists",
+			html: "<ol><li>One<ul><li>Nested</li></ul></li><li>Two</li></ol>",
+			want: "1. One\n   - Nested\n2. Two",
+		},
+		{
+			name: "blockquote and rule",
+			html: "<blockquote><p>Quoted</p><p>Twice</p></blockquote><hr><p>After</p>",
+			want: "> Quoted\n>\n> Twice\n\n---\n\nAfter",
+		},
+		{
+			name: "preformatted code keeps indentation",
+			html: "<pre><code>func main() {\n    fmt.Println(\"hi\")\n}</code></pre>",
+			want: "```\nfunc main() {\n    fmt.Println(\"hi\")\n}\n```",
+		},
+		{
+			name: "drops scripts and escapes markdown in text",
+			html: "<p>2*3 = 6 and snake_case</p><script>alert(1)</script>",
+			want: "2\\*3 = 6 and snake\\_case",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HTMLToMarkdown(tt.html, base)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
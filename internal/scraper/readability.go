@@ -0,0 +1,346 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+	"github.com/robertguss/rss-agent-cli/pkg/retry"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/net/html/charset"
+)
+
+// ErrNoContent is returned when a page has no block of text that looks like
+// an article body.
+var ErrNoContent = errors.New("scraper: no readable content found")
+
+// minReadableLength is the shortest extracted text accepted as an article.
+const minReadableLength = 140
+
+var (
+	unlikelyCandidate = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|extra|foot|header|legends|menu|modal|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|ad-break|agegate|pagination|pager|popup|share|subscribe|newsletter|promo`)
+	maybeCandidate    = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveClass     = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story`)
+	negativeClass     = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// ReadabilityScraper downloads pages directly and extracts their main
+// content in-process, in the spirit of Mozilla's Readability: paragraphs are
+// scored by length and punctuation, scores propagate to their ancestors, and
+// the best-scoring container (with related siblings) is converted to Markdown.
+// It needs no third-party service but copes less well than Jina Reader with
+// pages that render their content with JavaScript.
+type ReadabilityScraper struct{}
+
+func NewReadabilityScraper() *ReadabilityScraper {
+	return &ReadabilityScraper{}
+}
+
+func (r *ReadabilityScraper) Scrape(rawURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	return r.scrape(ctx, rawURL)
+}
+
+func (r *ReadabilityScraper) ScrapeWithRetry(ctx context.Context, rawURL string, cfg *config.Config) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.NetworkTimeout)
+	defer cancel()
+
+	var content string
+	err := retry.DoWithCallback(ctx, cfg.RetryConfig(), func() error {
+		var e error
+		content, e = r.scrape(ctx, rawURL)
+		return e
+	}, func(attempt int, err error) {
+		logging.Retry("scrape_url", attempt, err)
+	})
+
+	if err != nil {
+		wrappedErr := errs.Wrap("scrape "+rawURL, err)
+		logging.Error("scrape_url", wrappedErr)
+		return "", wrappedErr
+	}
+
+	return content, nil
+}
+
+func (r *ReadabilityScraper) scrape(ctx context.Context, rawURL string) (string, error) {
+	body, pageURL, err := fetchPage(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	return ExtractReadable(body, pageURL)
+}
+
+// fetchPage downloads an HTML page and returns its body, decoded to UTF-8,
+// along with the URL it was finally served from.
+func fetchPage(ctx context.Context, rawURL string) (io.Reader, *url.URL, error) {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, nil, ErrInvalidURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; rss-agent-cli)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, ErrStatus{Code: resp.StatusCode}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, nil, fmt.Errorf("scraper: unsupported content type %q", contentType)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, 5<<20), contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	page, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bytes.NewReader(page), resp.Request.URL, nil
+}
+
+// ExtractReadable finds the main content of an HTML page and returns it as
+// Markdown, headed by the page title. Relative URLs are resolved against
+// pageURL. It returns ErrNoContent if nothing resembling an article is found.
+func ExtractReadable(r io.Reader, pageURL *url.URL) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return "", err
+	}
+
+	base := pageURL
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok && base != nil {
+		if ref, err := base.Parse(href); err == nil {
+			base = ref
+		}
+	}
+
+	title := pageTitle(doc)
+	prepareDocument(doc)
+
+	content := topCandidate(doc)
+	if content == nil {
+		return "", ErrNoContent
+	}
+
+	markdown := nodeToMarkdown(content, base)
+	if len([]rune(markdown)) < minReadableLength {
+		return "", ErrNoContent
+	}
+
+	if title != "" && !strings.HasPrefix(markdown, "# ") {
+		markdown = "# " + title + "\n\n" + markdown
+	}
+	return markdown, nil
+}
+
+func pageTitle(doc *goquery.Document) string {
+	if og, ok := doc.Find(`meta[property="og:title"]`).Attr("content"); ok && strings.TrimSpace(og) != "" {
+		return inlineText(og)
+	}
+	if h1 := doc.Find("h1"); h1.Length() == 1 {
+		return inlineText(h1.Text())
+	}
+	return inlineText(doc.Find("title").First().Text())
+}
+
+// prepareDocument strips elements that never hold article text and those
+// whose class or id marks them as page chrome.
+func prepareDocument(doc *goquery.Document) {
+	doc.Find("script, style, noscript, template, iframe, form, button, input, select, textarea, svg, nav, aside, footer, object, embed").Remove()
+
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "body" || goquery.NodeName(s) == "html" || goquery.NodeName(s) == "article" {
+			return
+		}
+		match := matchString(s.Nodes[0])
+		if unlikelyCandidate.MatchString(match) && !maybeCandidate.MatchString(match) {
+			s.Remove()
+		}
+	})
+}
+
+func matchString(n *html.Node) string {
+	return attr(n, "class") + " " + attr(n, "id")
+}
+
+// topCandidate scores every container of a paragraph and returns a wrapper
+// holding the best one plus siblings that look like part of the same article.
+func topCandidate(doc *goquery.Document) *html.Node {
+	scores := make(map[*html.Node]float64)
+	var order []*html.Node
+
+	initialize := func(n *html.Node) {
+		if _, ok := scores[n]; ok {
+			return
+		}
+		scores[n] = initialScore(n)
+		order = append(order, n)
+	}
+
+	doc.Find("p, pre, td, blockquote, li, div").Each(func(_ int, s *goquery.Selection) {
+		n := s.Nodes[0]
+		if n.DataAtom == atom.Div && hasBlockChild(n) {
+			return
+		}
+
+		text := inlineText(s.Text())
+		if len(text) < 25 {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		for level, ancestor := 0, n.Parent; ancestor != nil && level < 3; level, ancestor = level+1, ancestor.Parent {
+			if ancestor.Type != html.ElementNode || ancestor.DataAtom == atom.Html {
+				break
+			}
+			initialize(ancestor)
+			switch level {
+			case 0:
+				scores[ancestor] += score
+			case 1:
+				scores[ancestor] += score / 2
+			default:
+				scores[ancestor] += score / float64(level*3)
+			}
+		}
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, n := range order {
+		scores[n] *= 1 - linkDensity(n)
+		if scores[n] > bestScore {
+			best, bestScore = n, scores[n]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	// Gather siblings that scored well or are plain paragraphs of prose, so
+	// that articles split across several containers are kept together.
+	wrapper := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	if best.Parent == nil {
+		return best
+	}
+	threshold := math.Max(10, bestScore*0.2)
+	var keep []*html.Node
+	for sibling := best.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+		if sibling == best || scores[sibling] >= threshold || isProseParagraph(sibling) {
+			keep = append(keep, sibling)
+		}
+	}
+	for _, n := range keep {
+		n.Parent.RemoveChild(n)
+		wrapper.AppendChild(n)
+	}
+	return wrapper
+}
+
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.DataAtom {
+	case atom.Article:
+		score = 10
+	case atom.Div, atom.Main, atom.Section:
+		score = 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score = 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		score = -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score = -5
+	}
+
+	for _, value := range []string{attr(n, "class"), attr(n, "id")} {
+		if value == "" {
+			continue
+		}
+		if negativeClass.MatchString(value) {
+			score -= 25
+		}
+		if positiveClass.MatchString(value) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity is the share of an element's text that sits inside links.
+func linkDensity(n *html.Node) float64 {
+	total := len(inlineText(textContent(n)))
+	if total == 0 {
+		return 0
+	}
+	var linked int
+	var walk func(*html.Node)
+	walk = func(c *html.Node) {
+		if c.Type == html.ElementNode && c.DataAtom == atom.A {
+			linked += len(inlineText(textContent(c)))
+			return
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return float64(linked) / float64(total)
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		switch c.DataAtom {
+		case atom.Div, atom.P, atom.Pre, atom.Table, atom.Ul, atom.Ol, atom.Blockquote,
+			atom.Section, atom.Article, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			return true
+		}
+	}
+	return false
+}
+
+func isProseParagraph(n *html.Node) bool {
+	if n.DataAtom != atom.P {
+		return false
+	}
+	text := inlineText(textContent(n))
+	density := linkDensity(n)
+	if len(text) > 80 {
+		return density < 0.25
+	}
+	return len(text) > 0 && density == 0 && strings.ContainsAny(text, ".!?")
+}
@@ -0,0 +1,124 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>New Model Released | Lab Blog</title>
+  <meta property="og:title" content="New Model Released">
+  <script>var tracking = "should not appear";</script>
+</head>
+<body>
+  <header class="site-header"><a href="/">Lab Blog</a></header>
+  <nav><a href="/about">About</a> <a href="/archive">Archive</a></nav>
+  <div class="layout">
+    <div class="sidebar">
+      <p>Subscribe to our newsletter for weekly updates, offers, and more news, delivered every Monday.</p>
+    </div>
+    <div class="post-body">
+      <p>Today we are releasing a new open-weights model, trained on a larger corpus, with better reasoning, coding, and multilingual performance than its predecessor.</p>
+      <h2>What changed</h2>
+      <p>The architecture keeps the same depth, but the context window grows to 128k tokens, and the tokenizer now covers more scripts, which reduces cost for many languages.</p>
+      <ul>
+        <li>Longer <strong>context</strong> window</li>
+        <li>Read the <a href="/docs/model-card">model card</a></li>
+      </ul>
+      <pre><code>pip install lab-model
+lab-model serve --port 8080</code></pre>
+      <p><img src="images/chart.png" alt="Benchmark chart"></p>
+    </div>
+    <div class="comments">
+      <p>Great post, thanks for sharing all of these details, can't wait to try it out this weekend!</p>
+    </div>
+  </div>
+  <footer>Copyright Lab</footer>
+</body>
+</html>`
+
+func TestExtractReadable(t *testing.T) {
+	pageURL, _ := url.Parse("https://example.com/blog/new-model")
+
+	markdown, err := ExtractReadable(strings.NewReader(articlePage), pageURL)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(markdown, "# New Model Released\n\n"), markdown)
+	assert.Contains(t, markdown, "Today we are releasing a new open-weights model")
+	assert.Contains(t, markdown, "## What changed")
+	assert.Contains(t, markdown, "- Longer **context** window")
+	assert.Contains(t, markdown, "- Read the [model card](https://example.com/docs/model-card)")
+	assert.Contains(t, markdown, "```\npip install lab-model\nlab-model serve --port 8080\n```")
+	assert.Contains(t, markdown, "![Benchmark chart](https://example.com/blog/images/chart.png)")
+
+	assert.NotContains(t, markdown, "newsletter")
+	assert.NotContains(t, markdown, "Great post")
+	assert.NotContains(t, markdown, "Archive")
+	assert.NotContains(t, markdown, "tracking")
+	assert.NotContains(t, markdown, "Copyright")
+}
+
+func TestExtractReadable_NoContent(t *testing.T) {
+	_, err := ExtractReadable(strings.NewReader(`<html><body><nav><a href="/">Home</a></nav><p>Short.</p></body></html>`), nil)
+	assert.ErrorIs(t, err, ErrNoContent)
+}
+
+func TestReadabilityScraper_ScrapeWithRetry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blog/new-model":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(articlePage))
+		case "/paper.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.4"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := NewReadabilityScraper()
+	cfg := testutil.TestConfig()
+
+	markdown, err := s.ScrapeWithRetry(context.Background(), server.URL+"/blog/new-model", cfg)
+	require.NoError(t, err)
+	assert.Contains(t, markdown, "Today we are releasing")
+	assert.Contains(t, markdown, "("+server.URL+"/docs/model-card)")
+
+	_, err = s.ScrapeWithRetry(context.Background(), server.URL+"/paper.pdf", cfg)
+	assert.ErrorContains(t, err, "unsupported content type")
+
+	_, err = s.Scrape(server.URL + "/missing")
+	assert.Equal(t, ErrStatus{Code: http.StatusNotFound}, err)
+
+	_, err = s.Scrape("ftp://example.com/file")
+	assert.ErrorIs(t, err, ErrInvalidURL)
+}
+
+func TestNew(t *testing.T) {
+	s, err := New("")
+	require.NoError(t, err)
+	assert.IsType(t, &Chain{}, s)
+
+	s, err = New("jina")
+	require.NoError(t, err)
+	assert.IsType(t, &JinaScraper{}, s)
+
+	s, err = New("Readability")
+	require.NoError(t, err)
+	assert.IsType(t, &ReadabilityScraper{}, s)
+
+	_, err = New("magic")
+	assert.ErrorContains(t, err, `unknown scraper "magic"`)
+}
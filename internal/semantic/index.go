@@ -0,0 +1,205 @@
+// Package semantic ranks stored articles by meaning rather than by keywords.
+// Articles are embedded with a processor.Embedder, their vectors are kept in
+// the database, and queries are answered from an in-memory index by cosine
+// similarity.
+package semantic
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+)
+
+const (
+	// embedBatchSize is how many articles are embedded per request.
+	embedBatchSize = 32
+	// maxTextLength caps the characters of an article that are embedded;
+	// the opening of an article says most about what it is about.
+	maxTextLength = 8000
+)
+
+// Match is an article found by Index.Search.
+type Match struct {
+	ArticleID int64
+	Score     float64 // cosine similarity to the query, at most 1
+}
+
+// Index answers semantic queries over the stored articles. It is built on
+// first use: articles without a vector from the embedder's model are
+// embedded and stored, then all vectors are loaded into memory.
+type Index struct {
+	queries  *database.Queries
+	embedder processor.Embedder
+
+	once    sync.Once
+	err     error
+	ids     []int64
+	vectors [][]float32
+}
+
+func NewIndex(queries *database.Queries, embedder processor.Embedder) *Index {
+	return &Index{queries: queries, embedder: embedder}
+}
+
+// Search returns all indexed articles ordered by their similarity to query,
+// most similar first.
+func (ix *Index) Search(ctx context.Context, query string) ([]Match, error) {
+	ix.once.Do(func() { ix.err = ix.build(ctx) })
+	if ix.err != nil {
+		return nil, ix.err
+	}
+
+	vectors, err := ix.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, errs.Wrap("embed query", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embed query: got %d vectors", len(vectors))
+	}
+	q := normalize(vectors[0])
+
+	matches := make([]Match, 0, len(ix.ids))
+	for i, v := range ix.vectors {
+		if len(v) != len(q) {
+			continue
+		}
+		matches = append(matches, Match{ArticleID: ix.ids[i], Score: dot(q, v)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches, nil
+}
+
+func (ix *Index) build(ctx context.Context) error {
+	if _, err := Backfill(ctx, ix.queries, ix.embedder); err != nil {
+		return err
+	}
+
+	rows, err := ix.queries.ListArticleEmbeddings(ctx, ix.embedder.Model())
+	if err != nil {
+		return errs.Wrap("list article embeddings", err)
+	}
+	for _, row := range rows {
+		ix.ids = append(ix.ids, row.ArticleID)
+		ix.vectors = append(ix.vectors, normalize(DecodeVector(row.Vector)))
+	}
+	return nil
+}
+
+// Backfill embeds every article that has no vector from the embedder's
+// model yet and stores the vectors. An article's vector is dropped when its
+// title, summary or content changes, so articles analyzed since they were
+// embedded are embedded again. It returns the number of articles embedded.
+func Backfill(ctx context.Context, queries *database.Queries, embedder processor.Embedder) (int, error) {
+	articles, err := queries.ListArticlesWithoutEmbedding(ctx, embedder.Model())
+	if err != nil {
+		return 0, errs.Wrap("list articles without embedding", err)
+	}
+
+	embedded := 0
+	for start := 0; start < len(articles); start += embedBatchSize {
+		batch := articles[start:min(start+embedBatchSize, len(articles))]
+		texts := make([]string, len(batch))
+		for i, article := range batch {
+			texts[i] = ArticleText(article)
+		}
+
+		vectors, err := embedder.Embed(ctx, texts)
+		if err != nil {
+			return embedded, errs.Wrap("embed articles", err)
+		}
+		if len(vectors) != len(batch) {
+			return embedded, fmt.Errorf("embed articles: got %d vectors for %d articles", len(vectors), len(batch))
+		}
+
+		for i, article := range batch {
+			err := queries.UpsertArticleEmbedding(ctx, database.UpsertArticleEmbeddingParams{
+				ArticleID:  article.ID,
+				Model:      embedder.Model(),
+				Dimensions: int64(len(vectors[i])),
+				Vector:     EncodeVector(vectors[i]),
+			})
+			if err != nil {
+				return embedded, errs.Wrap("store article embedding", err)
+			}
+			embedded++
+		}
+	}
+	return embedded, nil
+}
+
+// ArticleText is the text of an article that gets embedded: its title,
+// summary and the beginning of its content.
+func ArticleText(article database.Article) string {
+	var parts []string
+	for _, part := range []string{article.Title.String, article.Summary.String, article.Content.String} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	text := strings.Join(parts, "\n\n")
+	if runes := []rune(text); len(runes) > maxTextLength {
+		text = string(runes[:maxTextLength])
+	}
+	return text
+}
+
+// EncodeVector encodes a vector as little-endian float32 values.
+func EncodeVector(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(x))
+	}
+	return b
+}
+
+// DecodeVector decodes a vector encoded by EncodeVector.
+func DecodeVector(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v
+}
+
+// Cosine returns the cosine similarity of two vectors of the same length,
+// or 0 if either is a zero vector.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	return dot(normalize(a), normalize(b))
+}
+
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		return out
+	}
+	norm := math.Sqrt(sum)
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
@@ -0,0 +1,138 @@
+package semantic
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordEmbedder embeds a text as counts of a few known words, which is
+// enough to tell topics apart in tests.
+type wordEmbedder struct {
+	calls int
+}
+
+var vocabulary = []string{"robot", "chip", "model", "law"}
+
+func (e *wordEmbedder) Model() string { return "words" }
+
+func (e *wordEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(vocabulary))
+		for j, word := range vocabulary {
+			vectors[i][j] = float32(strings.Count(strings.ToLower(text), word))
+		}
+	}
+	return vectors, nil
+}
+
+func setupTestDB(t *testing.T) *database.Queries {
+	t.Helper()
+	db, queries, err := database.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, database.InitSchema(db))
+	return queries
+}
+
+func createArticle(t *testing.T, queries *database.Queries, title, summary string) int64 {
+	t.Helper()
+	article, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+		Title:   sql.NullString{String: title, Valid: true},
+		Url:     sql.NullString{String: "https://example.com/" + title, Valid: true},
+		Summary: sql.NullString{String: summary, Valid: true},
+	})
+	require.NoError(t, err)
+	return article.ID
+}
+
+func TestIndex_SearchRanksByMeaning(t *testing.T) {
+	queries := setupTestDB(t)
+	ctx := context.Background()
+
+	robots := createArticle(t, queries, "Humanoid robot startup raises funds", "The robot maker plans a robot for homes.")
+	chips := createArticle(t, queries, "New inference chip", "A chip built to serve a model cheaply.")
+	laws := createArticle(t, queries, "EU passes AI law", "The law regulates model providers.")
+
+	embedder := &wordEmbedder{}
+	index := NewIndex(queries, embedder)
+
+	matches, err := index.Search(ctx, "robots")
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+	assert.Equal(t, robots, matches[0].ArticleID)
+	assert.InDelta(t, 1.0, matches[0].Score, 1e-6)
+
+	matches, err = index.Search(ctx, "regulation law")
+	require.NoError(t, err)
+	assert.Equal(t, laws, matches[0].ArticleID)
+	assert.NotEqual(t, chips, matches[0].ArticleID)
+
+	// Vectors were stored on first use; a new index only embeds queries.
+	embedder.calls = 0
+	_, err = NewIndex(queries, embedder).Search(ctx, "chip")
+	require.NoError(t, err)
+	assert.Equal(t, 1, embedder.calls)
+}
+
+func TestBackfill_OnlyEmbedsMissingArticles(t *testing.T) {
+	queries := setupTestDB(t)
+	ctx := context.Background()
+	createArticle(t, queries, "robot", "")
+
+	embedded, err := Backfill(ctx, queries, &wordEmbedder{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, embedded)
+
+	createArticle(t, queries, "chip", "")
+	embedded, err = Backfill(ctx, queries, &wordEmbedder{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, embedded)
+}
+
+func TestBackfill_ReembedsArticlesWhoseTextChanged(t *testing.T) {
+	queries := setupTestDB(t)
+	ctx := context.Background()
+	id := createArticle(t, queries, "Pending article", "")
+
+	embedded, err := Backfill(ctx, queries, &wordEmbedder{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, embedded)
+
+	require.NoError(t, queries.UpdateArticleContent(ctx, database.UpdateArticleContentParams{
+		Content: sql.NullString{String: "A robot that folds laundry.", Valid: true},
+		ID:      id,
+	}))
+	require.NoError(t, queries.CompleteArticleAnalysis(ctx, database.CompleteArticleAnalysisParams{
+		Summary: sql.NullString{String: "The robot is for sale.", Valid: true},
+		ID:      id,
+	}))
+
+	embedder := &wordEmbedder{}
+	matches, err := NewIndex(queries, embedder).Search(ctx, "robot")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.InDelta(t, 1.0, matches[0].Score, 1e-6, "the vector reflects the scraped content and summary")
+	assert.Equal(t, 2, embedder.calls)
+
+	embedded, err = Backfill(ctx, queries, &wordEmbedder{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, embedded)
+}
+
+func TestVectorEncodingAndCosine(t *testing.T) {
+	v := []float32{0.25, -1, 3.5}
+	assert.Equal(t, v, DecodeVector(EncodeVector(v)))
+
+	assert.InDelta(t, 1.0, Cosine([]float32{1, 2}, []float32{2, 4}), 1e-6)
+	assert.InDelta(t, 0.0, Cosine([]float32{1, 0}, []float32{0, 1}), 1e-6)
+	assert.Zero(t, Cosine([]float32{0, 0}, []float32{1, 1}))
+	assert.Zero(t, Cosine([]float32{1}, []float32{1, 1}))
+}
@@ -0,0 +1,155 @@
+package story
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+)
+
+// Defaults used for StoryConfig fields left at zero.
+const (
+	DefaultWindow        = 48 * time.Hour
+	DefaultMinSimilarity = 0.5
+	DefaultMaxDistance   = 3
+	DefaultShingleSize   = 3
+)
+
+// Match reports whether two fingerprints belong to the same story under cfg.
+func Match(a, b Fingerprint, cfg config.StoryConfig) bool {
+	if a.Empty() || b.Empty() {
+		return false
+	}
+	cfg = withDefaults(cfg)
+	if cfg.MinSimilarity >= 0 && a.Similarity(b) >= cfg.MinSimilarity {
+		return true
+	}
+	return cfg.MaxDistance >= 0 && a.Distance(b) <= cfg.MaxDistance
+}
+
+// Assign fingerprints a stored article and puts it in a story: that of the
+// most similar matching article published within the window, from any
+// source, or a new story if none matches. It stores the fingerprint,
+// updates the article's story_group_id and returns it. An article without
+// any words is left out of every story.
+func Assign(ctx context.Context, queries *database.Queries, cfg config.StoryConfig, article database.Article) (string, error) {
+	cfg = withDefaults(cfg)
+
+	text := article.Content.String
+	if text == "" {
+		text = article.Summary.String
+	}
+	fp := New(article.Title.String, text, cfg.ShingleSize)
+
+	published := time.Now()
+	if article.PublishedDate.Valid && !article.PublishedDate.Time.IsZero() {
+		published = article.PublishedDate.Time
+	}
+
+	groupID := ""
+	if !fp.Empty() {
+		groupID = newGroupID(fp, article.ID)
+
+		candidates, err := queries.ListStoryCandidates(ctx, database.ListStoryCandidatesParams{
+			PublishedFrom: published.Add(-cfg.Window).Unix(),
+			PublishedTo:   published.Add(cfg.Window).Unix(),
+			ArticleID:     article.ID,
+		})
+		if err != nil {
+			return "", errs.Wrap("list story candidates", err)
+		}
+
+		best := -1.0
+		for _, candidate := range candidates {
+			if !candidate.StoryGroupID.Valid || candidate.StoryGroupID.String == "" {
+				continue
+			}
+			other := Fingerprint{
+				SimHash: uint64(candidate.Simhash),
+				MinHash: DecodeMinHash(candidate.Minhash),
+			}
+			if !Match(fp, other, cfg) {
+				continue
+			}
+			if similarity := fp.Similarity(other); similarity > best {
+				best = similarity
+				groupID = candidate.StoryGroupID.String
+			}
+		}
+	}
+
+	err := queries.UpsertArticleFingerprint(ctx, database.UpsertArticleFingerprintParams{
+		ArticleID:   article.ID,
+		Simhash:     int64(fp.SimHash),
+		Minhash:     fp.MinHashBytes(),
+		PublishedAt: published.Unix(),
+	})
+	if err != nil {
+		return "", errs.Wrap("store article fingerprint", err)
+	}
+
+	err = queries.UpdateArticleStoryGroup(ctx, database.UpdateArticleStoryGroupParams{
+		StoryGroupID: sql.NullString{String: groupID, Valid: groupID != ""},
+		ID:           article.ID,
+	})
+	if err != nil {
+		return "", errs.Wrap("update story group", err)
+	}
+	return groupID, nil
+}
+
+// AssignPending assigns every article that has no fingerprint yet to a
+// story, oldest first, so that each story is started by its earliest article.
+// It returns the number of articles assigned.
+func AssignPending(ctx context.Context, queries *database.Queries, cfg config.StoryConfig) (int, error) {
+	articles, err := queries.ListUnclusteredArticles(ctx)
+	if err != nil {
+		return 0, errs.Wrap("list unclustered articles", err)
+	}
+	sort.SliceStable(articles, func(i, j int) bool {
+		return articles[i].PublishedDate.Time.Before(articles[j].PublishedDate.Time)
+	})
+
+	for i, article := range articles {
+		if _, err := Assign(ctx, queries, cfg, article); err != nil {
+			return i, err
+		}
+	}
+	return len(articles), nil
+}
+
+// Rebuild discards all fingerprints and reassigns every article, for use
+// after the thresholds in cfg have changed.
+func Rebuild(ctx context.Context, queries *database.Queries, cfg config.StoryConfig) (int, error) {
+	if err := queries.DeleteArticleFingerprints(ctx); err != nil {
+		return 0, errs.Wrap("delete article fingerprints", err)
+	}
+	return AssignPending(ctx, queries, cfg)
+}
+
+// newGroupID names the story started by an article. The article ID is mixed
+// in so that a story repeated outside the window does not reuse the ID.
+func newGroupID(fp Fingerprint, articleID int64) string {
+	return fmt.Sprintf("%016x", mix(fp.SimHash^uint64(articleID)))
+}
+
+func withDefaults(cfg config.StoryConfig) config.StoryConfig {
+	if cfg.Window == 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.MinSimilarity == 0 {
+		cfg.MinSimilarity = DefaultMinSimilarity
+	}
+	if cfg.MaxDistance == 0 {
+		cfg.MaxDistance = DefaultMaxDistance
+	}
+	if cfg.ShingleSize <= 0 {
+		cfg.ShingleSize = DefaultShingleSize
+	}
+	return cfg
+}
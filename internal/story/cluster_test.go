@@ -0,0 +1,88 @@
+package story
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTestDB(t *testing.T) *database.Queries {
+	t.Helper()
+	db, queries, err := database.Open(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, database.InitSchema(db))
+	return queries
+}
+
+func createArticle(t *testing.T, queries *database.Queries, source, title, content string, published time.Time) database.Article {
+	t.Helper()
+	article, err := queries.CreateArticle(context.Background(), database.CreateArticleParams{
+		Title:         sql.NullString{String: title, Valid: true},
+		Url:           sql.NullString{String: "https://" + source + ".example.com/" + title, Valid: true},
+		SourceName:    sql.NullString{String: source, Valid: true},
+		PublishedDate: sql.NullTime{Time: published, Valid: true},
+		Content:       sql.NullString{String: content, Valid: true},
+	})
+	require.NoError(t, err)
+	return article
+}
+
+func storyOf(t *testing.T, queries *database.Queries, id int64) string {
+	t.Helper()
+	article, err := queries.GetArticle(context.Background(), id)
+	require.NoError(t, err)
+	return article.StoryGroupID.String
+}
+
+func TestAssignPending_GroupsAcrossSourcesWithinWindow(t *testing.T) {
+	queries := setupTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	first := createArticle(t, queries, "alpha", "OpenAI releases GPT-5", launchReport, now)
+	rewrite := createArticle(t, queries, "beta", "OpenAI Releases GPT-5 to Everyone", launchRewrite, now.Add(6*time.Hour))
+	late := createArticle(t, queries, "gamma", "OpenAI releases GPT-5", launchReport, now.Add(10*24*time.Hour))
+	other := createArticle(t, queries, "beta", "DeepMind unveils drug discovery model", otherStory, now.Add(time.Hour))
+
+	assigned, err := AssignPending(ctx, queries, config.StoryConfig{Window: 48 * time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, 4, assigned)
+
+	group := storyOf(t, queries, first.ID)
+	assert.NotEmpty(t, group)
+	assert.Equal(t, group, storyOf(t, queries, rewrite.ID))
+	assert.NotEqual(t, group, storyOf(t, queries, other.ID))
+	assert.NotEqual(t, group, storyOf(t, queries, late.ID), "articles outside the window start a new story")
+
+	assigned, err = AssignPending(ctx, queries, config.StoryConfig{})
+	require.NoError(t, err)
+	assert.Zero(t, assigned, "fingerprinted articles are not assigned again")
+}
+
+func TestAssign_IsStable(t *testing.T) {
+	queries := setupTestDB(t)
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	first := createArticle(t, queries, "alpha", "OpenAI releases GPT-5", launchReport, now)
+	group, err := Assign(ctx, queries, config.StoryConfig{}, first)
+	require.NoError(t, err)
+
+	rewrite := createArticle(t, queries, "beta", "OpenAI Releases GPT-5 to Everyone", launchRewrite, now.Add(time.Hour))
+	got, err := Assign(ctx, queries, config.StoryConfig{}, rewrite)
+	require.NoError(t, err)
+	assert.Equal(t, group, got)
+
+	assigned, err := Rebuild(ctx, queries, config.StoryConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, assigned)
+	assert.Equal(t, group, storyOf(t, queries, first.ID), "rebuilding keeps story IDs")
+	assert.Equal(t, group, storyOf(t, queries, rewrite.ID))
+}
@@ -0,0 +1,200 @@
+// Package story groups articles that report the same story, typically the
+// same announcement covered by several sources, using locality-sensitive
+// fingerprints of their text.
+//
+// Each article's title and content are normalized and cut into overlapping
+// word shingles. Two fingerprints are taken over the shingles: a 64-bit
+// SimHash, whose Hamming distance to another SimHash is small for texts that
+// are nearly identical, and a MinHash signature, whose agreement with
+// another signature estimates the Jaccard similarity of the two shingle sets
+// and so also catches rewrites that share a good part of their wording.
+package story
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MinHashSize is the number of hash functions in a MinHash signature.
+const MinHashSize = 64
+
+var (
+	markdownLink = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	bareURL      = regexp.MustCompile(`https?://\S+`)
+)
+
+// stopWords are left out of shingles; they carry little about a story and
+// otherwise make unrelated texts look alike.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "has": true, "have": true,
+	"in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"were": true, "will": true, "with": true,
+}
+
+// Fingerprint holds the locality-sensitive hashes of an article's text. The
+// zero value fingerprints a text without any words and is similar to nothing.
+type Fingerprint struct {
+	SimHash uint64
+	MinHash []uint32
+}
+
+// New fingerprints an article's title and content, hashing shingleSize
+// consecutive words together.
+func New(title, content string, shingleSize int) Fingerprint {
+	shingles := Shingles(Normalize(title+"\n"+content), shingleSize)
+	if len(shingles) == 0 {
+		return Fingerprint{}
+	}
+	return Fingerprint{
+		SimHash: simHash(shingles),
+		MinHash: minHash(shingles),
+	}
+}
+
+// Normalize reduces text to lowercase words separated by single spaces,
+// dropping Markdown link targets, bare URLs, punctuation and stop words.
+func Normalize(text string) string {
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = bareURL.ReplaceAllString(text, " ")
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if !stopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// Shingles returns the distinct hashes of every run of size consecutive
+// words in normalized text. A text shorter than size is a single shingle.
+func Shingles(normalized string, size int) []uint64 {
+	words := strings.Fields(normalized)
+	if len(words) == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = 1
+	}
+	if len(words) < size {
+		size = len(words)
+	}
+
+	seen := make(map[uint64]bool)
+	var shingles []uint64
+	for i := 0; i+size <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+size], " ")))
+		sum := h.Sum64()
+		if !seen[sum] {
+			seen[sum] = true
+			shingles = append(shingles, sum)
+		}
+	}
+	return shingles
+}
+
+// Empty reports whether the fingerprint was taken of a text without words.
+func (f Fingerprint) Empty() bool {
+	return len(f.MinHash) == 0
+}
+
+// Distance returns the number of bits in which the SimHashes of f and other
+// differ.
+func (f Fingerprint) Distance(other Fingerprint) int {
+	return bits.OnesCount64(f.SimHash ^ other.SimHash)
+}
+
+// Similarity estimates the Jaccard similarity of the shingle sets behind f
+// and other, from 0 for unrelated texts to 1 for the same text.
+func (f Fingerprint) Similarity(other Fingerprint) float64 {
+	if f.Empty() || len(f.MinHash) != len(other.MinHash) {
+		return 0
+	}
+	same := 0
+	for i := range f.MinHash {
+		if f.MinHash[i] == other.MinHash[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(f.MinHash))
+}
+
+// MinHashBytes encodes the MinHash signature for storage.
+func (f Fingerprint) MinHashBytes() []byte {
+	b := make([]byte, 4*len(f.MinHash))
+	for i, v := range f.MinHash {
+		binary.LittleEndian.PutUint32(b[4*i:], v)
+	}
+	return b
+}
+
+// DecodeMinHash decodes a signature encoded by MinHashBytes.
+func DecodeMinHash(b []byte) []uint32 {
+	if len(b) == 0 {
+		return nil
+	}
+	signature := make([]uint32, len(b)/4)
+	for i := range signature {
+		signature[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return signature
+}
+
+func simHash(shingles []uint64) uint64 {
+	var weights [64]int
+	for _, shingle := range shingles {
+		for bit := 0; bit < 64; bit++ {
+			if shingle&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash
+}
+
+// minHash keeps, for each of MinHashSize seeded hash functions, the smallest
+// value any shingle hashes to. The seeds are fixed so that signatures stay
+// comparable across runs.
+func minHash(shingles []uint64) []uint32 {
+	signature := make([]uint32, MinHashSize)
+	for i := range signature {
+		seed := mix(uint64(i+1) * 0x9e3779b97f4a7c15)
+		min := ^uint64(0)
+		for _, shingle := range shingles {
+			if h := mix(shingle ^ seed); h < min {
+				min = h
+			}
+		}
+		signature[i] = uint32(min >> 32)
+	}
+	return signature
+}
+
+// mix is the SplitMix64 finalizer, used as a cheap family of hash functions.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,61 @@
+package story
+
+import (
+	"testing"
+
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	launchReport  = "OpenAI today released GPT-5, its newest large language model, to all ChatGPT users. The company says the model is faster, makes fewer factual errors and is much better at writing code than GPT-4o. Developers can use it through the API starting this week at a lower price per token."
+	launchRewrite = "OpenAI today released GPT-5, its newest large language model, to all ChatGPT users. The company says the model is faster, makes fewer factual errors and is much better at writing code than GPT-4o. Developers can use it through the API starting next week at a lower price per token."
+	otherStory    = "Google DeepMind published a paper describing a protein folding system that predicts how drug molecules bind to their targets, which researchers say could shorten early stages of drug discovery by months."
+)
+
+func TestNormalize(t *testing.T) {
+	got := Normalize("The **GPT-5** launch: see [the post](https://openai.com/gpt-5) or https://example.com/x!")
+	assert.Equal(t, "gpt 5 launch see post", got)
+}
+
+func TestShingles(t *testing.T) {
+	assert.Len(t, Shingles("one two three four", 3), 2)
+	assert.Len(t, Shingles("one two", 3), 1, "short texts are a single shingle")
+	assert.Len(t, Shingles("a a a a", 2), 1, "repeated shingles are counted once")
+	assert.Empty(t, Shingles("", 3))
+}
+
+func TestFingerprint_NearDuplicates(t *testing.T) {
+	a := New("OpenAI releases GPT-5", launchReport, 3)
+	b := New("OpenAI Releases GPT-5 to Everyone", launchRewrite, 3)
+	c := New("DeepMind unveils drug discovery model", otherStory, 3)
+
+	assert.Equal(t, 1.0, a.Similarity(a))
+	assert.Equal(t, 0, a.Distance(a))
+
+	assert.Greater(t, a.Similarity(b), 0.5)
+	assert.Less(t, a.Similarity(c), 0.2)
+	assert.Less(t, a.Distance(b), a.Distance(c))
+}
+
+func TestFingerprint_Empty(t *testing.T) {
+	empty := New("", "!!!", 3)
+	assert.True(t, empty.Empty())
+	assert.False(t, Match(empty, empty, config.StoryConfig{}))
+}
+
+func TestMinHashBytesRoundTrip(t *testing.T) {
+	fp := New("title", launchReport, 3)
+	assert.Equal(t, fp.MinHash, DecodeMinHash(fp.MinHashBytes()))
+	assert.Len(t, fp.MinHashBytes(), 4*MinHashSize)
+}
+
+func TestMatch_Thresholds(t *testing.T) {
+	a := New("OpenAI releases GPT-5", launchReport, 3)
+	b := New("OpenAI Releases GPT-5 to Everyone", launchRewrite, 3)
+
+	assert.True(t, Match(a, b, config.StoryConfig{}))
+	assert.False(t, Match(a, b, config.StoryConfig{MinSimilarity: 0.99, MaxDistance: -1}))
+	assert.True(t, Match(a, a, config.StoryConfig{MinSimilarity: -1, MaxDistance: 0}))
+	assert.False(t, Match(a, b, config.StoryConfig{MinSimilarity: -1, MaxDistance: -1}))
+}
@@ -9,17 +9,20 @@ type ProgressMsg struct {
 }
 
 type CompletedMsg struct {
-	Source string
-	Added  int
-	Error  error
+	Source    string
+	Added     int
+	Unchanged bool
+	Error     error
 }
 
 type FinalSummaryMsg struct {
-	TotalAdded   int
-	TotalSources int
-	SuccessCount int
-	ErrorCount   int
-	Errors       []error
+	TotalAdded     int
+	TotalSources   int
+	SuccessCount   int
+	UnchangedCount int
+	ErrorCount     int
+	Errors         []error
+	AIUsage        string // summary of the AI calls made, if any
 }
 
 type ArticleProgressMsg struct {
@@ -21,22 +21,40 @@ type SourceProgress struct {
 	Error        error
 	Progress     progress.Model
 	Complete     bool
+	Unchanged    bool
+}
+
+// Labels names what the model shows progress of. The zero value labels a
+// fetch.
+type Labels struct {
+	Title     string // heading while running, e.g. "Fetching Articles"
+	DoneTitle string // heading once complete, e.g. "Fetch Complete"
+	Added     string // what happened to counted articles, e.g. "added"
+}
+
+var fetchLabels = Labels{
+	Title:     "Fetching Articles",
+	DoneTitle: "Fetch Complete",
+	Added:     "added",
 }
 
 type Model struct {
-	sources      map[string]*SourceProgress
-	sourceOrder  []string
-	spinner      spinner.Model
-	totalAdded   int
-	totalSources int
-	successCount int
-	errorCount   int
-	errors       []error
-	showErrors   bool
-	complete     bool
-	width        int
-	height       int
-	workerCount  int
+	labels         Labels
+	sources        map[string]*SourceProgress
+	sourceOrder    []string
+	spinner        spinner.Model
+	totalAdded     int
+	totalSources   int
+	successCount   int
+	unchangedCount int
+	errorCount     int
+	errors         []error
+	aiUsage        string
+	showErrors     bool
+	complete       bool
+	width          int
+	height         int
+	workerCount    int
 }
 
 func New(sourceNames []string) Model {
@@ -55,6 +73,7 @@ func New(sourceNames []string) Model {
 	}
 
 	return Model{
+		labels:       fetchLabels,
 		sources:      sources,
 		sourceOrder:  sourceNames,
 		spinner:      s,
@@ -63,6 +82,20 @@ func New(sourceNames []string) Model {
 	}
 }
 
+// SetLabels replaces the fetch wording of the model, for commands that
+// reuse it to show progress through other work. Empty labels are kept.
+func (m *Model) SetLabels(labels Labels) {
+	if labels.Title != "" {
+		m.labels.Title = labels.Title
+	}
+	if labels.DoneTitle != "" {
+		m.labels.DoneTitle = labels.DoneTitle
+	}
+	if labels.Added != "" {
+		m.labels.Added = labels.Added
+	}
+}
+
 func (m *Model) SetWorkerCount(count int) {
 	m.workerCount = count
 }
@@ -130,6 +163,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					source.Status = "AI analyzing..."
 				}
+			case tui.PhaseUnchanged:
+				source.Complete = true
+				source.Unchanged = true
+				source.Status = "Unchanged since last fetch"
 			case tui.PhaseDone:
 				source.Complete = true
 				source.Status = "Complete"
@@ -148,10 +185,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			source.Complete = true
 			source.Error = msg.Error
 
-			if msg.Error != nil {
+			switch {
+			case msg.Error != nil:
 				m.errorCount++
 				m.errors = append(m.errors, fmt.Errorf("%s: %w", msg.Source, msg.Error))
-			} else {
+			case msg.Unchanged:
+				source.Unchanged = true
+				m.unchangedCount++
+			default:
 				m.successCount++
 			}
 		}
@@ -161,8 +202,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.totalAdded = msg.TotalAdded
 		m.totalSources = msg.TotalSources
 		m.successCount = msg.SuccessCount
+		m.unchangedCount = msg.UnchangedCount
 		m.errorCount = msg.ErrorCount
 		m.errors = msg.Errors
+		m.aiUsage = msg.AIUsage
 	}
 
 	return m, tea.Batch(cmds...)
@@ -175,7 +218,7 @@ func (m Model) View() string {
 
 	var b strings.Builder
 
-	title := tui.TitleStyle.Render("Fetching Articles")
+	title := tui.TitleStyle.Render(m.labels.Title)
 	b.WriteString(fmt.Sprintf("┌─ %s %s\n", title, strings.Repeat("─", max(0, m.width-len(title)-4))))
 	b.WriteString("│\n")
 
@@ -197,8 +240,11 @@ func (m Model) View() string {
 		b.WriteString(m.renderErrors())
 	}
 
-	progress := fmt.Sprintf("Progress: %d/%d sources • %d articles added",
-		m.successCount+m.errorCount, m.totalSources, m.totalAdded)
+	progress := fmt.Sprintf("Progress: %d/%d sources • %d articles %s",
+		m.successCount+m.unchangedCount+m.errorCount, m.totalSources, m.totalAdded, m.labels.Added)
+	if m.unchangedCount > 0 {
+		progress += fmt.Sprintf(" • %d unchanged", m.unchangedCount)
+	}
 	b.WriteString(fmt.Sprintf("│ %s\n", progress))
 
 	help := "Press 'e' to toggle errors, 'q' to quit"
@@ -216,6 +262,8 @@ func (m Model) renderSource(source *SourceProgress) string {
 	if source.Complete {
 		if source.Error != nil {
 			status = tui.ErrorStyle.Render("⚠️")
+		} else if source.Unchanged {
+			status = tui.HelpStyle.Render("=")
 		} else {
 			status = tui.SuccessStyle.Render("✓")
 		}
@@ -244,6 +292,10 @@ func (m Model) renderSource(source *SourceProgress) string {
 		b.WriteString(statusLine + "\n")
 	}
 
+	if source.Unchanged && source.Complete {
+		b.WriteString(fmt.Sprintf("│   └─ %s\n", tui.HelpStyle.Render("Unchanged since last fetch")))
+	}
+
 	if source.Error != nil && source.Complete {
 		errorLine := fmt.Sprintf("│   └─ %s", tui.ErrorStyle.Render(fmt.Sprintf("Error: %v", source.Error)))
 		b.WriteString(errorLine + "\n")
@@ -267,7 +319,7 @@ func (m Model) renderErrors() string {
 func (m Model) renderComplete() string {
 	var b strings.Builder
 
-	title := "Fetch Complete"
+	title := m.labels.DoneTitle
 	if m.errorCount > 0 {
 		title = tui.WarningStyle.Render(title)
 	} else {
@@ -277,7 +329,7 @@ func (m Model) renderComplete() string {
 	b.WriteString(fmt.Sprintf("┌─ %s %s\n", title, strings.Repeat("─", max(0, m.width-len(title)-4))))
 	b.WriteString("│\n")
 
-	summary := fmt.Sprintf("Added %d articles from %d sources", m.totalAdded, m.totalSources)
+	summary := fmt.Sprintf("%s %d articles from %d sources", capitalize(m.labels.Added), m.totalAdded, m.totalSources)
 	b.WriteString(fmt.Sprintf("│ %s\n", tui.TitleStyle.Render(summary)))
 
 	if m.successCount > 0 {
@@ -285,6 +337,11 @@ func (m Model) renderComplete() string {
 		b.WriteString(fmt.Sprintf("│ %s\n", tui.SuccessStyle.Render(success)))
 	}
 
+	if m.unchangedCount > 0 {
+		unchanged := fmt.Sprintf("= %d sources unchanged since last fetch", m.unchangedCount)
+		b.WriteString(fmt.Sprintf("│ %s\n", tui.HelpStyle.Render(unchanged)))
+	}
+
 	if m.errorCount > 0 {
 		errors := fmt.Sprintf("⚠️  %d sources failed", m.errorCount)
 		b.WriteString(fmt.Sprintf("│ %s\n", tui.ErrorStyle.Render(errors)))
@@ -297,6 +354,12 @@ func (m Model) renderComplete() string {
 		}
 	}
 
+	for _, line := range strings.Split(m.aiUsage, "\n") {
+		if line != "" {
+			b.WriteString(fmt.Sprintf("│ %s\n", tui.HelpStyle.Render(line)))
+		}
+	}
+
 	b.WriteString("│\n")
 	b.WriteString(fmt.Sprintf("│ %s\n", tui.HelpStyle.Render("Press 'q' to quit")))
 	b.WriteString(fmt.Sprintf("└%s┘", strings.Repeat("─", max(0, m.width-2))))
@@ -304,6 +367,13 @@ func (m Model) renderComplete() string {
 	return b.String()
 }
 
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
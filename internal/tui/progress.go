@@ -3,10 +3,11 @@ package tui
 type Phase string
 
 const (
-	PhaseRSSFetch Phase = "rss_fetch"
-	PhaseScrape   Phase = "scrape"
-	PhaseAI       Phase = "ai"
-	PhaseDone     Phase = "done"
+	PhaseRSSFetch  Phase = "rss_fetch"
+	PhaseScrape    Phase = "scrape"
+	PhaseAI        Phase = "ai"
+	PhaseUnchanged Phase = "unchanged"
+	PhaseDone      Phase = "done"
 )
 
 type DetailedProgressMsg struct {
@@ -21,6 +21,8 @@ type ArticleItem struct {
 	URL     string
 	IsRead  bool
 	Content string
+	Author  string
+	Image   string // URL of the lead image
 }
 
 type FilterMode int
@@ -419,6 +421,11 @@ func (m Model) renderPreview(width int) string {
 	// Title
 	b.WriteString(lipgloss.NewStyle().Bold(true).Render(article.Title) + "\n\n")
 
+	// Author
+	if article.Author != "" {
+		b.WriteString("By " + article.Author + "\n\n")
+	}
+
 	// Status
 	status := "Unread"
 	if article.IsRead {
@@ -441,6 +448,11 @@ func (m Model) renderPreview(width int) string {
 		b.WriteString("URL: " + article.URL + "\n\n")
 	}
 
+	// Lead image; terminals cannot show it inline, so link to it instead
+	if article.Image != "" {
+		b.WriteString("Image: " + article.Image + "\n\n")
+	}
+
 	// Help text
 	b.WriteString(helpStyle.Render("Enter to mark as read • Space to open in browser • V to view in terminal"))
 
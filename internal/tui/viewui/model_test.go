@@ -59,3 +59,20 @@ func TestModel_ShowsArticleTitlesInView(t *testing.T) {
 	// The second article should be in the list but might be truncated
 	assert.Contains(t, view, "Sec") // Partial match for "Second"
 }
+
+func TestModel_PreviewShowsAuthorAndImage(t *testing.T) {
+	articles := []ArticleItem{
+		{ID: 1, Title: "Episode 12", Source: "Podcast", Author: "Jane Doe", Image: "https://example.com/ep12.jpg"},
+		{ID: 2, Title: "Untitled", Source: "Podcast"},
+	}
+
+	model := New(articles)
+	preview := model.renderPreview(200)
+	assert.Contains(t, preview, "By Jane Doe")
+	assert.Contains(t, preview, "Image: https://example.com/ep12.jpg")
+
+	model.selectedIndex = 1
+	preview = model.renderPreview(200)
+	assert.NotContains(t, preview, "By ")
+	assert.NotContains(t, preview, "Image:")
+}
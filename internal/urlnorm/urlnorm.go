@@ -0,0 +1,209 @@
+// Package urlnorm canonicalizes article URLs so that the same post reached
+// through different links is stored once.
+//
+// Canonical rewrites a URL into the form worth storing: the host is
+// lowercased, default ports and fragments are dropped, tracking parameters
+// such as utm_source are removed and AMP variants point back at the regular
+// page. Key goes further, ignoring differences that rarely distinguish two
+// pages (http vs https, a leading "www.", a trailing slash, the order of
+// query parameters), and is what duplicates are detected by.
+package urlnorm
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultStripParams are the query parameters removed from every URL. A
+// trailing "*" matches any parameter with that prefix. Names are compared
+// case-insensitively.
+var DefaultStripParams = []string{
+	"utm_*",
+	"fbclid", "gclid", "gclsrc", "dclid", "gbraid", "wbraid", "msclkid", "yclid", "twclid", "igshid",
+	"mc_cid", "mc_eid",
+	"_hsenc", "_hsmi", "__hssc", "__hstc", "__hsfp", "hsctatracking",
+	"mkt_tok", "oly_anon_id", "oly_enc_id", "vero_id", "vero_conv", "_ga", "_gl",
+	"ref_src", "ref_url",
+	"amp",
+}
+
+// Normalizer canonicalizes URLs with a fixed set of parameter rules.
+type Normalizer struct {
+	strip []string
+}
+
+// New returns a Normalizer that removes DefaultStripParams plus any extra
+// parameter rules, which use the same syntax.
+func New(extraStripParams ...string) *Normalizer {
+	n := &Normalizer{}
+	for _, rule := range append(append([]string{}, DefaultStripParams...), extraStripParams...) {
+		if rule = strings.ToLower(strings.TrimSpace(rule)); rule != "" {
+			n.strip = append(n.strip, rule)
+		}
+	}
+	return n
+}
+
+var defaultNormalizer = New()
+
+// Canonical canonicalizes rawURL with the default rules; see
+// Normalizer.Canonical.
+func Canonical(rawURL string) string {
+	return defaultNormalizer.Canonical(rawURL)
+}
+
+// Key returns the duplicate-detection key of rawURL with the default rules;
+// see Normalizer.Key.
+func Key(rawURL string) string {
+	return defaultNormalizer.Key(rawURL)
+}
+
+// Canonical returns rawURL with its host lowercased, default port, fragment
+// and tracking parameters removed, and AMP variants rewritten to the regular
+// page. The remaining query parameters keep their order. Anything that is
+// not an absolute http or https URL is returned trimmed but otherwise
+// unchanged.
+func (n *Normalizer) Canonical(rawURL string) string {
+	u, ok := n.parse(rawURL)
+	if !ok {
+		return strings.TrimSpace(rawURL)
+	}
+	return u.String()
+}
+
+// Key returns the form of rawURL that duplicates are detected by: its
+// canonical form with the scheme forced to https, a leading "www." removed,
+// trailing slashes trimmed and query parameters sorted. Keys are meant to be
+// compared, not followed.
+func (n *Normalizer) Key(rawURL string) string {
+	u, ok := n.parse(rawURL)
+	if !ok {
+		return strings.TrimSpace(rawURL)
+	}
+
+	u.Scheme = "https"
+	u.Host = strings.TrimPrefix(u.Host, "www.")
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	if u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		sort.Strings(params)
+		u.RawQuery = strings.Join(params, "&")
+	}
+	return u.String()
+}
+
+func (n *Normalizer) parse(rawURL string) (*url.URL, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, false
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = u.Hostname()
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.User = nil
+
+	unwrapAMPCache(u)
+	stripAMPPath(u)
+	u.RawQuery = n.filterQuery(u.RawQuery)
+	u.ForceQuery = false
+	return u, true
+}
+
+// filterQuery removes tracking parameters from a raw query string, keeping
+// the others in order and exactly as they were encoded.
+func (n *Normalizer) filterQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	var kept []string
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		name = strings.ToLower(name)
+		if n.stripped(name) || (name == "outputtype" && strings.EqualFold(value, "amp")) {
+			continue
+		}
+		kept = append(kept, param)
+	}
+	return strings.Join(kept, "&")
+}
+
+func (n *Normalizer) stripped(name string) bool {
+	for _, rule := range n.strip {
+		if prefix, ok := strings.CutSuffix(rule, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// unwrapAMPCache turns a Google AMP cache URL such as
+// https://example-com.cdn.ampproject.org/c/s/example.com/post into the URL of
+// the page it caches.
+func unwrapAMPCache(u *url.URL) {
+	if !strings.HasSuffix(u.Hostname(), ".cdn.ampproject.org") {
+		return
+	}
+
+	rest, ok := strings.CutPrefix(u.Path, "/c/")
+	if !ok {
+		rest, ok = strings.CutPrefix(u.Path, "/v/")
+	}
+	if !ok {
+		return
+	}
+
+	scheme := "http"
+	if after, ok := strings.CutPrefix(rest, "s/"); ok {
+		scheme, rest = "https", after
+	}
+	host, p, _ := strings.Cut(rest, "/")
+	if host == "" {
+		return
+	}
+	u.Scheme, u.Host, u.Path, u.RawPath = scheme, strings.ToLower(host), "/"+p, ""
+}
+
+// stripAMPPath removes the AMP markers publishers add to the path of the
+// regular page: a trailing /amp segment or an .amp extension.
+func stripAMPPath(u *url.URL) {
+	p := u.Path
+	trailingSlash := strings.HasSuffix(p, "/") && p != "/"
+	trimmed := strings.TrimSuffix(p, "/")
+
+	switch {
+	case path.Base(trimmed) == "amp" && trimmed != "/amp":
+		p = strings.TrimSuffix(trimmed, "amp")
+	case strings.HasSuffix(trimmed, ".amp.html"):
+		p = strings.TrimSuffix(trimmed, ".amp.html") + ".html"
+	case strings.HasSuffix(trimmed, ".amp"):
+		p = strings.TrimSuffix(trimmed, ".amp")
+	default:
+		return
+	}
+	if trailingSlash && !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	u.Path, u.RawPath = p, ""
+}
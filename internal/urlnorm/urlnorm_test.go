@@ -0,0 +1,56 @@
+package urlnorm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"lowercases host", "https://Example.COM/Post", "https://example.com/Post"},
+		{"drops fragment and default port", "https://example.com:443/post#comments", "https://example.com/post"},
+		{"keeps other ports", "http://example.com:8080/post", "http://example.com:8080/post"},
+		{"strips tracking params", "https://example.com/post?utm_source=rss&id=7&utm_medium=feed&fbclid=abc", "https://example.com/post?id=7"},
+		{"strips prefix rules case-insensitively", "https://example.com/post?UTM_Campaign=x", "https://example.com/post"},
+		{"keeps parameter order and encoding", "https://example.com/s?q=a+b&lang=en", "https://example.com/s?q=a+b&lang=en"},
+		{"amp path segment", "https://example.com/2025/08/post/amp/", "https://example.com/2025/08/post/"},
+		{"amp extension", "https://example.com/news/post.amp.html", "https://example.com/news/post.html"},
+		{"amp query", "https://example.com/post?amp=1&outputType=amp", "https://example.com/post"},
+		{"amp cache", "https://example-com.cdn.ampproject.org/c/s/example.com/post", "https://example.com/post"},
+		{"keeps a root amp page", "https://example.com/amp", "https://example.com/amp"},
+		{"leaves other schemes alone", " mailto:someone@example.com ", "mailto:someone@example.com"},
+		{"leaves relative URLs alone", "/post", "/post"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Canonical(tt.in))
+		})
+	}
+}
+
+func TestKey_MatchesVariants(t *testing.T) {
+	want := Key("https://example.com/post?a=1&b=2")
+	for _, variant := range []string{
+		"http://example.com/post?a=1&b=2",
+		"https://www.example.com/post?a=1&b=2",
+		"https://example.com/post/?b=2&a=1",
+		"https://EXAMPLE.com/post?a=1&b=2&utm_source=rss#top",
+		"https://example.com/post/amp?a=1&b=2",
+	} {
+		assert.Equal(t, want, Key(variant), variant)
+	}
+
+	assert.NotEqual(t, want, Key("https://example.com/post?a=1"))
+	assert.NotEqual(t, Key("https://example.com/Post"), Key("https://example.com/post"), "paths are case-sensitive")
+}
+
+func TestNew_ExtraStripParams(t *testing.T) {
+	n := New("ref", "share_*")
+	assert.Equal(t, "https://example.com/post?page=2", n.Canonical("https://example.com/post?ref=hn&share_id=1&page=2"))
+	assert.Equal(t, "https://example.com/post?ref=hn", Canonical("https://example.com/post?ref=hn"), "the default rules keep ref")
+}
@@ -0,0 +1,248 @@
+// Package usage accounts for the tokens that AI calls use and what they
+// cost, and enforces the monthly budget from the config.
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/robertguss/rss-agent-cli/pkg/errs"
+	"github.com/robertguss/rss-agent-cli/pkg/logging"
+)
+
+// ErrBudgetExceeded is returned instead of analyzing an article once the
+// monthly budget is spent.
+var ErrBudgetExceeded = errors.New("monthly AI budget exceeded")
+
+// Ways to group usage in Summarize.
+const (
+	ByDay    = "day"
+	ByWeek   = "week"
+	BySource = "source"
+)
+
+// Totals adds up a number of AI calls.
+type Totals struct {
+	Calls        int
+	PromptTokens int64
+	OutputTokens int64
+	Cost         float64 // in US dollars, for the calls to models with a price
+}
+
+func (t *Totals) add(promptTokens, outputTokens int64, cost float64) {
+	t.Calls++
+	t.PromptTokens += promptTokens
+	t.OutputTokens += outputTokens
+	t.Cost += cost
+}
+
+// Cost returns what a call costs under prices, and whether model has a price.
+func Cost(prices []config.ModelPrice, model string, promptTokens, outputTokens int64) (float64, bool) {
+	for _, price := range prices {
+		if strings.EqualFold(price.Model, model) {
+			return (float64(promptTokens)*price.Input + float64(outputTokens)*price.Output) / 1e6, true
+		}
+	}
+	return 0, false
+}
+
+// MonthStart returns the start of the calendar month of t, in t's location.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// Tracker records the AI calls of one run and enforces the monthly budget.
+// What the month has cost is read from the database whenever the budget is
+// checked, so the calls of other processes sharing the database count too,
+// and a long-running worker starts afresh when the month changes. Processes
+// checking at the same time can still overshoot the budget by the calls
+// they have in flight. A nil *Tracker records nothing and has no budget. It
+// is safe for concurrent use.
+type Tracker struct {
+	queries *database.Queries
+	cfg     config.UsageConfig
+	runID   string
+	now     func() time.Time
+
+	mu         sync.Mutex
+	monthStart time.Time
+	month      float64
+	run        Totals
+}
+
+// NewTracker starts a run, reading what the month has cost so far.
+func NewTracker(ctx context.Context, queries *database.Queries, cfg config.UsageConfig) (*Tracker, error) {
+	now := time.Now()
+	t := &Tracker{
+		queries: queries,
+		cfg:     cfg,
+		runID:   fmt.Sprintf("%s-%04x", now.UTC().Format("20060102T150405"), rand.IntN(0x10000)),
+		now:     time.Now,
+	}
+	if err := t.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// refresh reads what the current month has cost from the database.
+func (t *Tracker) refresh(ctx context.Context) error {
+	start := MonthStart(t.now())
+	rows, err := t.queries.SumAIUsageByModelSince(ctx, start.Unix())
+	if err != nil {
+		return errs.Wrap("sum AI usage", err)
+	}
+	var month float64
+	for _, row := range rows {
+		cost, _ := Cost(t.cfg.Prices, row.Model, row.PromptTokens, row.OutputTokens)
+		month += cost
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.monthStart = start
+	t.month = month
+	return nil
+}
+
+// RunID identifies the run in the ai_usage table.
+func (t *Tracker) RunID() string {
+	if t == nil {
+		return ""
+	}
+	return t.runID
+}
+
+// OverBudget reports whether the current month's calls, by any process,
+// have cost at least the monthly budget. If the database cannot be read,
+// the last reading and the calls recorded since are used instead.
+func (t *Tracker) OverBudget(ctx context.Context) bool {
+	if t == nil || t.cfg.MonthlyBudget <= 0 {
+		return false
+	}
+	if err := t.refresh(ctx); err != nil {
+		logging.Warn("usage_budget", fmt.Sprintf("Checking the budget against the last reading: %v", err))
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if MonthStart(t.now()).After(t.monthStart) {
+		return false
+	}
+	return t.month >= t.cfg.MonthlyBudget
+}
+
+// Run returns the totals of the calls recorded in this run.
+func (t *Tracker) Run() Totals {
+	if t == nil {
+		return Totals{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.run
+}
+
+// Record stores calls made for an article of source; an articleID of zero
+// means the article was not stored.
+func (t *Tracker) Record(ctx context.Context, articleID int64, source string, calls []processor.Usage) error {
+	if t == nil {
+		return nil
+	}
+	now := t.now().Unix()
+	for _, call := range calls {
+		err := t.queries.CreateAIUsage(ctx, database.CreateAIUsageParams{
+			RunID:        t.runID,
+			ArticleID:    sql.NullInt64{Int64: articleID, Valid: articleID != 0},
+			SourceName:   source,
+			Provider:     call.Provider,
+			Model:        call.Model,
+			PromptTokens: call.PromptTokens,
+			OutputTokens: call.OutputTokens,
+			LatencyMs:    call.Latency.Milliseconds(),
+			CreatedAt:    now,
+		})
+		if err != nil {
+			return errs.Wrap("record AI usage", err)
+		}
+
+		cost, _ := Cost(t.cfg.Prices, call.Model, call.PromptTokens, call.OutputTokens)
+		t.mu.Lock()
+		t.month += cost
+		t.run.add(call.PromptTokens, call.OutputTokens, cost)
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+// Row is the usage of one period or source in a Summary.
+type Row struct {
+	Key string
+	Totals
+}
+
+// Summary is usage grouped by period or source.
+type Summary struct {
+	Rows     []Row
+	Total    Totals
+	Unpriced []string // models used without a price in the config
+}
+
+// Summarize groups usage rows by day, ISO week or source, in time zone loc.
+// Periods are in chronological order and sources by descending cost.
+func Summarize(rows []database.ListAIUsageSinceRow, prices []config.ModelPrice, by string, loc *time.Location) Summary {
+	var summary Summary
+	groups := make(map[string]*Totals)
+	var keys []string
+	unpriced := make(map[string]bool)
+
+	for _, row := range rows {
+		var key string
+		switch by {
+		case ByWeek:
+			year, week := time.Unix(row.CreatedAt, 0).In(loc).ISOWeek()
+			key = fmt.Sprintf("%d-W%02d", year, week)
+		case BySource:
+			key = row.SourceName
+			if key == "" {
+				key = "(none)"
+			}
+		default:
+			key = time.Unix(row.CreatedAt, 0).In(loc).Format(time.DateOnly)
+		}
+
+		cost, priced := Cost(prices, row.Model, row.PromptTokens, row.OutputTokens)
+		if !priced && !unpriced[row.Model] {
+			unpriced[row.Model] = true
+			summary.Unpriced = append(summary.Unpriced, row.Model)
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &Totals{}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.add(row.PromptTokens, row.OutputTokens, cost)
+		summary.Total.add(row.PromptTokens, row.OutputTokens, cost)
+	}
+
+	for _, key := range keys {
+		summary.Rows = append(summary.Rows, Row{Key: key, Totals: *groups[key]})
+	}
+	if by == BySource {
+		sort.SliceStable(summary.Rows, func(i, j int) bool {
+			return summary.Rows[i].Cost > summary.Rows[j].Cost
+		})
+	}
+	sort.Strings(summary.Unpriced)
+	return summary
+}
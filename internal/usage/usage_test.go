@@ -0,0 +1,150 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/robertguss/rss-agent-cli/internal/ai/processor"
+	"github.com/robertguss/rss-agent-cli/internal/config"
+	"github.com/robertguss/rss-agent-cli/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+var testPrices = []config.ModelPrice{
+	{Model: "gemini-2.5-flash", Input: 0.30, Output: 2.50},
+	{Model: "gpt-4o-mini", Input: 0.15, Output: 0.60},
+}
+
+func setupTestDB(t *testing.T) *database.Queries {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, database.InitSchema(db))
+	return database.New(db)
+}
+
+func TestCost(t *testing.T) {
+	cost, ok := Cost(testPrices, "GPT-4o-mini", 2_000_000, 1_000_000)
+	assert.True(t, ok)
+	assert.InDelta(t, 0.90, cost, 1e-9)
+
+	cost, ok = Cost(testPrices, "llama3", 1000, 1000)
+	assert.False(t, ok)
+	assert.Zero(t, cost)
+}
+
+func TestTracker_RecordsUsageAndEnforcesBudget(t *testing.T) {
+	ctx := context.Background()
+	queries := setupTestDB(t)
+
+	// Spent last month, which does not count against this month's budget.
+	require.NoError(t, queries.CreateAIUsage(ctx, database.CreateAIUsageParams{
+		RunID:        "old",
+		Model:        "gemini-2.5-flash",
+		PromptTokens: 100_000_000,
+		CreatedAt:    MonthStart(time.Now()).Add(-time.Hour).Unix(),
+	}))
+
+	tracker, err := NewTracker(ctx, queries, config.UsageConfig{Prices: testPrices, MonthlyBudget: 1})
+	require.NoError(t, err)
+	assert.NotEmpty(t, tracker.RunID())
+	assert.False(t, tracker.OverBudget(ctx))
+
+	err = tracker.Record(ctx, 7, "Blog", []processor.Usage{
+		{Provider: "gemini", Model: "gemini-2.5-flash", PromptTokens: 1_000_000, OutputTokens: 100_000, Latency: 1500 * time.Millisecond},
+		{Provider: "gemini", Model: "gemini-2.5-flash", PromptTokens: 1_000_000, OutputTokens: 100_000},
+	})
+	require.NoError(t, err)
+
+	run := tracker.Run()
+	assert.Equal(t, 2, run.Calls)
+	assert.Equal(t, int64(2_000_000), run.PromptTokens)
+	assert.InDelta(t, 1.10, run.Cost, 1e-9)
+	assert.True(t, tracker.OverBudget(ctx))
+
+	rows, err := queries.ListAIUsageSince(ctx, MonthStart(time.Now()).Unix())
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "Blog", rows[0].SourceName)
+	assert.Equal(t, int64(1500), rows[0].LatencyMs)
+
+	// A new run picks up what the month has cost so far.
+	next, err := NewTracker(ctx, queries, config.UsageConfig{Prices: testPrices, MonthlyBudget: 1})
+	require.NoError(t, err)
+	assert.True(t, next.OverBudget(ctx))
+	assert.NotEqual(t, tracker.RunID(), next.RunID())
+}
+
+func TestTracker_NilAndWithoutBudget(t *testing.T) {
+	ctx := context.Background()
+	var nilTracker *Tracker
+	assert.False(t, nilTracker.OverBudget(ctx))
+	assert.NoError(t, nilTracker.Record(ctx, 1, "Blog", []processor.Usage{{Model: "m"}}))
+	assert.Zero(t, nilTracker.Run())
+
+	tracker, err := NewTracker(ctx, setupTestDB(t), config.UsageConfig{Prices: testPrices})
+	require.NoError(t, err)
+	require.NoError(t, tracker.Record(ctx, 0, "", []processor.Usage{{Model: "gpt-4o-mini", PromptTokens: 1e9}}))
+	assert.False(t, tracker.OverBudget(ctx))
+}
+
+func TestTracker_BudgetSeesOtherRunsAndNewMonth(t *testing.T) {
+	ctx := context.Background()
+	queries := setupTestDB(t)
+	cfg := config.UsageConfig{Prices: testPrices, MonthlyBudget: 1}
+
+	clock := time.Now()
+	tracker, err := NewTracker(ctx, queries, cfg)
+	require.NoError(t, err)
+	tracker.now = func() time.Time { return clock }
+	assert.False(t, tracker.OverBudget(ctx))
+
+	// Another process spends the budget.
+	other, err := NewTracker(ctx, queries, cfg)
+	require.NoError(t, err)
+	require.NoError(t, other.Record(ctx, 1, "Blog", []processor.Usage{
+		{Provider: "gemini", Model: "gemini-2.5-flash", PromptTokens: 4_000_000},
+	}))
+	assert.True(t, tracker.OverBudget(ctx))
+
+	// The budget is fresh again next month.
+	clock = MonthStart(clock).AddDate(0, 1, 0)
+	assert.False(t, tracker.OverBudget(ctx))
+}
+
+func TestSummarize(t *testing.T) {
+	loc := time.UTC
+	monday := time.Date(2026, 3, 2, 12, 0, 0, 0, loc)
+	rows := []database.ListAIUsageSinceRow{
+		{SourceName: "Blog", Model: "gemini-2.5-flash", PromptTokens: 1_000_000, OutputTokens: 0, CreatedAt: monday.Unix()},
+		{SourceName: "Papers", Model: "gpt-4o-mini", PromptTokens: 1_000_000, OutputTokens: 1_000_000, CreatedAt: monday.Add(24 * time.Hour).Unix()},
+		{SourceName: "Blog", Model: "llama3", PromptTokens: 500, OutputTokens: 50, CreatedAt: monday.Add(7 * 24 * time.Hour).Unix()},
+	}
+
+	byDay := Summarize(rows, testPrices, ByDay, loc)
+	require.Len(t, byDay.Rows, 3)
+	assert.Equal(t, "2026-03-02", byDay.Rows[0].Key)
+	assert.Equal(t, "2026-03-09", byDay.Rows[2].Key)
+	assert.Equal(t, 3, byDay.Total.Calls)
+	assert.InDelta(t, 1.05, byDay.Total.Cost, 1e-9)
+	assert.Equal(t, []string{"llama3"}, byDay.Unpriced)
+
+	byWeek := Summarize(rows, testPrices, ByWeek, loc)
+	require.Len(t, byWeek.Rows, 2)
+	assert.Equal(t, "2026-W10", byWeek.Rows[0].Key)
+	assert.Equal(t, 2, byWeek.Rows[0].Calls)
+	assert.Equal(t, "2026-W11", byWeek.Rows[1].Key)
+
+	bySource := Summarize(rows, testPrices, BySource, loc)
+	require.Len(t, bySource.Rows, 2)
+	assert.Equal(t, "Papers", bySource.Rows[0].Key)
+	assert.InDelta(t, 0.75, bySource.Rows[0].Cost, 1e-9)
+	assert.Equal(t, "Blog", bySource.Rows[1].Key)
+	assert.Equal(t, int64(1_000_500), bySource.Rows[1].PromptTokens)
+}
@@ -186,6 +186,7 @@ func IsAI(err error) bool {
 	errStr := strings.ToLower(err.Error())
 	aiKeywords := []string{
 		"gemini",
+		"openai",
 		"api key",
 		"api_key",
 		"quota",
@@ -288,6 +289,20 @@ func IsRateLimit(err error) bool {
 		strings.Contains(errStr, "too many requests")
 }
 
+// IsQuota reports whether err says that a usage quota or rate limit was hit,
+// which waiting a little may not fix.
+func IsQuota(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	errStr := strings.ToLower(err.Error())
+	return IsRateLimit(err) ||
+		strings.Contains(errStr, "quota") ||
+		strings.Contains(errStr, "resource has been exhausted") ||
+		strings.Contains(errStr, "resource_exhausted")
+}
+
 func IsRetryable(err error) bool {
 	var appErr *AppError
 	if errors.As(err, &appErr) {